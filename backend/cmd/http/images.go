@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mattismoel/konnekt/internal/service"
+)
+
+var ErrInvalidImagesCommand = errors.New("Usage: images backfill")
+
+// Runs the images subcommand given by the arguments following "images".
+func runImages(ctx context.Context, placeholderService *service.PlaceholderService, args []string) error {
+	if len(args) <= 0 {
+		return ErrInvalidImagesCommand
+	}
+
+	switch args[0] {
+	case "backfill":
+		updated, err := placeholderService.Backfill(ctx)
+		fmt.Printf("Computed placeholders of %d images\n", updated)
+
+		return err
+	default:
+		return ErrInvalidImagesCommand
+	}
+}
@@ -2,23 +2,44 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"flag"
 	"log"
 	"log/slog"
 	"net"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	// Embeds the time zone database, as recurring events are generated in
+	// their IANA time zone.
+	_ "time/tzdata"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+	"github.com/mattismoel/konnekt/internal/mailer"
+	"github.com/mattismoel/konnekt/internal/mailer/file"
+	"github.com/mattismoel/konnekt/internal/mailer/smtp"
+	"github.com/mattismoel/konnekt/internal/object"
+	"github.com/mattismoel/konnekt/internal/object/local"
 	"github.com/mattismoel/konnekt/internal/object/s3"
 	"github.com/mattismoel/konnekt/internal/server"
 	"github.com/mattismoel/konnekt/internal/service"
+	"github.com/mattismoel/konnekt/internal/sitemap"
 	"github.com/mattismoel/konnekt/internal/storage/sqlite"
+	"github.com/mattismoel/konnekt/internal/upload"
 	_ "modernc.org/sqlite"
 )
 
 const (
 	MAX_STARTUP_DURATION = 10 * time.Second
+
+	// The interval at which scheduled publishing of events is applied.
+	EVENT_SCHEDULER_INTERVAL = time.Minute
+
+	// The interval at which expired sessions are deleted.
+	SESSION_PRUNE_INTERVAL = time.Hour
 )
 
 func main() {
@@ -33,6 +54,34 @@ func main() {
 	port := flag.Int("port", 8080, "The port of the web server")
 	s3Region := flag.String("s3Region", "eu-north-1", "The region of the S3 bucket")
 	s3Bucket := flag.String("s3Bucket", "konnekt-bucket", "The bucket name of the S3 bucket")
+	s3Endpoint := flag.String("s3Endpoint", "", "The endpoint of an S3-compatible provider, such as MinIO. Defaults to AWS")
+	s3PathStyle := flag.Bool("s3PathStyle", false, "Whether or not to address the S3 bucket by path rather than by subdomain")
+	s3AccessKey := flag.String("s3AccessKey", os.Getenv("S3_ACCESS_KEY"), "The access key ID of the S3 provider. Defaults to the credentials of the environment")
+	s3SecretKey := flag.String("s3SecretKey", os.Getenv("S3_SECRET_KEY"), "The secret access key of the S3 provider")
+	s3PublicBaseURL := flag.String("s3PublicBaseURL", "", "The public base URL of S3 objects, such as a CDN domain. Defaults to the URL of the bucket")
+	objectStoreKind := flag.String("objectStore", "s3", "The object store to use, either s3 or local")
+	objectRoot := flag.String("objectRoot", "./data/objects", "The directory of objects, when using the local object store")
+	objectBaseURL := flag.String("objectBaseURL", "", "The public base URL of objects, when using the local object store. Defaults to /objects of the web server")
+	autoMigrate := flag.Bool("autoMigrate", true, "Whether or not to apply pending database migrations at startup")
+	seed := flag.Bool("seed", false, "Whether or not to seed the default teams and permissions at startup")
+	maxUploadBytes := flag.Int64("maxUploadBytes", upload.DEFAULT_MAX_BYTES, "The maximum size of image upload requests in bytes")
+	maxImagePixels := flag.Int("maxImagePixels", upload.DEFAULT_MAX_PIXELS, "The maximum width times height of uploaded images")
+	orphanGracePeriod := flag.Duration("orphanGracePeriod", service.DEFAULT_ORPHAN_GRACE_PERIOD, "The duration objects are kept before unreferenced objects are considered orphaned")
+	siteURL := flag.String("siteURL", server.DEFAULT_SITE_URL, "The public URL of the site, to which the sitemap and calendars link")
+	sitemapURL := flag.String("sitemapURL", "", "The public URL of the sitemap, to which the sitemap index links. Defaults to /api/sitemap of the site URL")
+	sitemapPageSize := flag.Int("sitemapPageSize", sitemap.MAX_URLS, "The maximum amount of URLs of a single sitemap, before the sitemap is split into an index")
+	mailerKind := flag.String("mailer", "file", "The mailer to use, either smtp or file. The file mailer writes mails to a directory instead of sending them")
+	mailDir := flag.String("mailDir", "./data/mail", "The directory of mails, when using the file mailer")
+	mailFrom := flag.String("mailFrom", "Konnekt <noreply@knnkt.dk>", "The sender address of mails")
+	smtpHost := flag.String("smtpHost", "", "The host of the SMTP server")
+	smtpPort := flag.Int("smtpPort", 587, "The port of the SMTP server")
+	smtpUsername := flag.String("smtpUsername", os.Getenv("SMTP_USERNAME"), "The username of the SMTP server. Mails are sent without authentication if empty")
+	smtpPassword := flag.String("smtpPassword", os.Getenv("SMTP_PASSWORD"), "The password of the SMTP server")
+	signingKey := flag.String("signingKey", os.Getenv("SIGNING_KEY"), "The secret key of at least 32 bytes, with which email verification links are signed. Required while registration is open and emails must be verified")
+	twoFactorTeams := flag.String("twoFactorTeams", "", "The comma-separated names of the teams, such as admin, whose members must log in with two-factor authentication")
+	openRegistration := flag.Bool("openRegistration", true, "Whether or not anyone may register. If not, members only join by invitation")
+	emailVerification := flag.Bool("emailVerification", false, "Whether or not registered members must verify their email before logging in. Requires the smtp mailer and a signing key, while registration is open")
+	orphanCollectInterval := flag.Duration("orphanCollectInterval", 0, "The interval at which orphaned objects are deleted. Orphans are only deleted on request if 0")
 
 	flag.Parse()
 
@@ -45,6 +94,37 @@ func main() {
 		log.Fatal(err)
 	}
 
+	migrator, err := sqlite.NewMigrator(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Migrations may outlast the startup timeout, so they are not bound by it.
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(context.Background(), migrator, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if *autoMigrate {
+		migrations, err := migrator.Up(context.Background())
+		for _, m := range migrations {
+			slog.Info("Applied migration", "version", m.Version, "name", m.Name)
+		}
+
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *seed {
+		if err := migrator.Seed(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	contentRepo, err := sqlite.NewContentRepository(db)
 	if err != nil {
 		log.Fatal(err)
@@ -80,27 +160,215 @@ func main() {
 		log.Fatal(err)
 	}
 
-	authService, err := service.NewAuthService(memberRepo, authRepo, teamRepo)
+	seriesRepo, err := sqlite.NewSeriesRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	searchRepo, err := sqlite.NewSearchRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mediaRepo, err := sqlite.NewMediaRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	invitationRepo, err := sqlite.NewInvitationRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var mail mailer.Mailer
+
+	switch *mailerKind {
+	case "smtp":
+		smtpCfgs := make([]smtp.CfgFunc, 0)
+
+		if *smtpUsername != "" {
+			smtpCfgs = append(smtpCfgs, smtp.WithCredentials(*smtpUsername, *smtpPassword))
+		}
+
+		mail, err = smtp.NewSMTPMailer(*smtpHost, *smtpPort, *mailFrom, smtpCfgs...)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "file":
+		mail, err = file.NewFileMailer(*mailDir, *mailFrom)
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("Unknown mailer %q", *mailerKind)
+	}
+
+	// Links sent by the file mailer never reach registrants, and links signed
+	// by a random key are invalidated on restart, so registrants would be
+	// locked out.
+	if *openRegistration && *emailVerification && (*mailerKind != "smtp" || *signingKey == "") {
+		log.Fatal("Email verification requires the smtp mailer and a signing key. Set -mailer=smtp and SIGNING_KEY, or disable either -openRegistration or -emailVerification")
+	}
+
+	key := []byte(*signingKey)
+	if len(key) == 0 {
+		slog.Warn("No signing key given. Email verification links are invalidated on restart")
+
+		key = make([]byte, auth.MINIMUM_SIGNING_KEY_LENGTH)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	verifier, err := auth.NewVerifier(key)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var objectStore object.Store
+	serverCfgs := make([]server.CfgFunc, 0)
+
+	switch *objectStoreKind {
+	case "s3":
+		s3Cfgs := []s3.CfgFunc{s3.WithPathStyle(*s3PathStyle)}
+
+		if *s3Endpoint != "" {
+			s3Cfgs = append(s3Cfgs, s3.WithEndpoint(*s3Endpoint))
+		}
+
+		if *s3AccessKey != "" || *s3SecretKey != "" {
+			s3Cfgs = append(s3Cfgs, s3.WithCredentials(*s3AccessKey, *s3SecretKey))
+		}
+
+		if *s3PublicBaseURL != "" {
+			s3Cfgs = append(s3Cfgs, s3.WithPublicBaseURL(*s3PublicBaseURL))
+		}
+
+		objectStore, err = s3.NewS3ObjectStore(*s3Region, *s3Bucket, s3Cfgs...)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "local":
+		if *objectBaseURL == "" {
+			*objectBaseURL = "http://" + net.JoinHostPort(*host, strconv.Itoa(*port)) + "/objects"
+		}
+
+		localStore, err := local.NewLocalObjectStore(*objectRoot, *objectBaseURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		objectStore = localStore
+		serverCfgs = append(serverCfgs, server.WithObjectFS(localStore.FS()))
+	default:
+		log.Fatalf("Unknown object store %q", *objectStoreKind)
+	}
+
+	imageService, err := service.NewImageService(objectStore, service.WithMediaRepository(mediaRepo))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	authService, err := service.NewAuthService(
+		memberRepo, authRepo, teamRepo,
+		service.WithMailer(mail),
+		service.WithPasswordResetURL(strings.TrimSuffix(*siteURL, "/")+"/auth/reset-password"),
+		service.WithVerifier(verifier),
+		service.WithVerificationURL(strings.TrimSuffix(*siteURL, "/")+"/auth/verify-email"),
+		service.WithEmailVerification(*emailVerification),
+		service.WithOpenRegistration(*openRegistration),
+		service.WithTwoFactorTeams(strings.Split(*twoFactorTeams, ",")...),
+		service.WithImageService(imageService),
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	invitationService, err := service.NewInvitationService(
+		invitationRepo, memberRepo, teamRepo, mail,
+		strings.TrimSuffix(*siteURL, "/")+"/auth/accept-invitation",
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Backfilling reads every stored image, and may outlast the startup
+	// timeout.
+	if flag.Arg(0) == "images" {
+		placeholderService, err := service.NewPlaceholderService(imageService, artistRepo, eventRepo, seriesRepo, memberRepo, contentRepo)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := runImages(context.Background(), placeholderService, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	memberService, err := service.NewMemberService(memberRepo, teamRepo, imageService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	eventService, err := service.NewEventService(eventRepo, artistRepo, venueRepo, seriesRepo, imageService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	seriesService, err := service.NewSeriesService(seriesRepo, eventRepo, imageService)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	s3Store, err := s3.NewS3ObjectStore(*s3Region, *s3Bucket)
+	artistService, err := service.NewArtistService(artistRepo, eventRepo, imageService)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	memberService, err := service.NewMemberService(memberRepo, teamRepo, s3Store)
+	mediaService, err := service.NewMediaService(mediaRepo, imageService)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	eventService, err := service.NewEventService(eventRepo, artistRepo, venueRepo, s3Store)
+	searchService, err := service.NewSearchService(searchRepo)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	artistService, err := service.NewArtistService(artistRepo, eventRepo, s3Store)
+	// The index is kept up to date as rows are written, so it is only rebuilt
+	// on request. Rebuilding reads every indexed row, and may outlast the
+	// startup timeout.
+	if flag.Arg(0) == "reindex" {
+		if err := searchService.Reindex(context.Background()); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	// The scheduler runs for the lifetime of the process, so it must not use
+	// the startup context.
+	go eventService.RunScheduler(context.Background(), EVENT_SCHEDULER_INTERVAL)
+	go authService.RunSessionPruner(context.Background(), SESSION_PRUNE_INTERVAL)
+
+	storageService, err := service.NewStorageService(
+		objectStore, artistRepo, eventRepo, seriesRepo, memberRepo, contentRepo, mediaRepo,
+		service.WithOrphanGracePeriod(*orphanGracePeriod),
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *orphanCollectInterval > 0 {
+		go storageService.RunCollector(context.Background(), *orphanCollectInterval)
+	}
+
+	uploadGuard, err := upload.NewGuard(upload.WithMaxBytes(*maxUploadBytes), upload.WithMaxPixels(*maxImagePixels))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -108,9 +376,14 @@ func main() {
 	venueService := service.NewVenueService(venueRepo)
 
 	teamService := service.NewTeamService(teamRepo, memberRepo, authRepo)
-	contentService := service.NewContentService(s3Store, contentRepo)
+	contentService := service.NewContentService(imageService, contentRepo)
 
-	srv, err := server.New(
+	if *sitemapURL != "" {
+		serverCfgs = append(serverCfgs, server.WithSitemapURL(*sitemapURL))
+	}
+
+	srv, err := server.New(append(
+		serverCfgs,
 		server.WithContentService(contentService),
 		server.WithTeamService(teamService),
 		server.WithAddress(net.JoinHostPort(*host, strconv.Itoa(*port))),
@@ -120,7 +393,18 @@ func main() {
 		server.WithEventService(eventService),
 		server.WithArtistService(artistService),
 		server.WithVenueService(venueService),
-	)
+		server.WithSeriesService(seriesService),
+		server.WithSearchService(searchService),
+		server.WithStorageService(storageService),
+		server.WithMediaService(mediaService),
+		server.WithInvitationService(invitationService),
+		server.WithUploadGuard(uploadGuard),
+		server.WithSiteURL(*siteURL),
+		server.WithSitemapPageSize(*sitemapPageSize),
+	)...)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	slog.Info("Started server", "host", *host, "port", *port, "origin", *origin)
 	if err := srv.Start(); err != nil {
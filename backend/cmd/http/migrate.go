@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/storage/sqlite"
+)
+
+var ErrInvalidMigrateCommand = errors.New("Usage: migrate up | down [count] | status | baseline <version> | seed")
+
+// Runs the migrate subcommand given by the arguments following "migrate".
+func runMigrate(ctx context.Context, migrator *sqlite.Migrator, args []string) error {
+	if len(args) <= 0 {
+		return ErrInvalidMigrateCommand
+	}
+
+	switch args[0] {
+	case "up":
+		migrations, err := migrator.Up(ctx)
+		for _, m := range migrations {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+
+		return err
+	case "down":
+		count := 1
+		if len(args) > 1 {
+			var err error
+			if count, err = strconv.Atoi(args[1]); err != nil {
+				return ErrInvalidMigrateCommand
+			}
+		}
+
+		migrations, err := migrator.Down(ctx, count)
+		for _, m := range migrations {
+			fmt.Printf("Reverted %04d_%s\n", m.Version, m.Name)
+		}
+
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%04d_%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+
+		return w.Flush()
+	case "baseline":
+		if len(args) < 2 {
+			return ErrInvalidMigrateCommand
+		}
+
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return ErrInvalidMigrateCommand
+		}
+
+		return migrator.Baseline(ctx, version)
+	case "seed":
+		return migrator.Seed(ctx)
+	default:
+		return ErrInvalidMigrateCommand
+	}
+}
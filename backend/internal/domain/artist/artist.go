@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
 )
 
 var (
@@ -21,13 +24,16 @@ var (
 type ArtistCfg func(a *Artist) error
 
 type Artist struct {
-	ID          int64    `json:"id"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	ImageURL    string   `json:"imageUrl"`
-	PreviewURL  string   `json:"previewUrl,omitempty"`
-	Genres      []Genre  `json:"genres"`
-	Socials     []Social `json:"socials"`
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Image       media.Image `json:"image"`
+	PreviewURL  string      `json:"previewUrl,omitempty"`
+	Genres      []Genre     `json:"genres"`
+	Socials     []Social    `json:"socials"`
+
+	// The time of the latest change to the artist.
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 func NewArtist(cfgs ...ArtistCfg) (*Artist, error) {
@@ -86,14 +92,22 @@ func WithDescription(desc string) ArtistCfg {
 	}
 }
 
-func WithImageURL(u string) ArtistCfg {
+func WithImage(img media.Image) ArtistCfg {
 	return func(a *Artist) error {
-		url, err := url.ParseRequestURI(u)
-		if err != nil {
+		if err := img.Attribution.Validate(); err != nil {
+			return err
+		}
+
+		if err := img.Validate(); err != nil {
 			return ErrInvalidImageURL
 		}
 
-		resp, err := http.Get(url.String())
+		// Artists are always shown to the public.
+		if err := img.ValidatePublic(); err != nil {
+			return err
+		}
+
+		resp, err := http.Get(img.URL)
 		if err != nil {
 			return ErrImageURLInaccessible
 		}
@@ -102,7 +116,7 @@ func WithImageURL(u string) ArtistCfg {
 			return ErrImageURLInaccessible
 		}
 
-		a.ImageURL = url.String()
+		a.Image = img
 
 		return nil
 	}
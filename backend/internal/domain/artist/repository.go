@@ -3,6 +3,7 @@ package artist
 import (
 	"context"
 
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/query"
 )
 
@@ -15,5 +16,5 @@ type Repository interface {
 	GenreByID(ctx context.Context, genreID int64) (Genre, error)
 	ListGenres(ctx context.Context, q GenreQuery) (query.ListResult[Genre], error)
 	InsertGenre(ctx context.Context, name string) (int64, error)
-	SetImageURL(ctx context.Context, artistID int64, u string) error
+	SetImage(ctx context.Context, artistID int64, img media.Image) error
 }
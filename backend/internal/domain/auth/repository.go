@@ -9,9 +9,56 @@ import (
 
 type Repository interface {
 	Session(ctx context.Context, sessionID SessionID) (Session, error)
+	MemberSessions(ctx context.Context, memberID int64) ([]Session, error)
 	InsertSession(ctx context.Context, s Session) error
-	DeleteMemberSession(ctx context.Context, memberID int64) error
+	DeleteSession(ctx context.Context, sessionID SessionID) error
+
+	// Deletes every session of the member, except for the given sessions.
+	DeleteMemberSessions(ctx context.Context, memberID int64, except ...SessionID) error
+
+	// Deletes every session expired at the given time, returning the amount of
+	// deleted sessions.
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
+
 	SetSessionExpiry(ctx context.Context, sessionID SessionID, newExpiry time.Time) error
+	SetSessionLastSeen(ctx context.Context, sessionID SessionID, lastSeen time.Time) error
+
+	// Increments the failed two-factor attempts of the session, returning the
+	// new amount of attempts.
+	AddSessionFailedAttempt(ctx context.Context, sessionID SessionID) (int, error)
+
+	// Returns ErrTwoFactorNotEnrolled if the member has not set up two-factor
+	// authentication.
+	TwoFactor(ctx context.Context, memberID int64) (TwoFactor, error)
+
+	// Inserts or replaces the two-factor authentication of the member.
+	SetTwoFactor(ctx context.Context, tf TwoFactor) error
+
+	// Records the period of a used code. Returns ErrInvalidTwoFactorCode if a
+	// code of the period or a later period has already been used.
+	UseTwoFactorPeriod(ctx context.Context, memberID int64, period int64) error
+
+	// Deletes the two-factor authentication and the recovery codes of the
+	// member.
+	DeleteTwoFactor(ctx context.Context, memberID int64) error
+
+	// Replaces the recovery codes of the member with the codes of the given
+	// hashes.
+	SetRecoveryCodes(ctx context.Context, memberID int64, hashes ...string) error
+
+	// Deletes the recovery code of the hash, so that it may only be used once.
+	// Returns ErrInvalidTwoFactorCode if the member has no such code.
+	UseRecoveryCode(ctx context.Context, memberID int64, hash string) error
+
+	RecoveryCodeCount(ctx context.Context, memberID int64) (int, error)
+
+	InsertPasswordReset(ctx context.Context, r PasswordReset) error
+
+	// Deletes and returns the password reset, so that it may only be used
+	// once. Returns ErrInvalidResetToken if there is no such reset.
+	ConsumePasswordReset(ctx context.Context, resetID ResetID) (PasswordReset, error)
+
+	DeleteMemberPasswordResets(ctx context.Context, memberID int64) error
 
 	ListPermissions(ctx context.Context, q query.ListQuery) (query.ListResult[Permission], error)
 	TeamPermissions(ctx context.Context, teamID int64) (PermissionCollection, error)
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidResetToken = errors.New("Password reset link is invalid or has expired")
+)
+
+// A token given to a member by email, with which the member may set a new
+// password once.
+type ResetToken string
+
+// The stored hash of a reset token.
+type ResetID string
+
+type PasswordReset struct {
+	ID        ResetID
+	MemberID  int64
+	ExpiresAt time.Time
+}
+
+func NewPasswordReset(token ResetToken, memberID int64, lifetime time.Duration) PasswordReset {
+	return PasswordReset{
+		ID:        token.ResetID(),
+		MemberID:  memberID,
+		ExpiresAt: time.Now().Add(lifetime),
+	}
+}
+
+func NewResetToken() (ResetToken, error) {
+	token, err := GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	return ResetToken(token), nil
+}
+
+func (token ResetToken) ResetID() ResetID {
+	return ResetID(HashToken(string(token)))
+}
+
+// Returns whether or not the reset has passed its expiry date.
+func (r PasswordReset) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
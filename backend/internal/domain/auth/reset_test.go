@@ -0,0 +1,57 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+)
+
+func TestResetID(t *testing.T) {
+	token, err := auth.NewResetToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := auth.NewResetToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token == other {
+		t.Fatalf("got equal tokens %q, want distinct tokens", token)
+	}
+
+	if token.ResetID() != token.ResetID() {
+		t.Fatalf("got differing IDs of the same token, want equal IDs")
+	}
+
+	if string(token.ResetID()) == string(token) {
+		t.Fatalf("got ID equal to token, want hashed token")
+	}
+
+	if token.ResetID() == other.ResetID() {
+		t.Fatalf("got equal IDs of distinct tokens, want distinct IDs")
+	}
+}
+
+func TestResetIsExpired(t *testing.T) {
+	type test struct {
+		lifetime time.Duration
+		want     bool
+	}
+
+	tests := map[string]test{
+		"Unexpired": {lifetime: time.Hour, want: false},
+		"Expired":   {lifetime: -time.Minute, want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := auth.NewPasswordReset("token", 1, tt.lifetime)
+			if got := r.IsExpired(); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
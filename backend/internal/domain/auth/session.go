@@ -1,14 +1,16 @@
 package auth
 
 import (
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/base32"
-	"encoding/hex"
 	"errors"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// The maximum length of a recorded user agent. Longer user agents are
+// truncated.
+const MAX_USER_AGENT_LENGTH = 512
+
 var (
 	ErrNoSession      = errors.New("No such session")
 	ErrInvalidSession = errors.New("Session is invalid")
@@ -17,40 +19,95 @@ var (
 type SessionToken string
 type SessionID string
 
+// A step of logging in, which must be completed before a session is valid.
+type PendingStep string
+
+const (
+	// The member must enter a two-factor code.
+	PendingTwoFactor PendingStep = "two-factor"
+
+	// The member must set up two-factor authentication, as it is required for
+	// the teams of the member.
+	PendingTwoFactorEnrolment PendingStep = "two-factor-enrolment"
+)
+
 type Session struct {
-	ID        SessionID
-	MemberID  int64
-	ExpiresAt time.Time
+	ID        SessionID `json:"id"`
+	MemberID  int64     `json:"memberId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// The client, from which the member logged in.
+	Client
+
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+
+	// The step of logging in, which the session awaits, if any. Pending
+	// sessions are short-lived and grant no permissions.
+	Pending PendingStep `json:"pending,omitempty"`
+
+	// The amount of invalid two-factor codes entered for a pending session.
+	FailedAttempts int `json:"-"`
 }
 
-func NewSession(token SessionToken, memberID int64, lifetime time.Duration) Session {
-	expiry := time.Now().Add(lifetime)
+// Describes the device, from which a session was created.
+type Client struct {
+	UserAgent string `json:"userAgent"`
+	IPAddress string `json:"ipAddress"`
+}
+
+func NewSession(token SessionToken, memberID int64, lifetime time.Duration, client Client) Session {
+	now := time.Now()
 
 	return Session{
-		ID:        token.SessionID(),
-		MemberID:  memberID,
-		ExpiresAt: expiry,
+		ID:         token.SessionID(),
+		MemberID:   memberID,
+		ExpiresAt:  now.Add(lifetime),
+		Client:     client,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+}
+
+// Returns a session awaiting the given step of logging in.
+func NewPendingSession(token SessionToken, memberID int64, lifetime time.Duration, client Client, step PendingStep) Session {
+	s := NewSession(token, memberID, lifetime, client)
+	s.Pending = step
+
+	return s
+}
+
+// Returns whether or not the session awaits a step of logging in.
+func (s Session) IsPending() bool {
+	return s.Pending != ""
+}
+
+// Returns a client of the given user agent and IP address, truncating the user
+// agent to at most MAX_USER_AGENT_LENGTH characters.
+func NewClient(userAgent, ipAddress string) Client {
+	userAgent = strings.TrimSpace(userAgent)
+
+	if utf8.RuneCountInString(userAgent) > MAX_USER_AGENT_LENGTH {
+		userAgent = string([]rune(userAgent)[:MAX_USER_AGENT_LENGTH])
+	}
+
+	return Client{
+		UserAgent: userAgent,
+		IPAddress: strings.TrimSpace(ipAddress),
 	}
 }
 
 func NewSessionToken() (SessionToken, error) {
-	bytes := make([]byte, 20)
-	_, err := rand.Read(bytes)
+	token, err := GenerateToken()
 	if err != nil {
 		return "", err
 	}
 
-	encoder := base32.StdEncoding.WithPadding(base32.NoPadding)
-	token := encoder.EncodeToString(bytes)
 	return SessionToken(token), nil
 }
 
 func (token SessionToken) SessionID() SessionID {
-	hash := sha256.Sum256([]byte(token))
-
-	sessionID := hex.EncodeToString(hash[:])
-
-	return SessionID(sessionID)
+	return SessionID(HashToken(string(token)))
 }
 
 // Returns whether or not the session has passed its expiry date.
@@ -78,3 +135,9 @@ func (s Session) IsRefreshable(buffer time.Duration) bool {
 
 	return false
 }
+
+// Returns whether or not the session has been seen within the given duration
+// of now.
+func (s Session) SeenWithin(d time.Duration) bool {
+	return time.Since(s.LastSeenAt) < d
+}
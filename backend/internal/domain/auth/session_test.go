@@ -0,0 +1,82 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+)
+
+func TestNewClient(t *testing.T) {
+	type test struct {
+		userAgent string
+		ipAddress string
+		want      auth.Client
+	}
+
+	longUserAgent := strings.Repeat("æ", auth.MAX_USER_AGENT_LENGTH+10)
+
+	tests := map[string]test{
+		"Valid client": {
+			userAgent: "Mozilla/5.0",
+			ipAddress: "192.0.2.1",
+			want:      auth.Client{UserAgent: "Mozilla/5.0", IPAddress: "192.0.2.1"},
+		},
+		"Surrounding whitespace": {
+			userAgent: "  Mozilla/5.0 ",
+			ipAddress: " 2001:db8::1 ",
+			want:      auth.Client{UserAgent: "Mozilla/5.0", IPAddress: "2001:db8::1"},
+		},
+		"Empty client": {
+			want: auth.Client{},
+		},
+		"Long user agent": {
+			userAgent: longUserAgent,
+			want:      auth.Client{UserAgent: strings.Repeat("æ", auth.MAX_USER_AGENT_LENGTH)},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := auth.NewClient(tt.userAgent, tt.ipAddress)
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeenWithin(t *testing.T) {
+	type test struct {
+		lastSeenAt time.Time
+		within     time.Duration
+		want       bool
+	}
+
+	tests := map[string]test{
+		"Recently seen": {
+			lastSeenAt: time.Now().Add(-time.Minute),
+			within:     5 * time.Minute,
+			want:       true,
+		},
+		"Seen long ago": {
+			lastSeenAt: time.Now().Add(-time.Hour),
+			within:     5 * time.Minute,
+			want:       false,
+		},
+		"Never seen": {
+			within: 5 * time.Minute,
+			want:   false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := auth.Session{LastSeenAt: tt.lastSeenAt}
+			if got := s.SeenWithin(tt.within); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
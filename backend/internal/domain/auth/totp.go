@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// The duration, for which a two-factor code is valid.
+	TOTP_PERIOD = 30 * time.Second
+
+	// The amount of digits of a two-factor code.
+	TOTP_DIGITS = 6
+
+	// The amount of periods before and after the current period, within which
+	// codes are accepted, allowing for clocks out of sync.
+	TOTP_SKEW = 1
+)
+
+var (
+	ErrInvalidTOTPSecret    = errors.New("Two-factor secret is invalid")
+	ErrInvalidTwoFactorCode = errors.New("Two-factor code is invalid")
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// The base32 encoded key shared with the authenticator app of a member, from
+// which time-based one-time passwords of RFC 6238 are generated.
+type TOTPSecret string
+
+// Returns a random secret of 160 bits, as recommended by RFC 4226.
+func NewTOTPSecret() (TOTPSecret, error) {
+	secret, err := GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	return TOTPSecret(secret), nil
+}
+
+func (s TOTPSecret) key() ([]byte, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(string(s)))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidTOTPSecret
+	}
+
+	return key, nil
+}
+
+// Returns the code of the secret at the given time.
+func (s TOTPSecret) Code(t time.Time) (string, error) {
+	key, err := s.key()
+	if err != nil {
+		return "", err
+	}
+
+	return TOTP(key, t, TOTP_PERIOD, TOTP_DIGITS), nil
+}
+
+// Verifies the code at the given time, accepting codes of up to TOTP_SKEW
+// periods before or after it. Codes of periods up to and including the given
+// last used period are rejected, so that a code cannot be used twice. Returns
+// the period of the code.
+func (s TOTPSecret) Verify(code string, t time.Time, lastPeriod int64) (int64, error) {
+	key, err := s.key()
+	if err != nil {
+		return 0, err
+	}
+
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != TOTP_DIGITS {
+		return 0, ErrInvalidTwoFactorCode
+	}
+
+	current := TOTPPeriod(t, TOTP_PERIOD)
+
+	for period := current - TOTP_SKEW; period <= current+TOTP_SKEW; period++ {
+		if period <= lastPeriod {
+			continue
+		}
+
+		want := HOTP(key, uint64(period), TOTP_DIGITS)
+		if subtle.ConstantTimeCompare([]byte(code), []byte(want)) == 1 {
+			return period, nil
+		}
+	}
+
+	return 0, ErrInvalidTwoFactorCode
+}
+
+// Returns the otpauth URI of the secret, which authenticator apps read from a
+// QR code. The account is shown beneath the issuer in the app.
+func (s TOTPSecret) URI(issuer, account string) string {
+	q := url.Values{}
+	q.Set("secret", string(s))
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(TOTP_DIGITS))
+	q.Set("period", fmt.Sprint(int(TOTP_PERIOD.Seconds())))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: q.Encode(),
+	}
+
+	return u.String()
+}
+
+// Returns the period of the given time, counted from the Unix epoch.
+func TOTPPeriod(t time.Time, period time.Duration) int64 {
+	return t.Unix() / int64(period.Seconds())
+}
+
+// Returns the time-based one-time password of RFC 6238 of the key at the given
+// time.
+func TOTP(key []byte, t time.Time, period time.Duration, digits int) string {
+	return HOTP(key, uint64(TOTPPeriod(t, period)), digits)
+}
+
+// Returns the HMAC-based one-time password of RFC 4226 of the key and counter.
+func HOTP(key []byte, counter uint64, digits int) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation of RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
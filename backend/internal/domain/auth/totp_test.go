@@ -0,0 +1,159 @@
+package auth_test
+
+import (
+	"encoding/base32"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+)
+
+// The shared secret of the test vectors of RFC 4226 and RFC 6238.
+var rfcKey = []byte("12345678901234567890")
+
+func TestHOTP(t *testing.T) {
+	type test struct {
+		counter uint64
+		want    string
+	}
+
+	// RFC 4226, appendix D.
+	tests := map[string]test{
+		"Counter 0": {counter: 0, want: "755224"},
+		"Counter 1": {counter: 1, want: "287082"},
+		"Counter 2": {counter: 2, want: "359152"},
+		"Counter 3": {counter: 3, want: "969429"},
+		"Counter 4": {counter: 4, want: "338314"},
+		"Counter 5": {counter: 5, want: "254676"},
+		"Counter 6": {counter: 6, want: "287922"},
+		"Counter 7": {counter: 7, want: "162583"},
+		"Counter 8": {counter: 8, want: "399871"},
+		"Counter 9": {counter: 9, want: "520489"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := auth.HOTP(rfcKey, tt.counter, 6); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTOTP(t *testing.T) {
+	type test struct {
+		unix int64
+		want string
+	}
+
+	// RFC 6238, appendix B, SHA-1.
+	tests := map[string]test{
+		"1970-01-01 00:00:59": {unix: 59, want: "94287082"},
+		"2005-03-18 01:58:29": {unix: 1111111109, want: "07081804"},
+		"2005-03-18 01:58:31": {unix: 1111111111, want: "14050471"},
+		"2009-02-13 23:31:30": {unix: 1234567890, want: "89005924"},
+		"2033-05-18 03:33:20": {unix: 2000000000, want: "69279037"},
+		"2603-10-11 11:33:20": {unix: 20000000000, want: "65353130"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := auth.TOTP(rfcKey, time.Unix(tt.unix, 0), 30*time.Second, 8)
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTOTPSecretVerify(t *testing.T) {
+	secret := auth.TOTPSecret(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(rfcKey))
+
+	now := time.Unix(1111111109, 0)
+	period := auth.TOTPPeriod(now, auth.TOTP_PERIOD)
+
+	code, err := secret.Code(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous, err := secret.Code(now.Add(-auth.TOTP_PERIOD))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err := secret.Code(now.Add(-time.Duration(auth.TOTP_SKEW+1) * auth.TOTP_PERIOD))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type test struct {
+		secret     auth.TOTPSecret
+		code       string
+		lastPeriod int64
+		want       int64
+		wantErr    error
+	}
+
+	tests := map[string]test{
+		"Current code":         {secret: secret, code: code, want: period},
+		"Spaced code":          {secret: secret, code: code[:3] + " " + code[3:], want: period},
+		"Previous code":        {secret: secret, code: previous, want: period - 1},
+		"Stale code":           {secret: secret, code: stale, wantErr: auth.ErrInvalidTwoFactorCode},
+		"Used code":            {secret: secret, code: code, lastPeriod: period, wantErr: auth.ErrInvalidTwoFactorCode},
+		"Code before used":     {secret: secret, code: previous, lastPeriod: period - 1, wantErr: auth.ErrInvalidTwoFactorCode},
+		"Wrong code":           {secret: secret, code: "000000", wantErr: auth.ErrInvalidTwoFactorCode},
+		"Short code":           {secret: secret, code: code[:5], wantErr: auth.ErrInvalidTwoFactorCode},
+		"Lowercase secret":     {secret: auth.TOTPSecret("gezdgnbvgy3tqojqgezdgnbvgy3tqojq"), code: code, want: period},
+		"Malformed secret":     {secret: "not base32!", code: code, wantErr: auth.ErrInvalidTOTPSecret},
+		"Empty secret":         {secret: "", code: code, wantErr: auth.ErrInvalidTOTPSecret},
+		"Empty code":           {secret: secret, code: "", wantErr: auth.ErrInvalidTwoFactorCode},
+		"Code of other secret": {secret: auth.TOTPSecret("JBSWY3DPEHPK3PXP"), code: code, wantErr: auth.ErrInvalidTwoFactorCode},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.secret.Verify(tt.code, now, tt.lastPeriod)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got period %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTOTPSecretURI(t *testing.T) {
+	secret := auth.TOTPSecret("JBSWY3DPEHPK3PXP")
+
+	u, err := url.Parse(secret.URI("Konnekt", "anna@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Fatalf("got %s://%s, want otpauth://totp", u.Scheme, u.Host)
+	}
+
+	if u.Path != "/Konnekt:anna@example.com" {
+		t.Fatalf("got label %q, want %q", u.Path, "/Konnekt:anna@example.com")
+	}
+
+	want := map[string]string{
+		"secret":    "JBSWY3DPEHPK3PXP",
+		"issuer":    "Konnekt",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	}
+
+	for key, value := range want {
+		if got := u.Query().Get(key); got != value {
+			t.Fatalf("got %s %q, want %q", key, got, value)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+)
+
+// The amount of recovery codes given to a member on enabling two-factor
+// authentication.
+const RECOVERY_CODE_COUNT = 10
+
+var (
+	ErrTwoFactorNotEnrolled     = errors.New("Two-factor authentication is not set up")
+	ErrTwoFactorAlreadyEnabled  = errors.New("Two-factor authentication is already enabled")
+	ErrTwoFactorRequired        = errors.New("Two-factor authentication is required for the teams of the member")
+	ErrTwoFactorPending         = errors.New("Two-factor authentication must be completed")
+	ErrTooManyTwoFactorAttempts = errors.New("Too many invalid two-factor codes. Please log in again")
+)
+
+// The two-factor authentication of a member.
+type TwoFactor struct {
+	MemberID int64
+	Secret   TOTPSecret
+
+	// The time the member confirmed the secret with a code, or nil while the
+	// member is enrolling.
+	ConfirmedAt *time.Time
+
+	// The latest period, in which a code was used. Codes of this or earlier
+	// periods are rejected.
+	LastPeriod int64
+}
+
+func NewTwoFactor(memberID int64, secret TOTPSecret) TwoFactor {
+	return TwoFactor{
+		MemberID: memberID,
+		Secret:   secret,
+	}
+}
+
+// Returns whether or not the member has confirmed the secret, such that codes
+// are required on login.
+func (tf TwoFactor) IsEnabled() bool {
+	return tf.ConfirmedAt != nil
+}
+
+// A single-use code, with which a member may log in without the
+// authenticator app. Only the hash of the code is stored.
+type RecoveryCode string
+
+// The 32 characters of recovery codes, leaving out i, l, o and 1, which are
+// easily mistaken for one another.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz023456789"
+
+// Returns the given amount of random recovery codes, formatted as two groups
+// of five characters, such as "k7m2p-x9qrt".
+func NewRecoveryCodes(count int) ([]RecoveryCode, error) {
+	codes := make([]RecoveryCode, 0, count)
+
+	for range count {
+		bytes := make([]byte, 10)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, err
+		}
+
+		var sb strings.Builder
+		for i, b := range bytes {
+			if i == 5 {
+				sb.WriteByte('-')
+			}
+
+			sb.WriteByte(recoveryCodeAlphabet[b%32])
+		}
+
+		codes = append(codes, RecoveryCode(sb.String()))
+	}
+
+	return codes, nil
+}
+
+// Returns the hash of the code, by which it is stored. Codes are compared
+// regardless of case, spaces and dashes.
+func (c RecoveryCode) Hash() string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(string(c)))
+
+	return HashToken(normalized)
+}
@@ -0,0 +1,66 @@
+package auth_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+)
+
+func TestNewRecoveryCodes(t *testing.T) {
+	codes, err := auth.NewRecoveryCodes(auth.RECOVERY_CODE_COUNT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(codes) != auth.RECOVERY_CODE_COUNT {
+		t.Fatalf("got %d codes, want %d", len(codes), auth.RECOVERY_CODE_COUNT)
+	}
+
+	format := regexp.MustCompile(`^[a-hjkmnp-z02-9]{5}-[a-hjkmnp-z02-9]{5}$`)
+	seen := make(map[auth.RecoveryCode]bool)
+
+	for _, code := range codes {
+		if !format.MatchString(string(code)) {
+			t.Fatalf("got code %q, want two groups of five characters", code)
+		}
+
+		if seen[code] {
+			t.Fatalf("got duplicate code %q, want distinct codes", code)
+		}
+
+		seen[code] = true
+	}
+}
+
+func TestRecoveryCodeHash(t *testing.T) {
+	type test struct {
+		code  auth.RecoveryCode
+		other auth.RecoveryCode
+		want  bool
+	}
+
+	tests := map[string]test{
+		"Same code":       {code: "k7m2p-x9qrt", other: "k7m2p-x9qrt", want: true},
+		"Uppercase":       {code: "k7m2p-x9qrt", other: "K7M2P-X9QRT", want: true},
+		"Without dash":    {code: "k7m2p-x9qrt", other: "k7m2px9qrt", want: true},
+		"With spaces":     {code: "k7m2p-x9qrt", other: " k7m2p x9qrt ", want: true},
+		"Different code":  {code: "k7m2p-x9qrt", other: "k7m2p-x9qrs", want: false},
+		"Plain code hash": {code: "k7m2p-x9qrt", other: auth.RecoveryCode(auth.RecoveryCode("k7m2p-x9qrt").Hash()), want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.code.Hash() == tt.other.Hash(); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTwoFactorIsEnabled(t *testing.T) {
+	tf := auth.NewTwoFactor(1, "JBSWY3DPEHPK3PXP")
+	if tf.IsEnabled() {
+		t.Fatalf("got enabled, want unconfirmed enrolment disabled")
+	}
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The minimum length of the key, with which verification tokens are signed.
+const MINIMUM_SIGNING_KEY_LENGTH = 32
+
+var (
+	ErrSigningKeyTooShort       = errors.New("Signing key must be at least 32 bytes long")
+	ErrInvalidVerificationToken = errors.New("Verification link is invalid or has expired")
+)
+
+// A token given to a member by email, proving that the member owns the email.
+// The token is signed rather than stored, and holds the member, the email and
+// the expiry of the token.
+type VerificationToken string
+
+// The claims of a verification token.
+type Verification struct {
+	MemberID  int64
+	Email     string
+	ExpiresAt time.Time
+}
+
+// Signs and verifies verification tokens.
+type Verifier struct {
+	key []byte
+}
+
+func NewVerifier(key []byte) (*Verifier, error) {
+	if len(key) < MINIMUM_SIGNING_KEY_LENGTH {
+		return nil, ErrSigningKeyTooShort
+	}
+
+	return &Verifier{key: key}, nil
+}
+
+func (v Verifier) Sign(claims Verification) VerificationToken {
+	payload := fmt.Sprintf("%d\n%s\n%d", claims.MemberID, claims.Email, claims.ExpiresAt.Unix())
+
+	encoder := base64.RawURLEncoding
+	encodedPayload := encoder.EncodeToString([]byte(payload))
+
+	return VerificationToken(encodedPayload + "." + encoder.EncodeToString(v.sign(encodedPayload)))
+}
+
+// Returns the claims of the token, if it is signed by the verifier and has not
+// expired at the given time.
+func (v Verifier) Verify(token VerificationToken, now time.Time) (Verification, error) {
+	encodedPayload, encodedSig, ok := strings.Cut(string(token), ".")
+	if !ok {
+		return Verification{}, ErrInvalidVerificationToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return Verification{}, ErrInvalidVerificationToken
+	}
+
+	if !hmac.Equal(sig, v.sign(encodedPayload)) {
+		return Verification{}, ErrInvalidVerificationToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return Verification{}, ErrInvalidVerificationToken
+	}
+
+	parts := strings.Split(string(payload), "\n")
+	if len(parts) != 3 {
+		return Verification{}, ErrInvalidVerificationToken
+	}
+
+	memberID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Verification{}, ErrInvalidVerificationToken
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Verification{}, ErrInvalidVerificationToken
+	}
+
+	claims := Verification{
+		MemberID:  memberID,
+		Email:     parts[1],
+		ExpiresAt: time.Unix(expiresAt, 0),
+	}
+
+	if !now.Before(claims.ExpiresAt) {
+		return Verification{}, ErrInvalidVerificationToken
+	}
+
+	return claims, nil
+}
+
+func (v Verifier) sign(encodedPayload string) []byte {
+	mac := hmac.New(sha256.New, v.key)
+	mac.Write([]byte(encodedPayload))
+	return mac.Sum(nil)
+}
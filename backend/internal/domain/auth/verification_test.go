@@ -0,0 +1,91 @@
+package auth_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+)
+
+func TestNewVerifier(t *testing.T) {
+	type test struct {
+		key     []byte
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Valid key":     {key: bytes.Repeat([]byte("k"), auth.MINIMUM_SIGNING_KEY_LENGTH)},
+		"Short key":     {key: bytes.Repeat([]byte("k"), auth.MINIMUM_SIGNING_KEY_LENGTH-1), wantErr: auth.ErrSigningKeyTooShort},
+		"Empty key":     {key: nil, wantErr: auth.ErrSigningKeyTooShort},
+		"Long key":      {key: bytes.Repeat([]byte("k"), 2*auth.MINIMUM_SIGNING_KEY_LENGTH)},
+		"Arbitrary key": {key: bytes.Repeat([]byte{0, 255}, auth.MINIMUM_SIGNING_KEY_LENGTH)},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := auth.NewVerifier(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	verifier, err := auth.NewVerifier(bytes.Repeat([]byte("k"), auth.MINIMUM_SIGNING_KEY_LENGTH))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := auth.NewVerifier(bytes.Repeat([]byte("o"), auth.MINIMUM_SIGNING_KEY_LENGTH))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := auth.Verification{
+		MemberID:  42,
+		Email:     "a@example.com",
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	token := verifier.Sign(claims)
+	payload, sig, _ := strings.Cut(string(token), ".")
+
+	forged := auth.Verification{MemberID: 1, Email: "a@example.com", ExpiresAt: now.Add(time.Hour)}
+	forgedPayload, _, _ := strings.Cut(string(verifier.Sign(forged)), ".")
+
+	type test struct {
+		token   auth.VerificationToken
+		now     time.Time
+		want    auth.Verification
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Valid token":         {token: token, now: now, want: claims},
+		"Expired token":       {token: token, now: now.Add(time.Hour), wantErr: auth.ErrInvalidVerificationToken},
+		"Other key":           {token: other.Sign(claims), now: now, wantErr: auth.ErrInvalidVerificationToken},
+		"Swapped payload":     {token: auth.VerificationToken(forgedPayload + "." + sig), now: now, wantErr: auth.ErrInvalidVerificationToken},
+		"Missing signature":   {token: auth.VerificationToken(payload), now: now, wantErr: auth.ErrInvalidVerificationToken},
+		"Malformed signature": {token: auth.VerificationToken(payload + ".!"), now: now, wantErr: auth.ErrInvalidVerificationToken},
+		"Empty token":         {token: "", now: now, wantErr: auth.ErrInvalidVerificationToken},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := verifier.Verify(tt.token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got.MemberID != tt.want.MemberID || got.Email != tt.want.Email || !got.ExpiresAt.Equal(tt.want.ExpiresAt) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package content
 
+import (
+	"errors"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+var ErrLandingImageExists = errors.New("Landing image already exists")
+
 type LandingImage struct {
-	ID  int64  `json:"id"`
-	URL string `json:"url"`
+	ID int64 `json:"id"`
+	media.Image
 }
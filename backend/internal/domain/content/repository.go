@@ -1,10 +1,15 @@
 package content
 
-import "context"
+import (
+	"context"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
 
 type Repository interface {
 	LandingImages(ctx context.Context) ([]LandingImage, error)
 	LandingImageByID(ctx context.Context, id int64) (LandingImage, error)
-	InsertLandingImage(ctx context.Context, url string) (int64, error)
+	InsertLandingImage(ctx context.Context, img media.Image) (int64, error)
+	SetLandingImage(ctx context.Context, id int64, img media.Image) error
 	DeleteLandingImage(ctx context.Context, id int64) error
 }
@@ -0,0 +1,66 @@
+package event
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/concert"
+)
+
+var (
+	ErrInvalidCloneShift = errors.New("Clone must be shifted by either a day offset or a new base date")
+)
+
+// Returns a copy of the event as a new draft, with its concerts shifted either
+// by the given amount of days, or such that the earliest concert starts at the
+// given base date. Exactly one of the two must be given.
+//
+// The status, publish schedule and recurrence of the event are not copied, as
+// the clone must be published separately.
+func (e Event) Clone(offsetDays int, baseDate *time.Time) (Event, error) {
+	if (offsetDays != 0) == (baseDate != nil) {
+		return Event{}, ErrInvalidCloneShift
+	}
+
+	shift := func(t time.Time) time.Time {
+		return t.AddDate(0, 0, offsetDays)
+	}
+
+	if baseDate != nil {
+		if len(e.Concerts) <= 0 {
+			return Event{}, ErrInvalidCloneShift
+		}
+
+		start := e.Concerts[0].From
+		for _, c := range e.Concerts {
+			if c.From.Before(start) {
+				start = c.From
+			}
+		}
+
+		offset := baseDate.Sub(start)
+		shift = func(t time.Time) time.Time {
+			return t.Add(offset)
+		}
+	}
+
+	concerts := make([]concert.Concert, 0)
+	for _, c := range e.Concerts {
+		c.ID = 0
+		c.From = shift(c.From).UTC()
+		c.To = shift(c.To).UTC()
+
+		concerts = append(concerts, c)
+	}
+
+	return Event{
+		Title:       e.Title,
+		Description: e.Description,
+		TicketURL:   e.TicketURL,
+		Image:       e.Image,
+		Venue:       e.Venue,
+		Concerts:    concerts,
+		Status:      StatusDraft,
+		Series:      e.Series,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package event_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/concert"
+	"github.com/mattismoel/konnekt/internal/domain/event"
+)
+
+func TestClone(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 7, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	baseDate := at(20, 18)
+
+	e := event.Event{
+		ID:     1,
+		Title:  "Event",
+		Status: event.StatusScheduled,
+		Concerts: []concert.Concert{
+			{ID: 1, From: at(1, 20), To: at(1, 22)},
+			{ID: 2, From: at(1, 19), To: at(1, 20)},
+		},
+	}
+
+	type test struct {
+		offsetDays int
+		baseDate   *time.Time
+		wantFroms  []time.Time
+		wantErr    error
+	}
+
+	tests := map[string]test{
+		"Day offset": {
+			offsetDays: 7,
+			wantFroms:  []time.Time{at(8, 20), at(8, 19)},
+		},
+		"Negative day offset": {
+			offsetDays: -1,
+			wantFroms:  []time.Time{at(0, 20), at(0, 19)},
+		},
+		"Base date": {
+			baseDate:  &baseDate,
+			wantFroms: []time.Time{at(20, 19), at(20, 18)},
+		},
+		"No shift": {
+			wantErr: event.ErrInvalidCloneShift,
+		},
+		"Both shifts": {
+			offsetDays: 7,
+			baseDate:   &baseDate,
+			wantErr:    event.ErrInvalidCloneShift,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			clone, err := e.Clone(tt.offsetDays, tt.baseDate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if clone.ID != 0 || clone.Status != event.StatusDraft {
+				t.Fatalf("got ID %d and status %q, want new draft", clone.ID, clone.Status)
+			}
+
+			gotFroms := make([]time.Time, 0)
+			for _, c := range clone.Concerts {
+				if c.ID != 0 {
+					t.Fatalf("got concert ID %d, want 0", c.ID)
+				}
+
+				if c.To.Sub(c.From) <= 0 {
+					t.Fatalf("got concert of non-positive duration")
+				}
+
+				gotFroms = append(gotFroms, c.From)
+			}
+
+			if !slices.EqualFunc(gotFroms, tt.wantFroms, time.Time.Equal) {
+				t.Fatalf("got %v, want %v", gotFroms, tt.wantFroms)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package event
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrBookingConflict = errors.New("Event overlaps with existing bookings")
+)
+
+type ConflictReason string
+
+const (
+	// The same artist is booked for overlapping concerts.
+	ConflictArtist = ConflictReason("artist")
+
+	// The same venue hosts overlapping concerts of different events.
+	ConflictVenue = ConflictReason("venue")
+)
+
+// A booking of an artist at a venue for a given time range, as given by the
+// concerts of an event.
+type Booking struct {
+	EventID    int64     `json:"eventId"`
+	EventTitle string    `json:"eventTitle"`
+	ConcertID  int64     `json:"concertId"`
+	ArtistID   int64     `json:"artistId"`
+	VenueID    int64     `json:"venueId"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+}
+
+// Returns the statuses, whose events hold the bookings of their artists and
+// venue. Drafts are not yet confirmed, and cancelled and archived events no
+// longer take place, so their bookings conflict with no other bookings.
+func BookingStatuses() []Status {
+	return []Status{StatusScheduled, StatusPostponed}
+}
+
+// Returns whether or not the time ranges of two bookings overlap. Bookings
+// ending exactly when another starts do not overlap.
+func (b Booking) Overlaps(other Booking) bool {
+	return b.From.Before(other.To) && other.From.Before(b.To)
+}
+
+// A pair of overlapping bookings of different events.
+type Conflict struct {
+	Booking Booking          `json:"booking"`
+	With    Booking          `json:"with"`
+	Reasons []ConflictReason `json:"reasons"`
+}
+
+type BookingQuery struct {
+	// The time range, which bookings must overlap.
+	From time.Time
+	To   time.Time
+
+	// If any artist IDs or a venue ID are given, only bookings of either the
+	// artists or the venue are returned.
+	ArtistIDs []int64
+	VenueID   int64
+
+	// Bookings of the event with this ID are excluded. Used when checking an
+	// event against the bookings of all other events.
+	ExcludeEventID int64
+}
+
+// An error describing the conflicts preventing an event from being booked.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e ConflictError) Error() string {
+	return ErrBookingConflict.Error()
+}
+
+func (e ConflictError) Unwrap() error {
+	return ErrBookingConflict
+}
+
+// Returns all conflicts between the candidate bookings and the existing
+// bookings. Bookings of the same event never conflict with each other.
+func FindConflicts(candidates []Booking, existing []Booking) []Conflict {
+	conflicts := make([]Conflict, 0)
+
+	for _, candidate := range candidates {
+		for _, other := range existing {
+			if conflict, ok := conflictBetween(candidate, other); ok {
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// Returns all conflicts among the given bookings, reporting each conflicting
+// pair once.
+func AuditConflicts(bookings []Booking) []Conflict {
+	conflicts := make([]Conflict, 0)
+
+	for i, booking := range bookings {
+		for _, other := range bookings[i+1:] {
+			if conflict, ok := conflictBetween(booking, other); ok {
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+
+	return conflicts
+}
+
+func conflictBetween(b, other Booking) (Conflict, bool) {
+	if b.EventID != 0 && b.EventID == other.EventID {
+		return Conflict{}, false
+	}
+
+	if !b.Overlaps(other) {
+		return Conflict{}, false
+	}
+
+	reasons := make([]ConflictReason, 0)
+
+	if b.ArtistID == other.ArtistID {
+		reasons = append(reasons, ConflictArtist)
+	}
+
+	if b.VenueID == other.VenueID {
+		reasons = append(reasons, ConflictVenue)
+	}
+
+	if len(reasons) <= 0 {
+		return Conflict{}, false
+	}
+
+	return Conflict{Booking: b, With: other, Reasons: reasons}, true
+}
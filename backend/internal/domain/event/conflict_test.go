@@ -0,0 +1,120 @@
+package event_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/event"
+)
+
+func TestFindConflicts(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2025, 7, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	existing := []event.Booking{
+		{EventID: 1, ConcertID: 1, ArtistID: 1, VenueID: 1, From: at(18), To: at(20)},
+		{EventID: 2, ConcertID: 2, ArtistID: 2, VenueID: 2, From: at(20), To: at(22)},
+	}
+
+	type test struct {
+		candidate   event.Booking
+		wantReasons [][]event.ConflictReason
+	}
+
+	tests := map[string]test{
+		"No overlap": {
+			candidate:   event.Booking{ArtistID: 1, VenueID: 1, From: at(12), To: at(14)},
+			wantReasons: [][]event.ConflictReason{},
+		},
+		"Adjacent bookings": {
+			candidate:   event.Booking{ArtistID: 1, VenueID: 1, From: at(20), To: at(21)},
+			wantReasons: [][]event.ConflictReason{},
+		},
+		"Same artist elsewhere": {
+			candidate:   event.Booking{ArtistID: 1, VenueID: 3, From: at(19), To: at(21)},
+			wantReasons: [][]event.ConflictReason{{event.ConflictArtist}},
+		},
+		"Same venue, other artist": {
+			candidate:   event.Booking{ArtistID: 3, VenueID: 2, From: at(21), To: at(23)},
+			wantReasons: [][]event.ConflictReason{{event.ConflictVenue}},
+		},
+		"Same artist and venue": {
+			candidate:   event.Booking{ArtistID: 1, VenueID: 1, From: at(17), To: at(19)},
+			wantReasons: [][]event.ConflictReason{{event.ConflictArtist, event.ConflictVenue}},
+		},
+		"Overlapping both events": {
+			candidate: event.Booking{ArtistID: 2, VenueID: 1, From: at(19), To: at(21)},
+			wantReasons: [][]event.ConflictReason{
+				{event.ConflictVenue},
+				{event.ConflictArtist},
+			},
+		},
+		"Same event": {
+			candidate:   event.Booking{EventID: 1, ArtistID: 1, VenueID: 1, From: at(18), To: at(20)},
+			wantReasons: [][]event.ConflictReason{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			conflicts := event.FindConflicts([]event.Booking{tt.candidate}, existing)
+
+			gotReasons := make([][]event.ConflictReason, 0)
+			for _, c := range conflicts {
+				gotReasons = append(gotReasons, c.Reasons)
+			}
+
+			if !slices.EqualFunc(gotReasons, tt.wantReasons, slices.Equal) {
+				t.Fatalf("got %v, want %v", gotReasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestAuditConflicts(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2025, 7, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	bookings := []event.Booking{
+		{EventID: 1, ConcertID: 1, ArtistID: 1, VenueID: 1, From: at(18), To: at(20)},
+		{EventID: 1, ConcertID: 2, ArtistID: 2, VenueID: 1, From: at(19), To: at(21)},
+		{EventID: 2, ConcertID: 3, ArtistID: 1, VenueID: 2, From: at(19), To: at(22)},
+	}
+
+	conflicts := event.AuditConflicts(bookings)
+
+	if len(conflicts) != 1 {
+		t.Fatalf("got %d conflicts, want 1: %+v", len(conflicts), conflicts)
+	}
+
+	if conflicts[0].Booking.ConcertID != 1 || conflicts[0].With.ConcertID != 3 {
+		t.Fatalf("got conflict between concerts %d and %d, want 1 and 3",
+			conflicts[0].Booking.ConcertID, conflicts[0].With.ConcertID)
+	}
+}
+
+func TestBookingStatuses(t *testing.T) {
+	type test struct {
+		status event.Status
+		want   bool
+	}
+
+	tests := map[string]test{
+		"Draft":     {status: event.StatusDraft, want: false},
+		"Scheduled": {status: event.StatusScheduled, want: true},
+		"Postponed": {status: event.StatusPostponed, want: true},
+		"Cancelled": {status: event.StatusCancelled, want: false},
+		"Archived":  {status: event.StatusArchived, want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := slices.Contains(event.BookingStatuses(), tt.status); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/mattismoel/konnekt/internal/domain/concert"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/series"
 	"github.com/mattismoel/konnekt/internal/domain/venue"
 )
 
@@ -26,10 +29,28 @@ type Event struct {
 	Title       string            `json:"title"`
 	Description string            `json:"description"`
 	TicketURL   string            `json:"ticketUrl"`
-	ImageURL    string            `json:"imageUrl"`
+	Image       media.Image       `json:"image"`
 	Venue       venue.Venue       `json:"venue"`
 	Concerts    []concert.Concert `json:"concerts"`
-	IsPublic    bool              `json:"isPublic"`
+
+	Status       Status `json:"status"`
+	StatusNotice string `json:"statusNotice,omitempty"`
+
+	// The times at which the event is automatically published and unpublished.
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	UnpublishAt *time.Time `json:"unpublishAt,omitempty"`
+
+	// The series, which the event is part of, if any.
+	Series *series.Series `json:"series,omitempty"`
+
+	// The recurrence, which generated the event, if any, and the start time of
+	// the occurrence, which the event was generated for.
+	Recurrence      *Recurrence `json:"recurrence,omitempty"`
+	OccurrenceStart *time.Time  `json:"occurrenceStart,omitempty"`
+
+	// The time of the latest change to the event itself, not including its
+	// venue and artists.
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type CfgFunc func(e *Event) error
@@ -47,7 +68,7 @@ func (e *Event) WithCfgs(cfgs ...CfgFunc) error {
 func NewEvent(cfgs ...CfgFunc) (*Event, error) {
 	e := &Event{
 		Concerts: make([]concert.Concert, 0),
-		IsPublic: false,
+		Status:   StatusDraft,
 	}
 
 	if err := e.WithCfgs(cfgs...); err != nil {
@@ -116,14 +137,17 @@ func WithTicketURL(u string) CfgFunc {
 	}
 }
 
-func WithImageURL(u string) CfgFunc {
+func WithImage(img media.Image) CfgFunc {
 	return func(e *Event) error {
-		url, err := url.ParseRequestURI(u)
-		if err != nil {
+		if err := img.Attribution.Validate(); err != nil {
+			return err
+		}
+
+		if err := img.Validate(); err != nil {
 			return ErrInvalidImageURL
 		}
 
-		resp, err := http.Get(url.String())
+		resp, err := http.Get(img.URL)
 		if err != nil {
 			return ErrImageURLInaccessible
 		}
@@ -133,7 +157,7 @@ func WithImageURL(u string) CfgFunc {
 			return ErrImageURLInaccessible
 		}
 
-		e.ImageURL = url.String()
+		e.Image = img
 
 		return nil
 	}
@@ -153,9 +177,27 @@ func WithVenue(v venue.Venue) CfgFunc {
 	}
 }
 
-func WithIsPublic(isPublic bool) CfgFunc {
+func WithStatus(status Status) CfgFunc {
+	return func(e *Event) error {
+		if !status.Valid() {
+			return ErrInvalidStatus
+		}
+
+		e.Status = status
+		return nil
+	}
+}
+
+func WithStatusNotice(notice string) CfgFunc {
+	return func(e *Event) error {
+		e.StatusNotice = strings.TrimSpace(notice)
+		return nil
+	}
+}
+
+func WithSeries(s *series.Series) CfgFunc {
 	return func(e *Event) error {
-		e.IsPublic = isPublic
+		e.Series = s
 		return nil
 	}
 }
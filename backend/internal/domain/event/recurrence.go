@@ -0,0 +1,411 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/concert"
+)
+
+const (
+	// The maximum amount of occurrences a recurrence may generate, preventing
+	// rules from materialising an unbounded amount of events.
+	MAX_OCCURRENCES = 366
+
+	// The date format of recurrence exceptions.
+	EXCEPTION_DATE_FORMAT = "2006-01-02"
+
+	ruleUntilDateTimeFormat = "20060102T150405Z"
+	ruleUntilDateFormat     = "20060102"
+)
+
+var (
+	ErrInvalidRule          = errors.New("Recurrence rule must be of format FREQ=WEEKLY;COUNT=4 with WEEKLY or MONTHLY frequency")
+	ErrRuleUnbounded        = errors.New("Recurrence rule must have exactly one of COUNT or UNTIL")
+	ErrRuleByDayUnsupported = errors.New("Recurrence rule BYDAY is only supported for weekly frequency")
+	ErrInvalidTimeZone      = errors.New("Recurrence time zone must be a valid IANA time zone")
+	ErrInvalidException     = errors.New("Recurrence exceptions must be dates of format YYYY-MM-DD")
+	ErrTooManyOccurrences   = fmt.Errorf("Recurrence must not have more than %d occurrences", MAX_OCCURRENCES)
+	ErrNoOccurrences        = errors.New("Recurrence must have at least one occurrence")
+	ErrRecurrenceNoConcerts = errors.New("Recurring event must have at least one concert")
+	ErrNotRecurring         = errors.New("Event is not part of a recurrence")
+)
+
+type Frequency string
+
+const (
+	FrequencyWeekly  = Frequency("WEEKLY")
+	FrequencyMonthly = Frequency("MONTHLY")
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// A recurrence rule, being the subset of RFC 5545 section 3.3.10 needed for
+// recurring events. Supported parts are FREQ (WEEKLY or MONTHLY), INTERVAL,
+// COUNT, UNTIL and, for weekly rules, BYDAY without ordinals. Weeks start on
+// monday.
+type Rule struct {
+	Frequency Frequency
+	Interval  int
+	Count     int
+	Until     *time.Time
+	ByDay     []time.Weekday
+}
+
+// Parses a recurrence rule, such as "FREQ=WEEKLY;INTERVAL=2;COUNT=10". A
+// leading "RRULE:" is allowed.
+func ParseRule(s string) (Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(strings.ToUpper(s)), "RRULE:")
+
+	r := Rule{Interval: 1}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, ErrInvalidRule
+		}
+
+		switch key {
+		case "FREQ":
+			r.Frequency = Frequency(value)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval <= 0 {
+				return Rule{}, ErrInvalidRule
+			}
+
+			r.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count <= 0 {
+				return Rule{}, ErrInvalidRule
+			}
+
+			r.Count = count
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return Rule{}, ErrInvalidRule
+			}
+
+			r.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return Rule{}, ErrInvalidRule
+				}
+
+				if !slices.Contains(r.ByDay, weekday) {
+					r.ByDay = append(r.ByDay, weekday)
+				}
+			}
+		default:
+			return Rule{}, ErrInvalidRule
+		}
+	}
+
+	if r.Frequency != FrequencyWeekly && r.Frequency != FrequencyMonthly {
+		return Rule{}, ErrInvalidRule
+	}
+
+	if (r.Count > 0) == (r.Until != nil) {
+		return Rule{}, ErrRuleUnbounded
+	}
+
+	if r.Count > MAX_OCCURRENCES {
+		return Rule{}, ErrTooManyOccurrences
+	}
+
+	if len(r.ByDay) > 0 && r.Frequency != FrequencyWeekly {
+		return Rule{}, ErrRuleByDayUnsupported
+	}
+
+	// Sort the days by their position in a week starting on monday.
+	slices.SortFunc(r.ByDay, func(a, b time.Weekday) int {
+		return weekdayIndex(a) - weekdayIndex(b)
+	})
+
+	return r, nil
+}
+
+// Returns the rule in its RFC 5545 representation.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(ruleUntilDateTimeFormat))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0)
+		for _, weekday := range r.ByDay {
+			for code, d := range weekdays {
+				if d == weekday {
+					days = append(days, code)
+				}
+			}
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (r Rule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rule) UnmarshalText(text []byte) error {
+	rule, err := ParseRule(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = rule
+	return nil
+}
+
+// A recurrence of an event. Occurrences are generated in the time zone of the
+// recurrence, so that they keep their local time across daylight saving time
+// changes.
+type Recurrence struct {
+	ID       int64  `json:"id"`
+	Rule     Rule   `json:"rule"`
+	TimeZone string `json:"timeZone"`
+
+	// Dates on which no occurrence takes place, even though the rule says
+	// so. As per RFC 5545, exceptions still count towards the COUNT of the
+	// rule.
+	Exceptions []string `json:"exceptions"`
+}
+
+type RecurrenceCfgFunc func(r *Recurrence) error
+
+func NewRecurrence(cfgs ...RecurrenceCfgFunc) (*Recurrence, error) {
+	r := &Recurrence{
+		TimeZone:   "UTC",
+		Exceptions: make([]string, 0),
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(r); err != nil {
+			return &Recurrence{}, err
+		}
+	}
+
+	return r, nil
+}
+
+func WithRule(s string) RecurrenceCfgFunc {
+	return func(r *Recurrence) error {
+		rule, err := ParseRule(s)
+		if err != nil {
+			return err
+		}
+
+		r.Rule = rule
+		return nil
+	}
+}
+
+func WithTimeZone(tz string) RecurrenceCfgFunc {
+	return func(r *Recurrence) error {
+		tz = strings.TrimSpace(tz)
+		if tz == "" {
+			return nil
+		}
+
+		if _, err := time.LoadLocation(tz); err != nil {
+			return ErrInvalidTimeZone
+		}
+
+		r.TimeZone = tz
+		return nil
+	}
+}
+
+func WithExceptions(dates ...string) RecurrenceCfgFunc {
+	return func(r *Recurrence) error {
+		for _, date := range dates {
+			date = strings.TrimSpace(date)
+
+			if _, err := time.Parse(EXCEPTION_DATE_FORMAT, date); err != nil {
+				return ErrInvalidException
+			}
+
+			if !slices.Contains(r.Exceptions, date) {
+				r.Exceptions = append(r.Exceptions, date)
+			}
+		}
+
+		slices.Sort(r.Exceptions)
+		return nil
+	}
+}
+
+// Returns the start times of all occurrences of the recurrence, none of which
+// are before the given start time. The time of day of all occurrences is that
+// of the start time, in the time zone of the recurrence.
+func (r Recurrence) Occurrences(start time.Time) ([]time.Time, error) {
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+
+	start = start.In(loc)
+
+	var candidates func(period int) []time.Time
+
+	switch r.Rule.Frequency {
+	case FrequencyWeekly:
+		candidates = r.weeklyCandidates(start)
+	case FrequencyMonthly:
+		candidates = r.monthlyCandidates(start)
+	default:
+		return nil, ErrInvalidRule
+	}
+
+	occurrences := make([]time.Time, 0)
+	count := 0
+
+	// The amount of periods is bounded, as every period of a weekly rule has
+	// at least one candidate, and at least 7 of every 12 months have a given
+	// day of month.
+	for period := 0; period <= 2*MAX_OCCURRENCES; period++ {
+		for _, candidate := range candidates(period) {
+			if candidate.Before(start) {
+				continue
+			}
+
+			if r.Rule.Until != nil && candidate.After(*r.Rule.Until) {
+				return r.checkOccurrences(occurrences)
+			}
+
+			count++
+
+			if !slices.Contains(r.Exceptions, candidate.Format(EXCEPTION_DATE_FORMAT)) {
+				occurrences = append(occurrences, candidate.UTC())
+			}
+
+			if len(occurrences) > MAX_OCCURRENCES {
+				return nil, ErrTooManyOccurrences
+			}
+
+			if r.Rule.Count > 0 && count >= r.Rule.Count {
+				return r.checkOccurrences(occurrences)
+			}
+		}
+	}
+
+	return nil, ErrTooManyOccurrences
+}
+
+func (r Recurrence) checkOccurrences(occurrences []time.Time) ([]time.Time, error) {
+	if len(occurrences) <= 0 {
+		return nil, ErrNoOccurrences
+	}
+
+	return occurrences, nil
+}
+
+// Returns a function returning the candidates of the n'th week of the rule.
+func (r Recurrence) weeklyCandidates(start time.Time) func(period int) []time.Time {
+	days := r.Rule.ByDay
+	if len(days) <= 0 {
+		days = []time.Weekday{start.Weekday()}
+	}
+
+	// The monday of the week of the start time.
+	monday := start.AddDate(0, 0, -weekdayIndex(start.Weekday()))
+
+	return func(period int) []time.Time {
+		candidates := make([]time.Time, 0)
+
+		for _, day := range days {
+			date := monday.AddDate(0, 0, period*r.Rule.Interval*7+weekdayIndex(day))
+			candidates = append(candidates, atTimeOfDay(date, start))
+		}
+
+		return candidates
+	}
+}
+
+// Returns a function returning the candidate of the n'th month of the rule.
+// Months without the day of month of the start time are skipped, as per RFC
+// 5545.
+func (r Recurrence) monthlyCandidates(start time.Time) func(period int) []time.Time {
+	return func(period int) []time.Time {
+		date := time.Date(start.Year(), start.Month()+time.Month(period*r.Rule.Interval), 1, 0, 0, 0, 0, start.Location())
+
+		candidate := atTimeOfDay(date.AddDate(0, 0, start.Day()-1), start)
+		if candidate.Day() != start.Day() {
+			return nil
+		}
+
+		return []time.Time{candidate}
+	}
+}
+
+// Returns a copy of the event for the given occurrence. Concerts keep their
+// offset from the start of the occurrence, which for the original event is
+// given by base.
+func (e Event) ForOccurrence(base time.Time, occurrence time.Time) Event {
+	concerts := make([]concert.Concert, 0)
+	for _, c := range e.Concerts {
+		c.From = occurrence.Add(c.From.Sub(base))
+		c.To = occurrence.Add(c.To.Sub(base))
+
+		concerts = append(concerts, c)
+	}
+
+	e.Concerts = concerts
+	e.OccurrenceStart = &occurrence
+
+	return e
+}
+
+// Returns the date at the local time of day of the given time.
+func atTimeOfDay(date time.Time, t time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+}
+
+// Returns the index of the weekday in a week starting on monday.
+func weekdayIndex(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+func parseUntil(s string) (time.Time, error) {
+	if t, err := time.Parse(ruleUntilDateTimeFormat, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(ruleUntilDateFormat, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// A date includes all of the day.
+	return t.Add(24*time.Hour - time.Second), nil
+}
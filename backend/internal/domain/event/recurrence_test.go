@@ -0,0 +1,160 @@
+package event_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/event"
+)
+
+func TestParseRule(t *testing.T) {
+	type test struct {
+		rule    string
+		want    string
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Weekly count": {
+			rule: "FREQ=WEEKLY;COUNT=4",
+			want: "FREQ=WEEKLY;COUNT=4",
+		},
+		"Prefixed and lowercase": {
+			rule: "rrule:freq=monthly;interval=2;until=20260101",
+			want: "FREQ=MONTHLY;INTERVAL=2;UNTIL=20260101T235959Z",
+		},
+		"Days are sorted": {
+			rule: "FREQ=WEEKLY;BYDAY=SU,MO,WE;COUNT=3",
+			want: "FREQ=WEEKLY;COUNT=3;BYDAY=MO,WE,SU",
+		},
+		"Unbounded": {
+			rule:    "FREQ=WEEKLY",
+			wantErr: event.ErrRuleUnbounded,
+		},
+		"Both count and until": {
+			rule:    "FREQ=WEEKLY;COUNT=2;UNTIL=20260101",
+			wantErr: event.ErrRuleUnbounded,
+		},
+		"Daily frequency": {
+			rule:    "FREQ=DAILY;COUNT=2",
+			wantErr: event.ErrInvalidRule,
+		},
+		"Monthly by day": {
+			rule:    "FREQ=MONTHLY;BYDAY=FR;COUNT=2",
+			wantErr: event.ErrRuleByDayUnsupported,
+		},
+		"Too many occurrences": {
+			rule:    "FREQ=WEEKLY;COUNT=1000",
+			wantErr: event.ErrTooManyOccurrences,
+		},
+		"Unknown part": {
+			rule:    "FREQ=WEEKLY;COUNT=2;BYSETPOS=1",
+			wantErr: event.ErrInvalidRule,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule, err := event.ParseRule(tt.rule)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && rule.String() != tt.want {
+				t.Fatalf("got %q, want %q", rule.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	cph, err := time.LoadLocation("Europe/Copenhagen")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	type test struct {
+		rule       string
+		timeZone   string
+		exceptions []string
+		start      time.Time
+		want       []time.Time
+		wantErr    error
+	}
+
+	utc := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2025, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := map[string]test{
+		"Weekly": {
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			start: utc(1, 1, 20),
+			want:  []time.Time{utc(1, 1, 20), utc(1, 8, 20), utc(1, 15, 20)},
+		},
+		"Bi-weekly until": {
+			rule:  "FREQ=WEEKLY;INTERVAL=2;UNTIL=20250129T200000Z",
+			start: utc(1, 1, 20),
+			want:  []time.Time{utc(1, 1, 20), utc(1, 15, 20), utc(1, 29, 20)},
+		},
+		"Weekly by day": {
+			// 1 January 2025 is a wednesday.
+			rule:  "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=3",
+			start: utc(1, 1, 20),
+			want:  []time.Time{utc(1, 3, 20), utc(1, 6, 20), utc(1, 10, 20)},
+		},
+		"Exceptions count towards count": {
+			rule:       "FREQ=WEEKLY;COUNT=3",
+			exceptions: []string{"2025-01-08"},
+			start:      utc(1, 1, 20),
+			want:       []time.Time{utc(1, 1, 20), utc(1, 15, 20)},
+		},
+		"Monthly skips short months": {
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: utc(1, 31, 20),
+			want:  []time.Time{utc(1, 31, 20), utc(3, 31, 20), utc(5, 31, 20)},
+		},
+		"Local time across daylight saving time": {
+			rule:     "FREQ=WEEKLY;COUNT=2",
+			timeZone: "Europe/Copenhagen",
+			start:    time.Date(2025, 3, 27, 20, 0, 0, 0, cph),
+			want:     []time.Time{utc(3, 27, 19), utc(4, 3, 18)},
+		},
+		"Only exceptions": {
+			rule:       "FREQ=WEEKLY;COUNT=1",
+			exceptions: []string{"2025-01-01"},
+			start:      utc(1, 1, 20),
+			wantErr:    event.ErrNoOccurrences,
+		},
+		"Until far in the future": {
+			rule:    "FREQ=WEEKLY;UNTIL=21000101",
+			start:   utc(1, 1, 20),
+			wantErr: event.ErrTooManyOccurrences,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := event.NewRecurrence(
+				event.WithRule(tt.rule),
+				event.WithTimeZone(tt.timeZone),
+				event.WithExceptions(tt.exceptions...),
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.Occurrences(tt.start)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
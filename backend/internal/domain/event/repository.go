@@ -2,15 +2,46 @@ package event
 
 import (
 	"context"
+	"time"
 
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/query"
 )
 
 type Repository interface {
 	Insert(ctx context.Context, e Event) (int64, error)
+
+	// Inserts a recurrence and the events generated for its occurrences,
+	// returning the IDs of the events.
+	InsertRecurring(ctx context.Context, r Recurrence, events []Event) ([]int64, error)
+
 	Update(ctx context.Context, eventID int64, e Event) error
+
+	// Updates the given occurrences of a recurrence by their IDs at once, so
+	// that either all or none of them are updated.
+	UpdateOccurrences(ctx context.Context, events []Event) error
+
 	List(ctx context.Context, q query.ListQuery) (query.ListResult[Event], error)
 	Delete(ctx context.Context, eventID int64) error
 	ByID(ctx context.Context, eventID int64) (Event, error)
-	SetImageURL(ctx context.Context, eventID int64, imageURL string) error
+	SetImage(ctx context.Context, eventID int64, img media.Image) error
+
+	// Returns the images of all events by their ID, including drafts without
+	// concerts, which are left out of listings.
+	Images(ctx context.Context) (map[int64]media.Image, error)
+
+	SetStatus(ctx context.Context, eventID int64, status Status, notice string) error
+
+	// Sets the status and the publish schedule of an event at once, so that an
+	// applied schedule is cleared along with the status change.
+	ApplySchedule(ctx context.Context, eventID int64, status Status, notice string, publishAt, unpublishAt *time.Time) error
+
+	// Returns the IDs of all events, whose publish or unpublish time is at or
+	// before the given time.
+	ScheduleDue(ctx context.Context, now time.Time) ([]int64, error)
+
+	// Returns all bookings matching the query. Only bookings of events of the
+	// booking statuses are included, as others do not occupy their artists or
+	// venue.
+	Bookings(ctx context.Context, q BookingQuery) ([]Booking, error)
 }
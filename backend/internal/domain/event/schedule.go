@@ -0,0 +1,78 @@
+package event
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidPublishSchedule = errors.New("Event unpublish time must be after publish time")
+)
+
+// Sets the times at which the event is automatically published and
+// unpublished. Either may be nil, in which case the event is published or
+// unpublished manually.
+func WithPublishSchedule(publishAt, unpublishAt *time.Time) CfgFunc {
+	return func(e *Event) error {
+		if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+			return ErrInvalidPublishSchedule
+		}
+
+		e.PublishAt = utcTime(publishAt)
+		e.UnpublishAt = utcTime(unpublishAt)
+
+		return nil
+	}
+}
+
+// Returns whether or not the event is visible to the public at the given time.
+// An event is visible if its status is public, and the time is within its
+// publish schedule.
+func (e Event) IsVisible(now time.Time) bool {
+	if !e.Status.IsPublic() {
+		return false
+	}
+
+	if e.PublishAt != nil && now.Before(*e.PublishAt) {
+		return false
+	}
+
+	if e.UnpublishAt != nil && !now.Before(*e.UnpublishAt) {
+		return false
+	}
+
+	return true
+}
+
+// Checks that the event may be shown to the public, as events of public
+// statuses are, and events scheduled for publishing will be.
+func (e Event) ValidatePublic() error {
+	if !e.Status.IsPublic() && e.PublishAt == nil {
+		return nil
+	}
+
+	if err := e.Image.ValidatePublic(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns whether or not the event is due to be published at the given time.
+func (e Event) PublishDue(now time.Time) bool {
+	return e.PublishAt != nil && !now.Before(*e.PublishAt)
+}
+
+// Returns whether or not the event is due to be unpublished at the given time.
+func (e Event) UnpublishDue(now time.Time) bool {
+	return e.UnpublishAt != nil && !now.Before(*e.UnpublishAt)
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,149 @@
+package event_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+func TestIsVisible(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	type test struct {
+		status      event.Status
+		publishAt   *time.Time
+		unpublishAt *time.Time
+		want        bool
+	}
+
+	tests := map[string]test{
+		"Scheduled without schedule": {
+			status: event.StatusScheduled,
+			want:   true,
+		},
+		"Draft without schedule": {
+			status: event.StatusDraft,
+			want:   false,
+		},
+		"Cancelled without schedule": {
+			status: event.StatusCancelled,
+			want:   true,
+		},
+		"Not yet published": {
+			status:    event.StatusScheduled,
+			publishAt: &after,
+			want:      false,
+		},
+		"Published": {
+			status:    event.StatusScheduled,
+			publishAt: &before,
+			want:      true,
+		},
+		"Published at exactly now": {
+			status:    event.StatusScheduled,
+			publishAt: &now,
+			want:      true,
+		},
+		"Draft past publish time": {
+			status:    event.StatusDraft,
+			publishAt: &before,
+			want:      false,
+		},
+		"Unpublished": {
+			status:      event.StatusScheduled,
+			unpublishAt: &before,
+			want:        false,
+		},
+		"Unpublished at exactly now": {
+			status:      event.StatusScheduled,
+			unpublishAt: &now,
+			want:        false,
+		},
+		"Within schedule": {
+			status:      event.StatusPostponed,
+			publishAt:   &before,
+			unpublishAt: &after,
+			want:        true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := event.Event{
+				Status:      tt.status,
+				PublishAt:   tt.publishAt,
+				UnpublishAt: tt.unpublishAt,
+			}
+
+			if got := e.IsVisible(now); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPublishSchedule(t *testing.T) {
+	publishAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	unpublishAt := publishAt.Add(24 * time.Hour)
+
+	type test struct {
+		publishAt   *time.Time
+		unpublishAt *time.Time
+		wantErr     error
+	}
+
+	tests := map[string]test{
+		"No schedule":       {},
+		"Publish only":      {publishAt: &publishAt},
+		"Unpublish only":    {unpublishAt: &unpublishAt},
+		"Valid schedule":    {publishAt: &publishAt, unpublishAt: &unpublishAt},
+		"Equal times":       {publishAt: &publishAt, unpublishAt: &publishAt, wantErr: event.ErrInvalidPublishSchedule},
+		"Reversed schedule": {publishAt: &unpublishAt, unpublishAt: &publishAt, wantErr: event.ErrInvalidPublishSchedule},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := event.NewEvent(event.WithPublishSchedule(tt.publishAt, tt.unpublishAt))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePublic(t *testing.T) {
+	publishAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	described := media.Image{URL: "https://a/b.jpeg", Attribution: media.Attribution{AltText: "A crowd"}}
+	undescribed := media.Image{URL: "https://a/b.jpeg"}
+
+	type test struct {
+		status    event.Status
+		publishAt *time.Time
+		img       media.Image
+		wantErr   error
+	}
+
+	tests := map[string]test{
+		"Public with alt text":      {status: event.StatusScheduled, img: described},
+		"Draft without alt text":    {status: event.StatusDraft, img: undescribed},
+		"Public without alt text":   {status: event.StatusCancelled, img: undescribed, wantErr: media.ErrMissingAltText},
+		"Scheduled for publishing":  {status: event.StatusDraft, publishAt: &publishAt, img: undescribed, wantErr: media.ErrMissingAltText},
+		"Archived without alt text": {status: event.StatusArchived, img: undescribed},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := event.Event{Status: tt.status, PublishAt: tt.publishAt, Image: tt.img}
+
+			if err := e.ValidatePublic(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package event
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidStatus        = errors.New("Event status must be one of draft, scheduled, postponed, cancelled or archived")
+	ErrInvalidInitialStatus = errors.New("Event must be created as either draft or scheduled")
+	ErrStatusTransition     = errors.New("Event status transition is not allowed")
+	ErrStatusNoticeRequired = errors.New("Event status notice must not be empty when postponing or cancelling")
+)
+
+type Status string
+
+const (
+	// The event is being prepared and must never be shown publicly.
+	StatusDraft = Status("draft")
+
+	// The event is announced and takes place as planned.
+	StatusScheduled = Status("scheduled")
+
+	// The event has been moved. It stays visible, with a notice describing
+	// the change.
+	StatusPostponed = Status("postponed")
+
+	// The event has been called off. It stays visible, with a notice
+	// describing the cancellation.
+	StatusCancelled = Status("cancelled")
+
+	// The event is no longer relevant and is hidden from public listings.
+	StatusArchived = Status("archived")
+)
+
+// The statuses an event may transition to, given its current status.
+var transitions = map[Status][]Status{
+	StatusDraft:     {StatusScheduled, StatusArchived},
+	StatusScheduled: {StatusDraft, StatusPostponed, StatusCancelled, StatusArchived},
+	StatusPostponed: {StatusScheduled, StatusCancelled, StatusArchived},
+	StatusCancelled: {StatusScheduled, StatusArchived},
+	StatusArchived:  {StatusDraft},
+}
+
+// Returns all statuses, which are visible to the public.
+func PublicStatuses() []Status {
+	return []Status{StatusScheduled, StatusPostponed, StatusCancelled}
+}
+
+// Parses a status string, returning an error if it is not a known status.
+func ParseStatus(s string) (Status, error) {
+	status := Status(strings.ToLower(strings.TrimSpace(s)))
+	if !status.Valid() {
+		return "", ErrInvalidStatus
+	}
+
+	return status, nil
+}
+
+// Returns whether or not the status is a known status.
+func (s Status) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// Returns whether or not events with the status are visible to the public.
+func (s Status) IsPublic() bool {
+	return slices.Contains(PublicStatuses(), s)
+}
+
+// Returns whether or not an event may be created with the status.
+func (s Status) IsInitial() bool {
+	return s == StatusDraft || s == StatusScheduled
+}
+
+// Returns whether or not the status must be accompanied by a notice to the
+// audience.
+func (s Status) RequiresNotice() bool {
+	return s == StatusPostponed || s == StatusCancelled
+}
+
+// Returns whether or not the status may transition to the target status.
+func (s Status) CanTransitionTo(target Status) bool {
+	return slices.Contains(transitions[s], target)
+}
+
+// Transitions the event to the given status. The notice is required when
+// postponing or cancelling, and is cleared for all other statuses.
+func (e *Event) TransitionTo(status Status, notice string) error {
+	if !status.Valid() {
+		return ErrInvalidStatus
+	}
+
+	if !e.Status.CanTransitionTo(status) {
+		return ErrStatusTransition
+	}
+
+	notice = strings.TrimSpace(notice)
+
+	if !status.RequiresNotice() {
+		notice = ""
+	}
+
+	if status.RequiresNotice() && notice == "" {
+		return ErrStatusNoticeRequired
+	}
+
+	e.Status = status
+	e.StatusNotice = notice
+
+	return nil
+}
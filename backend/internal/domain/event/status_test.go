@@ -0,0 +1,124 @@
+package event_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/event"
+)
+
+func TestTransitionTo(t *testing.T) {
+	type test struct {
+		from       event.Status
+		fromNotice string
+		to         event.Status
+		notice     string
+		wantNotice string
+		wantErr    error
+	}
+
+	tests := map[string]test{
+		"Publish draft": {
+			from: event.StatusDraft,
+			to:   event.StatusScheduled,
+		},
+		"Postpone with notice": {
+			from:       event.StatusScheduled,
+			to:         event.StatusPostponed,
+			notice:     "  Moved to next week  ",
+			wantNotice: "Moved to next week",
+		},
+		"Cancel without notice": {
+			from:    event.StatusScheduled,
+			to:      event.StatusCancelled,
+			notice:  " ",
+			wantErr: event.ErrStatusNoticeRequired,
+		},
+		"Reschedule clears notice": {
+			from:       event.StatusPostponed,
+			fromNotice: "Moved to next week",
+			to:         event.StatusScheduled,
+			notice:     "Ignored",
+		},
+		"Cancel draft": {
+			from:    event.StatusDraft,
+			to:      event.StatusCancelled,
+			notice:  "Called off",
+			wantErr: event.ErrStatusTransition,
+		},
+		"Postpone archived": {
+			from:    event.StatusArchived,
+			to:      event.StatusPostponed,
+			notice:  "Moved",
+			wantErr: event.ErrStatusTransition,
+		},
+		"Restore archived as draft": {
+			from: event.StatusArchived,
+			to:   event.StatusDraft,
+		},
+		"Same status": {
+			from:    event.StatusScheduled,
+			to:      event.StatusScheduled,
+			wantErr: event.ErrStatusTransition,
+		},
+		"Unknown status": {
+			from:    event.StatusScheduled,
+			to:      event.Status("deleted"),
+			wantErr: event.ErrInvalidStatus,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := event.Event{Status: tt.from, StatusNotice: tt.fromNotice}
+
+			err := e.TransitionTo(tt.to, tt.notice)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				if e.Status != tt.from || e.StatusNotice != tt.fromNotice {
+					t.Fatalf("event was modified on failed transition: %+v", e)
+				}
+				return
+			}
+
+			if e.Status != tt.to {
+				t.Fatalf("got status %q, want %q", e.Status, tt.to)
+			}
+
+			if e.StatusNotice != tt.wantNotice {
+				t.Fatalf("got notice %q, want %q", e.StatusNotice, tt.wantNotice)
+			}
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	type test struct {
+		s       string
+		want    event.Status
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Lowercase":     {s: "cancelled", want: event.StatusCancelled},
+		"Mixed case":    {s: " Postponed ", want: event.StatusPostponed},
+		"Empty":         {s: "", wantErr: event.ErrInvalidStatus},
+		"Unknown value": {s: "public", wantErr: event.ErrInvalidStatus},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := event.ParseStatus(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
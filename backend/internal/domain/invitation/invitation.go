@@ -0,0 +1,81 @@
+package invitation
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+	"github.com/mattismoel/konnekt/internal/domain/team"
+)
+
+var (
+	ErrNotFound     = errors.New("Invitation not found")
+	ErrInvalidToken = errors.New("Invitation is invalid or has expired")
+	ErrEmailInvalid = errors.New("Invitation email must be valid")
+	ErrNoTeams      = errors.New("Invitation must assign at least one team")
+)
+
+// A token given to an invitee by email, with which the invitation may be
+// accepted once.
+type Token string
+
+// An invitation of an email to join as an active member of the given teams,
+// without registering and awaiting approval.
+type Invitation struct {
+	ID        int64               `json:"id"`
+	Email     string              `json:"email"`
+	Teams     team.TeamCollection `json:"teams"`
+	InvitedBy int64               `json:"invitedBy"`
+	CreatedAt time.Time           `json:"createdAt"`
+	ExpiresAt time.Time           `json:"expiresAt"`
+
+	// The hash of the token of the invitation, by which it is looked up.
+	TokenHash string `json:"-"`
+}
+
+func NewInvitation(token Token, email string, teams team.TeamCollection, invitedBy int64, lifetime time.Duration) (Invitation, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return Invitation{}, ErrEmailInvalid
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return Invitation{}, ErrEmailInvalid
+	}
+
+	if len(teams) == 0 {
+		return Invitation{}, ErrNoTeams
+	}
+
+	now := time.Now()
+
+	return Invitation{
+		Email:     addr.Address,
+		Teams:     teams,
+		InvitedBy: invitedBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(lifetime),
+		TokenHash: token.Hash(),
+	}, nil
+}
+
+func NewToken() (Token, error) {
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	return Token(token), nil
+}
+
+func (token Token) Hash() string {
+	return auth.HashToken(string(token))
+}
+
+// Returns whether or not the invitation has passed its expiry date.
+func (i Invitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
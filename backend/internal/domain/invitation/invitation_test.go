@@ -0,0 +1,112 @@
+package invitation_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/invitation"
+	"github.com/mattismoel/konnekt/internal/domain/team"
+)
+
+func TestNewInvitation(t *testing.T) {
+	type test struct {
+		email     string
+		teams     team.TeamCollection
+		wantEmail string
+		wantErr   error
+	}
+
+	teams := team.TeamCollection{{ID: 1, Name: "member"}}
+
+	tests := map[string]test{
+		"Valid invitation": {
+			email:     "a@example.com",
+			teams:     teams,
+			wantEmail: "a@example.com",
+		},
+		"Named address": {
+			email:     " Anna <a@example.com> ",
+			teams:     teams,
+			wantEmail: "a@example.com",
+		},
+		"Empty email": {
+			email:   "  ",
+			teams:   teams,
+			wantErr: invitation.ErrEmailInvalid,
+		},
+		"Invalid email": {
+			email:   "a@",
+			teams:   teams,
+			wantErr: invitation.ErrEmailInvalid,
+		},
+		"No teams": {
+			email:   "a@example.com",
+			wantErr: invitation.ErrNoTeams,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := invitation.NewInvitation("token", tt.email, tt.teams, 1, time.Hour)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got.Email != tt.wantEmail {
+				t.Fatalf("got email %q, want %q", got.Email, tt.wantEmail)
+			}
+		})
+	}
+}
+
+func TestTokenHash(t *testing.T) {
+	token, err := invitation.NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := invitation.NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token == other {
+		t.Fatalf("got equal tokens %q, want distinct tokens", token)
+	}
+
+	if token.Hash() == string(token) {
+		t.Fatalf("got hash equal to token, want hashed token")
+	}
+
+	if token.Hash() == other.Hash() {
+		t.Fatalf("got equal hashes of distinct tokens, want distinct hashes")
+	}
+}
+
+func TestInvitationIsExpired(t *testing.T) {
+	type test struct {
+		lifetime time.Duration
+		want     bool
+	}
+
+	tests := map[string]test{
+		"Unexpired": {lifetime: time.Hour, want: false},
+		"Expired":   {lifetime: -time.Minute, want: true},
+	}
+
+	teams := team.TeamCollection{{ID: 1, Name: "member"}}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			i, err := invitation.NewInvitation("token", "a@example.com", teams, 1, tt.lifetime)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := i.IsExpired(); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package invitation
+
+import (
+	"context"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/member"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+type Repository interface {
+	List(ctx context.Context, q query.ListQuery) (query.ListResult[Invitation], error)
+	ByID(ctx context.Context, invitationID int64) (Invitation, error)
+	ByTokenHash(ctx context.Context, tokenHash string) (Invitation, error)
+	Insert(ctx context.Context, i Invitation) (int64, error)
+	Delete(ctx context.Context, invitationID int64) error
+	DeleteByEmail(ctx context.Context, email string) error
+
+	// Accepts the invitation by inserting the given member as an active member
+	// with a verified email, assigned the teams of the invitation, and deleting
+	// the invitation, all at once. Returns the ID of the inserted member.
+	Accept(ctx context.Context, invitationID int64, m member.Member, verifiedAt time.Time) (int64, error)
+}
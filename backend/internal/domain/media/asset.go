@@ -0,0 +1,190 @@
+package media
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const MAX_TAGS = 20
+
+var (
+	ErrAssetNoExist      = errors.New("Media asset does not exist")
+	ErrInvalidAssetID    = errors.New("Media asset ID must be a positive integer")
+	ErrEmptyTitle        = errors.New("Media asset title must not be empty")
+	ErrInvalidTag        = errors.New("Media asset tags must only contain lowercase letters, digits and dashes")
+	ErrTooManyTags       = errors.New("Media asset must not have more than 20 tags")
+	ErrInvalidDimensions = errors.New("Media asset dimensions must be positive")
+	ErrInvalidAssetImage = errors.New("Media asset image must be valid")
+	ErrAssetInUse        = errors.New("Media asset must not be in use to be deleted")
+)
+
+var tagRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// An uploaded image of the media library. Assets may be used by any number of
+// artists, events and landing images, which then share the variants of the
+// asset rather than uploading the image again.
+type Asset struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+
+	// A description of the image for readers unable to see it.
+	AltText string `json:"altText"`
+
+	// The photographer or other owner of the image.
+	Credit string `json:"credit"`
+
+	Tags []string `json:"tags"`
+
+	// The dimensions of the uploaded image, as displayed upright.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	Image     Image     `json:"image"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// An entity using a media asset.
+type Reference struct {
+	// The kind of the entity, such as "artist".
+	Kind string `json:"kind"`
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type AssetCfgFunc func(a *Asset) error
+
+func NewAsset(cfgs ...AssetCfgFunc) (*Asset, error) {
+	a := &Asset{Tags: make([]string, 0)}
+
+	if err := a.WithCfgs(cfgs...); err != nil {
+		return &Asset{}, err
+	}
+
+	return a, nil
+}
+
+func (a *Asset) WithCfgs(cfgs ...AssetCfgFunc) error {
+	for _, cfg := range cfgs {
+		if err := cfg(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WithAssetID(id int64) AssetCfgFunc {
+	return func(a *Asset) error {
+		if id <= 0 {
+			return ErrInvalidAssetID
+		}
+
+		a.ID = id
+		return nil
+	}
+}
+
+func WithTitle(title string) AssetCfgFunc {
+	return func(a *Asset) error {
+		title = strings.TrimSpace(title)
+
+		if title == "" {
+			return ErrEmptyTitle
+		}
+
+		a.Title = title
+		return nil
+	}
+}
+
+func WithAltText(altText string) AssetCfgFunc {
+	return func(a *Asset) error {
+		a.AltText = strings.TrimSpace(altText)
+		return nil
+	}
+}
+
+func WithCredit(credit string) AssetCfgFunc {
+	return func(a *Asset) error {
+		a.Credit = strings.TrimSpace(credit)
+		return nil
+	}
+}
+
+// Sets the tags of the asset, which are lowercased, sorted and deduplicated.
+func WithTags(tags ...string) AssetCfgFunc {
+	return func(a *Asset) error {
+		normalized := make([]string, 0, len(tags))
+
+		for _, tag := range tags {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" {
+				continue
+			}
+
+			if !tagRegex.MatchString(tag) {
+				return ErrInvalidTag
+			}
+
+			normalized = append(normalized, tag)
+		}
+
+		slices.Sort(normalized)
+		normalized = slices.Compact(normalized)
+
+		if len(normalized) > MAX_TAGS {
+			return ErrTooManyTags
+		}
+
+		a.Tags = normalized
+		return nil
+	}
+}
+
+func WithDimensions(width, height int) AssetCfgFunc {
+	return func(a *Asset) error {
+		if width <= 0 || height <= 0 {
+			return ErrInvalidDimensions
+		}
+
+		a.Width, a.Height = width, height
+		return nil
+	}
+}
+
+func WithAssetImage(img Image) AssetCfgFunc {
+	return func(a *Asset) error {
+		if err := img.Validate(); err != nil {
+			return ErrInvalidAssetImage
+		}
+
+		a.Image = img
+		return nil
+	}
+}
+
+func WithCreatedAt(t time.Time) AssetCfgFunc {
+	return func(a *Asset) error {
+		a.CreatedAt = t.UTC()
+		return nil
+	}
+}
+
+// Returns the image of the asset, as used by an entity. The image shares the
+// variants of the asset, and is cropped by the entity as needed.
+func (a Asset) SharedImage() Image {
+	return Image{
+		URL:      a.Image.URL,
+		Variants: slices.Clone(a.Image.Variants),
+		BlurHash: a.Image.BlurHash,
+		Color:    a.Image.Color,
+		Attribution: Attribution{
+			AltText: a.AltText,
+			Credit:  a.Credit,
+		},
+		MediaID: a.ID,
+	}
+}
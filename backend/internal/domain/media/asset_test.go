@@ -0,0 +1,92 @@
+package media_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+func TestNewAsset(t *testing.T) {
+	type test struct {
+		cfgs     []media.AssetCfgFunc
+		wantTags []string
+		wantErr  error
+	}
+
+	img := media.Image{URL: "https://a/b.jpeg"}
+
+	manyTags := make([]string, 0)
+	for i := range media.MAX_TAGS + 1 {
+		manyTags = append(manyTags, fmt.Sprintf("tag-%d", i))
+	}
+
+	tests := map[string]test{
+		"Valid asset": {
+			cfgs:     []media.AssetCfgFunc{media.WithTitle("Poster"), media.WithDimensions(640, 480), media.WithAssetImage(img)},
+			wantTags: []string{},
+		},
+		"Normalized tags": {
+			cfgs:     []media.AssetCfgFunc{media.WithTags(" Live ", "festival", "live", "", "Festival")},
+			wantTags: []string{"festival", "live"},
+		},
+		"Invalid tag":        {cfgs: []media.AssetCfgFunc{media.WithTags("live music")}, wantErr: media.ErrInvalidTag},
+		"Too many tags":      {cfgs: []media.AssetCfgFunc{media.WithTags(manyTags...)}, wantErr: media.ErrTooManyTags},
+		"Empty title":        {cfgs: []media.AssetCfgFunc{media.WithTitle("  ")}, wantErr: media.ErrEmptyTitle},
+		"Invalid ID":         {cfgs: []media.AssetCfgFunc{media.WithAssetID(0)}, wantErr: media.ErrInvalidAssetID},
+		"Invalid dimensions": {cfgs: []media.AssetCfgFunc{media.WithDimensions(640, 0)}, wantErr: media.ErrInvalidDimensions},
+		"Invalid image":      {cfgs: []media.AssetCfgFunc{media.WithAssetImage(media.Image{URL: "b.jpeg"})}, wantErr: media.ErrInvalidAssetImage},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := media.NewAsset(tt.cfgs...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !slices.Equal(a.Tags, tt.wantTags) {
+				t.Fatalf("got tags %v, want %v", a.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestOwnedURLs(t *testing.T) {
+	type test struct {
+		img  media.Image
+		want []string
+	}
+
+	variants := []media.Variant{{Width: 320, URL: "https://a/1-320w.jpeg"}, {Width: 640, URL: "https://a/1-640w.jpeg"}}
+	crops := []media.AspectCrop{{Aspect: media.AspectSquare, Variants: []media.Variant{{Width: 320, URL: "https://a/2-1x1-320w.jpeg"}}}}
+
+	tests := map[string]test{
+		"Own image": {
+			img:  media.Image{URL: "https://a/1-640w.jpeg", Variants: variants, Crops: crops},
+			want: []string{"https://a/1-640w.jpeg", "https://a/1-320w.jpeg", "https://a/2-1x1-320w.jpeg"},
+		},
+		"Asset image": {
+			img:  media.Image{URL: "https://a/1-640w.jpeg", Variants: variants, Crops: crops, MediaID: 4},
+			want: []string{"https://a/2-1x1-320w.jpeg"},
+		},
+		"Uncropped asset image": {
+			img:  media.Image{URL: "https://a/1-640w.jpeg", Variants: variants, MediaID: 4},
+			want: []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.img.OwnedURLs(); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
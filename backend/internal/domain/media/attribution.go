@@ -0,0 +1,69 @@
+package media
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrMissingAltText = errors.New("Image alt text must not be empty, as the image is shown to the public")
+	ErrInvalidLicense = errors.New("Image licence must be a valid URL")
+)
+
+// Describes an image for readers unable to see it, and credits its owner.
+type Attribution struct {
+	AltText string `json:"altText"`
+
+	// The photographer or other owner of the image.
+	Credit string `json:"credit"`
+
+	// The URL of the licence, under which the image is used, if any.
+	License string `json:"license,omitempty"`
+}
+
+func NewAttribution(altText, credit, license string) (Attribution, error) {
+	a := Attribution{
+		AltText: strings.TrimSpace(altText),
+		Credit:  strings.TrimSpace(credit),
+		License: strings.TrimSpace(license),
+	}
+
+	if err := a.Validate(); err != nil {
+		return Attribution{}, err
+	}
+
+	return a, nil
+}
+
+func (a Attribution) Validate() error {
+	if a.License == "" {
+		return nil
+	}
+
+	if _, err := url.ParseRequestURI(a.License); err != nil {
+		return ErrInvalidLicense
+	}
+
+	return nil
+}
+
+// Checks that the attributed image may be shown to the public, which requires
+// it to be described by alt text.
+func (a Attribution) ValidatePublic() error {
+	if strings.TrimSpace(a.AltText) == "" {
+		return ErrMissingAltText
+	}
+
+	return nil
+}
+
+// Checks that the image may be shown to the public. Empty images need no
+// description.
+func (img Image) ValidatePublic() error {
+	if img.URL == "" {
+		return nil
+	}
+
+	return img.Attribution.ValidatePublic()
+}
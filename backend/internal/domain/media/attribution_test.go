@@ -0,0 +1,64 @@
+package media_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+func TestNewAttribution(t *testing.T) {
+	type test struct {
+		altText string
+		credit  string
+		license string
+		want    media.Attribution
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Empty": {},
+		"Trimmed": {
+			altText: " A crowd at the stage ",
+			credit:  " Jane Doe ",
+			license: " https://creativecommons.org/licenses/by/4.0/ ",
+			want:    media.Attribution{AltText: "A crowd at the stage", Credit: "Jane Doe", License: "https://creativecommons.org/licenses/by/4.0/"},
+		},
+		"Licence without URL": {license: "CC BY 4.0", wantErr: media.ErrInvalidLicense},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := media.NewAttribution(tt.altText, tt.credit, tt.license)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePublic(t *testing.T) {
+	type test struct {
+		img     media.Image
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Described image": {img: media.Image{URL: "https://a/b.jpeg", Attribution: media.Attribution{AltText: "A crowd"}}},
+		"No image":        {img: media.Image{}},
+		"No alt text":     {img: media.Image{URL: "https://a/b.jpeg"}, wantErr: media.ErrMissingAltText},
+		"Blank alt text":  {img: media.Image{URL: "https://a/b.jpeg", Attribution: media.Attribution{AltText: "  "}}, wantErr: media.ErrMissingAltText},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.img.ValidatePublic(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package media
+
+import (
+	"errors"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidAspect     = errors.New("Aspect ratio must be of format <width>:<height>")
+	ErrInvalidFocalPoint = errors.New("Focal point must lie within the image")
+	ErrInvalidCrop       = errors.New("Crop must be a non-empty rectangle within the image")
+)
+
+// The aspect ratio of a crop, of format <width>:<height>.
+type Aspect string
+
+const (
+	AspectSquare   = Aspect("1:1")
+	AspectWide     = Aspect("16:9")
+	AspectPortrait = Aspect("4:5")
+)
+
+// Returns the width and height of the aspect ratio.
+func (a Aspect) Ratio() (int, int, error) {
+	w, h, ok := strings.Cut(string(a), ":")
+	if !ok {
+		return 0, 0, ErrInvalidAspect
+	}
+
+	width, err := strconv.Atoi(w)
+	if err != nil || width <= 0 {
+		return 0, 0, ErrInvalidAspect
+	}
+
+	height, err := strconv.Atoi(h)
+	if err != nil || height <= 0 {
+		return 0, 0, ErrInvalidAspect
+	}
+
+	return width, height, nil
+}
+
+// A point of an image, relative to its width and height, such that 0, 0 is
+// the top left corner and 1, 1 is the bottom right corner.
+type FocalPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// A rectangle of an image, relative to its width and height.
+type Crop struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Describes how an image is cropped to fixed aspect ratios. Crops are taken
+// from within the crop rectangle, or the whole image if nil, and are centred
+// on the focal point as far as the rectangle allows. The centre of the
+// rectangle is used, if the focal point is nil.
+type Framing struct {
+	FocalPoint *FocalPoint `json:"focalPoint,omitempty"`
+	Crop       *Crop       `json:"crop,omitempty"`
+}
+
+func (f Framing) Validate() error {
+	if p := f.FocalPoint; p != nil {
+		if p.X < 0 || p.X > 1 || p.Y < 0 || p.Y > 1 {
+			return ErrInvalidFocalPoint
+		}
+	}
+
+	if c := f.Crop; c != nil {
+		if c.X < 0 || c.Y < 0 || c.Width <= 0 || c.Height <= 0 || c.X+c.Width > 1 || c.Y+c.Height > 1 {
+			return ErrInvalidCrop
+		}
+	}
+
+	return nil
+}
+
+func (f Framing) Equal(other Framing) bool {
+	if (f.FocalPoint == nil) != (other.FocalPoint == nil) || (f.Crop == nil) != (other.Crop == nil) {
+		return false
+	}
+
+	if f.FocalPoint != nil && *f.FocalPoint != *other.FocalPoint {
+		return false
+	}
+
+	if f.Crop != nil && *f.Crop != *other.Crop {
+		return false
+	}
+
+	return true
+}
+
+// Returns the largest rectangle of the given aspect ratio, which is framed as
+// described, within an image of the given width and height.
+func (f Framing) Rect(width, height int, aspect Aspect) (image.Rectangle, error) {
+	if err := f.Validate(); err != nil {
+		return image.Rectangle{}, err
+	}
+
+	aspectWidth, aspectHeight, err := aspect.Ratio()
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+
+	bounds := image.Rect(0, 0, width, height)
+	if bounds.Empty() {
+		return image.Rectangle{}, ErrInvalidCrop
+	}
+
+	region := bounds
+	if c := f.Crop; c != nil {
+		region = image.Rect(
+			int(math.Round(c.X*float64(width))),
+			int(math.Round(c.Y*float64(height))),
+			int(math.Round((c.X+c.Width)*float64(width))),
+			int(math.Round((c.Y+c.Height)*float64(height))),
+		).Intersect(bounds)
+
+		if region.Empty() {
+			return image.Rectangle{}, ErrInvalidCrop
+		}
+	}
+
+	w, h := region.Dx(), region.Dx()*aspectHeight/aspectWidth
+	if h > region.Dy() {
+		w, h = region.Dy()*aspectWidth/aspectHeight, region.Dy()
+	}
+
+	w, h = max(w, 1), max(h, 1)
+
+	centerX := float64(region.Min.X+region.Max.X) / 2
+	centerY := float64(region.Min.Y+region.Max.Y) / 2
+
+	if p := f.FocalPoint; p != nil {
+		centerX, centerY = p.X*float64(width), p.Y*float64(height)
+	}
+
+	x := clamp(int(math.Round(centerX-float64(w)/2)), region.Min.X, region.Max.X-w)
+	y := clamp(int(math.Round(centerY-float64(h)/2)), region.Min.Y, region.Max.Y-h)
+
+	return image.Rect(x, y, x+w, y+h), nil
+}
+
+// A crop of an image at a fixed aspect ratio, along with the framing it was
+// cropped by, and its responsive variants, ordered by ascending width.
+type AspectCrop struct {
+	Aspect Aspect `json:"aspect"`
+	Framing
+	Variants []Variant `json:"variants"`
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
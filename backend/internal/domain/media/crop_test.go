@@ -0,0 +1,147 @@
+package media_test
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+func TestFramingRect(t *testing.T) {
+	type test struct {
+		framing media.Framing
+		width   int
+		height  int
+		aspect  media.Aspect
+		want    image.Rectangle
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Centred square": {
+			width: 1000, height: 500, aspect: media.AspectSquare,
+			want: image.Rect(250, 0, 750, 500),
+		},
+		"Square at left focal point": {
+			framing: media.Framing{FocalPoint: &media.FocalPoint{X: 0, Y: 0.5}},
+			width:   1000, height: 500, aspect: media.AspectSquare,
+			want: image.Rect(0, 0, 500, 500),
+		},
+		"Square at right focal point": {
+			framing: media.Framing{FocalPoint: &media.FocalPoint{X: 1, Y: 0.5}},
+			width:   1000, height: 500, aspect: media.AspectSquare,
+			want: image.Rect(500, 0, 1000, 500),
+		},
+		"Wide limited by height": {
+			width: 1000, height: 500, aspect: media.AspectWide,
+			want: image.Rect(56, 0, 944, 500),
+		},
+		"Wide limited by width": {
+			framing: media.Framing{FocalPoint: &media.FocalPoint{X: 0.5, Y: 0.25}},
+			width:   800, height: 1000, aspect: media.AspectWide,
+			want: image.Rect(0, 25, 800, 475),
+		},
+		"Portrait near focal point": {
+			framing: media.Framing{FocalPoint: &media.FocalPoint{X: 0.2, Y: 0.5}},
+			width:   1000, height: 500, aspect: media.AspectPortrait,
+			want: image.Rect(0, 0, 400, 500),
+		},
+		"Within crop": {
+			framing: media.Framing{Crop: &media.Crop{X: 0.5, Y: 0, Width: 0.5, Height: 1}},
+			width:   1000, height: 500, aspect: media.AspectSquare,
+			want: image.Rect(500, 0, 1000, 500),
+		},
+		"Focal point outside crop": {
+			framing: media.Framing{
+				FocalPoint: &media.FocalPoint{X: 0, Y: 0},
+				Crop:       &media.Crop{X: 0.5, Y: 0, Width: 0.5, Height: 1},
+			},
+			width: 1000, height: 500, aspect: media.AspectSquare,
+			want: image.Rect(500, 0, 1000, 500),
+		},
+		"Focal point outside image": {
+			framing: media.Framing{FocalPoint: &media.FocalPoint{X: 1.5, Y: 0.5}},
+			width:   1000, height: 500, aspect: media.AspectSquare,
+			wantErr: media.ErrInvalidFocalPoint,
+		},
+		"Empty crop": {
+			framing: media.Framing{Crop: &media.Crop{X: 0, Y: 0, Width: 0, Height: 1}},
+			width:   1000, height: 500, aspect: media.AspectSquare,
+			wantErr: media.ErrInvalidCrop,
+		},
+		"Crop outside image": {
+			framing: media.Framing{Crop: &media.Crop{X: 0.6, Y: 0, Width: 0.5, Height: 1}},
+			width:   1000, height: 500, aspect: media.AspectSquare,
+			wantErr: media.ErrInvalidCrop,
+		},
+		"Invalid aspect": {
+			width: 1000, height: 500, aspect: media.Aspect("16/9"),
+			wantErr: media.ErrInvalidAspect,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.framing.Rect(tt.width, tt.height, tt.aspect)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageCropped(t *testing.T) {
+	type test struct {
+		img  media.Image
+		want bool
+	}
+
+	focal := media.Framing{FocalPoint: &media.FocalPoint{X: 0.3, Y: 0.6}}
+	aspects := []media.Aspect{media.AspectSquare, media.AspectWide}
+
+	tests := map[string]test{
+		"No crops": {img: media.Image{URL: "https://a/b.jpeg"}, want: false},
+		"All crops framed": {
+			img: media.Image{
+				URL:     "https://a/b.jpeg",
+				Framing: focal,
+				Crops: []media.AspectCrop{
+					{Aspect: media.AspectSquare, Framing: focal},
+					{Aspect: media.AspectWide, Framing: focal},
+				},
+			},
+			want: true,
+		},
+		"Missing aspect": {
+			img: media.Image{
+				URL:     "https://a/b.jpeg",
+				Framing: focal,
+				Crops:   []media.AspectCrop{{Aspect: media.AspectSquare, Framing: focal}},
+			},
+			want: false,
+		},
+		"Framing changed": {
+			img: media.Image{
+				URL: "https://a/b.jpeg",
+				Crops: []media.AspectCrop{
+					{Aspect: media.AspectSquare, Framing: focal},
+					{Aspect: media.AspectWide, Framing: focal},
+				},
+			},
+			want: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.img.Cropped(aspects); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
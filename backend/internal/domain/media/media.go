@@ -0,0 +1,186 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidURL      = errors.New("Image URL must be valid")
+	ErrInvalidVariant  = errors.New("Image variants must have a positive width and a valid URL")
+	ErrInvalidBlurHash = errors.New("Image BlurHash must be valid")
+	ErrInvalidColor    = errors.New("Image colour must be of format #rrggbb")
+	ErrForeignImage    = errors.New("Image must have been uploaded for its purpose")
+)
+
+var (
+	blurHashRegex = regexp.MustCompile(`^[0-9A-Za-z#$%*+,\-.:;=?@\[\]^_{|}~]{6,}$`)
+	colorRegex    = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+)
+
+// A single width of an image.
+type Variant struct {
+	Width int    `json:"width"`
+	URL   string `json:"url"`
+}
+
+// An image along with its responsive variants, ordered by ascending width. The
+// URL refers to the widest variant, and is used wherever a single image is
+// needed. Images uploaded before variants were introduced have no variants.
+type Image struct {
+	URL      string    `json:"url"`
+	Variants []Variant `json:"variants"`
+
+	// A BlurHash and the dominant colour of the image, of format #rrggbb,
+	// shown while the image loads.
+	BlurHash string `json:"blurHash,omitempty"`
+	Color    string `json:"color,omitempty"`
+
+	Attribution
+
+	// How the image is cropped to the aspect ratios of its profile, and the
+	// resulting crops. Only images of some purposes are cropped.
+	Framing
+	Crops []AspectCrop `json:"crops,omitempty"`
+
+	// The media asset, whose variants the image shares, if any. The variants
+	// belong to the asset, and only the crops belong to the image.
+	MediaID int64 `json:"mediaId,omitempty"`
+}
+
+// Creates an image of the given URL and variants, ordering the variants by
+// ascending width.
+func NewImage(u string, variants ...Variant) (Image, error) {
+	img := Image{
+		URL:      u,
+		Variants: slices.Clone(variants),
+	}
+
+	slices.SortFunc(img.Variants, func(a, b Variant) int {
+		return a.Width - b.Width
+	})
+
+	if err := img.Validate(); err != nil {
+		return Image{}, err
+	}
+
+	return img, nil
+}
+
+// Checks that the URL and all variants of the image are valid.
+func (img Image) Validate() error {
+	if _, err := url.ParseRequestURI(img.URL); err != nil {
+		return ErrInvalidURL
+	}
+
+	for _, v := range img.Variants {
+		if v.Width <= 0 {
+			return ErrInvalidVariant
+		}
+
+		if _, err := url.ParseRequestURI(v.URL); err != nil {
+			return ErrInvalidVariant
+		}
+	}
+
+	if img.BlurHash != "" && !blurHashRegex.MatchString(img.BlurHash) {
+		return ErrInvalidBlurHash
+	}
+
+	if img.Color != "" && !colorRegex.MatchString(img.Color) {
+		return ErrInvalidColor
+	}
+
+	if err := img.Attribution.Validate(); err != nil {
+		return err
+	}
+
+	if err := img.Framing.Validate(); err != nil {
+		return err
+	}
+
+	for _, c := range img.Crops {
+		if _, _, err := c.Aspect.Ratio(); err != nil {
+			return err
+		}
+
+		for _, v := range c.Variants {
+			if v.Width <= 0 {
+				return ErrInvalidVariant
+			}
+
+			if _, err := url.ParseRequestURI(v.URL); err != nil {
+				return ErrInvalidVariant
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns whether or not the image has a crop of each of the given aspect
+// ratios, cropped by its current framing.
+func (img Image) Cropped(aspects []Aspect) bool {
+	for _, aspect := range aspects {
+		i := slices.IndexFunc(img.Crops, func(c AspectCrop) bool {
+			return c.Aspect == aspect && c.Framing.Equal(img.Framing)
+		})
+
+		if i < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the value of the srcset attribute of an HTML image, such as
+// "https://a/1-320w.jpeg 320w, https://a/1-640w.jpeg 640w". Returns an empty
+// string, if the image has no variants.
+func (img Image) SrcSet() string {
+	candidates := make([]string, 0)
+	for _, v := range img.Variants {
+		candidates = append(candidates, fmt.Sprintf("%s %dw", v.URL, v.Width))
+	}
+
+	return strings.Join(candidates, ", ")
+}
+
+// Returns the distinct URLs of the image, its variants and its crops.
+func (img Image) URLs() []string {
+	urls := make([]string, 0)
+
+	if img.URL != "" {
+		urls = append(urls, img.URL)
+	}
+
+	for _, v := range img.Variants {
+		if !slices.Contains(urls, v.URL) {
+			urls = append(urls, v.URL)
+		}
+	}
+
+	for _, c := range img.Crops {
+		for _, v := range c.Variants {
+			if !slices.Contains(urls, v.URL) {
+				urls = append(urls, v.URL)
+			}
+		}
+	}
+
+	return urls
+}
+
+// Returns the distinct URLs of the objects belonging to the image, which may
+// be deleted along with it. Images of media assets only own their crops.
+func (img Image) OwnedURLs() []string {
+	if img.MediaID <= 0 {
+		return img.URLs()
+	}
+
+	return Image{Crops: img.Crops}.URLs()
+}
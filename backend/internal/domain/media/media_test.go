@@ -0,0 +1,184 @@
+package media_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+func TestNewImage(t *testing.T) {
+	type test struct {
+		url      string
+		variants []media.Variant
+		want     media.Image
+		wantErr  error
+	}
+
+	tests := map[string]test{
+		"No variants": {
+			url:  "https://cdn.konnekt.dk/events/a.jpeg",
+			want: media.Image{URL: "https://cdn.konnekt.dk/events/a.jpeg"},
+		},
+		"Variants ordered by width": {
+			url: "https://cdn.konnekt.dk/events/a-640w.jpeg",
+			variants: []media.Variant{
+				{Width: 640, URL: "https://cdn.konnekt.dk/events/a-640w.jpeg"},
+				{Width: 320, URL: "https://cdn.konnekt.dk/events/a-320w.jpeg"},
+			},
+			want: media.Image{
+				URL: "https://cdn.konnekt.dk/events/a-640w.jpeg",
+				Variants: []media.Variant{
+					{Width: 320, URL: "https://cdn.konnekt.dk/events/a-320w.jpeg"},
+					{Width: 640, URL: "https://cdn.konnekt.dk/events/a-640w.jpeg"},
+				},
+			},
+		},
+		"Empty URL":           {url: "", wantErr: media.ErrInvalidURL},
+		"Invalid URL":         {url: "not a url", wantErr: media.ErrInvalidURL},
+		"Non-positive width":  {url: "https://a/b.jpeg", variants: []media.Variant{{Width: 0, URL: "https://a/b.jpeg"}}, wantErr: media.ErrInvalidVariant},
+		"Invalid variant URL": {url: "https://a/b.jpeg", variants: []media.Variant{{Width: 320, URL: "b.jpeg"}}, wantErr: media.ErrInvalidVariant},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			img, err := media.NewImage(tt.url, tt.variants...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if img.URL != tt.want.URL || !slices.Equal(img.Variants, tt.want.Variants) {
+				t.Fatalf("got %+v, want %+v", img, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	type test struct {
+		img     media.Image
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Placeholder": {
+			img: media.Image{URL: "https://a/b.jpeg", BlurHash: "LEHV6nWB2yk8pyo0adR*.7kCMdnj", Color: "#1a2b3c"},
+		},
+		"No placeholder": {img: media.Image{URL: "https://a/b.jpeg"}},
+		"Short BlurHash": {
+			img:     media.Image{URL: "https://a/b.jpeg", BlurHash: "LEHV6"},
+			wantErr: media.ErrInvalidBlurHash,
+		},
+		"Invalid BlurHash characters": {
+			img:     media.Image{URL: "https://a/b.jpeg", BlurHash: "LEHV6 nWB2yk8"},
+			wantErr: media.ErrInvalidBlurHash,
+		},
+		"Colour without hash": {
+			img:     media.Image{URL: "https://a/b.jpeg", Color: "1a2b3c"},
+			wantErr: media.ErrInvalidColor,
+		},
+		"Upper case colour": {
+			img:     media.Image{URL: "https://a/b.jpeg", Color: "#1A2B3C"},
+			wantErr: media.ErrInvalidColor,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.img.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSrcSet(t *testing.T) {
+	type test struct {
+		img  media.Image
+		want string
+	}
+
+	tests := map[string]test{
+		"No variants": {img: media.Image{URL: "https://a/b.jpeg"}, want: ""},
+		"Variants": {
+			img: media.Image{
+				URL: "https://a/b-640w.jpeg",
+				Variants: []media.Variant{
+					{Width: 320, URL: "https://a/b-320w.jpeg"},
+					{Width: 640, URL: "https://a/b-640w.jpeg"},
+				},
+			},
+			want: "https://a/b-320w.jpeg 320w, https://a/b-640w.jpeg 640w",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.img.SrcSet(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProfile(t *testing.T) {
+	type test struct {
+		quality    int
+		widths     []int
+		wantWidths []int
+		wantErr    error
+	}
+
+	tests := map[string]test{
+		"Valid":              {quality: 80, widths: []int{320, 640}, wantWidths: []int{320, 640}},
+		"Unordered widths":   {quality: 80, widths: []int{1280, 320, 640, 320}, wantWidths: []int{320, 640, 1280}},
+		"Zero quality":       {quality: 0, widths: []int{320}, wantErr: media.ErrInvalidQuality},
+		"Excessive quality":  {quality: 101, widths: []int{320}, wantErr: media.ErrInvalidQuality},
+		"No widths":          {quality: 80, wantErr: media.ErrNoWidths},
+		"Non-positive width": {quality: 80, widths: []int{0}, wantErr: media.ErrInvalidWidth},
+		"Excessive width":    {quality: 80, widths: []int{media.MAX_WIDTH_PX + 1}, wantErr: media.ErrInvalidWidth},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := media.NewProfile(tt.quality, tt.widths...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(p.Widths, tt.wantWidths) {
+				t.Fatalf("got widths %v, want %v", p.Widths, tt.wantWidths)
+			}
+		})
+	}
+}
+
+func TestVariantWidths(t *testing.T) {
+	type test struct {
+		imageWidth int
+		want       []int
+	}
+
+	profile := media.Profile{Widths: []int{320, 640, 1280}, Quality: 80}
+
+	tests := map[string]test{
+		"Wider than profile":    {imageWidth: 4000, want: []int{320, 640, 1280}},
+		"Equal to widest":       {imageWidth: 1280, want: []int{320, 640, 1280}},
+		"Between widths":        {imageWidth: 1000, want: []int{320, 640, 1000}},
+		"Equal to width":        {imageWidth: 640, want: []int{320, 640}},
+		"Narrower than profile": {imageWidth: 100, want: []int{100}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := profile.VariantWidths(tt.imageWidth); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
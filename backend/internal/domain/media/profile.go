@@ -0,0 +1,107 @@
+package media
+
+import (
+	"errors"
+	"slices"
+)
+
+const (
+	DEFAULT_QUALITY = 80
+	MAX_WIDTH_PX    = 4096
+)
+
+var (
+	ErrInvalidPurpose = errors.New("Image purpose must be one of artist, event, series, member, landing or media")
+	ErrInvalidQuality = errors.New("Image quality must be between 1 and 100")
+	ErrNoWidths       = errors.New("Image profile must have at least one width")
+	ErrInvalidWidth   = errors.New("Image widths must be between 1 and 4096 pixels")
+)
+
+// What an image is used for, which decides how it is processed.
+type Purpose string
+
+const (
+	PurposeArtist  = Purpose("artist")
+	PurposeEvent   = Purpose("event")
+	PurposeSeries  = Purpose("series")
+	PurposeMember  = Purpose("member")
+	PurposeLanding = Purpose("landing")
+
+	// Images of the media library, which are shared by other purposes.
+	PurposeMedia = Purpose("media")
+)
+
+// Describes the variants produced of images of a purpose. Images are never
+// upscaled, so variants wider than the uploaded image are left out, and the
+// uploaded image is used as the widest variant instead.
+type Profile struct {
+	// The widths of the variants, in ascending order.
+	Widths []int
+
+	// The JPEG quality of the variants, between 1 and 100.
+	Quality int
+
+	// The aspect ratios, which images are cropped to. Each crop has variants
+	// of the same widths as the image.
+	Aspects []Aspect
+}
+
+// Creates a profile of the given JPEG quality and variant widths.
+func NewProfile(quality int, widths ...int) (Profile, error) {
+	if quality < 1 || quality > 100 {
+		return Profile{}, ErrInvalidQuality
+	}
+
+	if len(widths) <= 0 {
+		return Profile{}, ErrNoWidths
+	}
+
+	for _, w := range widths {
+		if w < 1 || w > MAX_WIDTH_PX {
+			return Profile{}, ErrInvalidWidth
+		}
+	}
+
+	widths = slices.Clone(widths)
+	slices.Sort(widths)
+
+	return Profile{
+		Widths:  slices.Compact(widths),
+		Quality: quality,
+	}, nil
+}
+
+// Returns the widths of the variants of an image of the given width.
+func (p Profile) VariantWidths(imageWidth int) []int {
+	widths := make([]int, 0)
+
+	for _, w := range p.Widths {
+		if w >= imageWidth {
+			break
+		}
+
+		widths = append(widths, w)
+	}
+
+	// The image itself is the widest variant, if narrower than the widest
+	// width of the profile.
+	widest := min(imageWidth, p.Widths[len(p.Widths)-1])
+	if imageWidth > 0 && !slices.Contains(widths, widest) {
+		widths = append(widths, widest)
+	}
+
+	return widths
+}
+
+// The aspect ratios of cards, hero images and portrait posters.
+var DefaultAspects = []Aspect{AspectSquare, AspectWide, AspectPortrait}
+
+// The profiles used, unless configured otherwise.
+var DefaultProfiles = map[Purpose]Profile{
+	PurposeArtist:  {Widths: []int{320, 640, 1280, 2048}, Quality: DEFAULT_QUALITY, Aspects: DefaultAspects},
+	PurposeEvent:   {Widths: []int{320, 640, 1280, 2048}, Quality: DEFAULT_QUALITY, Aspects: DefaultAspects},
+	PurposeSeries:  {Widths: []int{320, 640, 1280, 2048}, Quality: DEFAULT_QUALITY},
+	PurposeMember:  {Widths: []int{128, 256, 512}, Quality: DEFAULT_QUALITY},
+	PurposeLanding: {Widths: []int{640, 1280, 2048}, Quality: DEFAULT_QUALITY},
+	PurposeMedia:   {Widths: []int{320, 640, 1280, 2048}, Quality: DEFAULT_QUALITY},
+}
@@ -0,0 +1,19 @@
+package media
+
+import (
+	"context"
+
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+// Stores the assets of the media library.
+type Repository interface {
+	Insert(ctx context.Context, a Asset) (int64, error)
+	Update(ctx context.Context, assetID int64, a Asset) error
+	ByID(ctx context.Context, assetID int64) (Asset, error)
+	List(ctx context.Context, q query.ListQuery) (query.ListResult[Asset], error)
+	Delete(ctx context.Context, assetID int64) error
+
+	// Returns the entities using the given asset.
+	References(ctx context.Context, assetID int64) ([]Reference, error)
+}
@@ -4,9 +4,10 @@ import (
 	"errors"
 	"net/http"
 	"net/mail"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/domain/team"
 )
 
@@ -23,16 +24,23 @@ var (
 
 	ErrProfileImageURLInvalid      = errors.New("Profile image URL must be valid")
 	ErrProfileImageURLInaccessible = errors.New("Profile image URL must be accessible")
+	ErrProfileImageMedia           = errors.New("Profile image must not be an image of the media library")
 )
 
 type Member struct {
-	ID                int64               `json:"id"`
-	FirstName         string              `json:"firstName"`
-	LastName          string              `json:"lastName"`
-	Email             string              `json:"email"`
-	ProfilePictureURL string              `json:"profilePictureUrl"`
-	Teams             team.TeamCollection `json:"teams"`
-	Active            bool                `json:"active"`
+	ID             int64               `json:"id"`
+	FirstName      string              `json:"firstName"`
+	LastName       string              `json:"lastName"`
+	Email          string              `json:"email"`
+	ProfilePicture media.Image         `json:"profilePicture"`
+	Teams          team.TeamCollection `json:"teams"`
+	Active         bool                `json:"active"`
+
+	// The time the member verified owning the email, or nil if unverified.
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt"`
+
+	// The time the latest verification email was sent to the member, if any.
+	VerificationSentAt *time.Time `json:"-"`
 
 	PasswordHash PasswordHash `json:"-"`
 }
@@ -139,14 +147,19 @@ func WithPasswordHash(hash []byte) cfgFunc {
 	}
 }
 
-func WithProfilePictureURL(imageUrl string) cfgFunc {
+// Sets the profile picture of the member. Members do not keep a reference to
+// media assets, so images of the media library are rejected.
+func WithProfilePicture(img media.Image) cfgFunc {
 	return func(m *Member) error {
-		u, err := url.Parse(imageUrl)
-		if err != nil {
+		if img.MediaID != 0 {
+			return ErrProfileImageMedia
+		}
+
+		if err := img.Validate(); err != nil {
 			return ErrProfileImageURLInvalid
 		}
 
-		resp, err := http.Get(u.String())
+		resp, err := http.Get(img.URL)
 		if err != nil {
 			return err
 		}
@@ -155,8 +168,12 @@ func WithProfilePictureURL(imageUrl string) cfgFunc {
 			return ErrProfileImageURLInaccessible
 		}
 
-		m.ProfilePictureURL = u.String()
+		m.ProfilePicture = img
 
 		return nil
 	}
 }
+
+func (m Member) IsEmailVerified() bool {
+	return m.EmailVerifiedAt != nil
+}
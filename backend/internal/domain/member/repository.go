@@ -2,7 +2,9 @@ package member
 
 import (
 	"context"
+	"time"
 
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/query"
 )
 
@@ -11,10 +13,13 @@ type Repository interface {
 	ByID(ctx context.Context, memberID int64) (Member, error)
 	List(ctx context.Context, query query.ListQuery) (query.ListResult[Member], error)
 	PasswordHash(ctx context.Context, memberID int64) (PasswordHash, error)
+	SetPasswordHash(ctx context.Context, memberID int64, hash PasswordHash) error
+	SetEmailVerified(ctx context.Context, memberID int64, verifiedAt time.Time) error
+	SetVerificationSentAt(ctx context.Context, memberID int64, sentAt time.Time) error
 	Insert(ctx context.Context, m Member) (int64, error)
 	Update(ctx context.Context, memberID int64, m Member) error
 	SetMemberTeams(ctx context.Context, memberID int64, teamIDs ...int64) error
 	Approve(ctx context.Context, memberID int64) error
 	Delete(ctx context.Context, memberID int64) error
-	SetProfilePictureURL(ctx context.Context, memberID int64, url string) error
+	SetProfilePicture(ctx context.Context, memberID int64, img media.Image) error
 }
@@ -0,0 +1,13 @@
+package search
+
+import "context"
+
+type Repository interface {
+	// Returns the results matching the query, grouped by kind in the order of
+	// the kinds of the query. The results of each kind are ordered by relevance,
+	// as the ranks of different kinds are not comparable.
+	Search(ctx context.Context, q Query) ([]Result, error)
+
+	// Rebuilds the search index from all events, artists and venues.
+	Reindex(ctx context.Context) error
+}
@@ -0,0 +1,178 @@
+package search
+
+import (
+	"errors"
+	"html"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// The default and maximum amount of results of each kind of a search.
+	DEFAULT_LIMIT = 20
+	MAX_LIMIT     = 50
+
+	// The maximum amount of terms of a search, as every term adds to the cost
+	// of the full-text query.
+	MAX_TERMS = 8
+
+	// The markers surrounding matched terms of raw snippets, as returned by the
+	// search index. They are control characters, so that they never collide
+	// with indexed text.
+	HIGHLIGHT_START = "\x02"
+	HIGHLIGHT_END   = "\x03"
+)
+
+var (
+	ErrEmptyQuery  = errors.New("Search query must contain at least one word")
+	ErrInvalidKind = errors.New("Search kind must be one of event, artist or venue")
+)
+
+type Kind string
+
+const (
+	KindEvent  = Kind("event")
+	KindArtist = Kind("artist")
+	KindVenue  = Kind("venue")
+)
+
+func Kinds() []Kind {
+	return []Kind{KindEvent, KindArtist, KindVenue}
+}
+
+func ParseKind(s string) (Kind, error) {
+	kind := Kind(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Kinds(), kind) {
+		return "", ErrInvalidKind
+	}
+
+	return kind, nil
+}
+
+// A single search result, being a reference to the matching entity.
+type Result struct {
+	Kind  Kind   `json:"kind"`
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+
+	// An HTML-escaped excerpt of the matching text, with matched terms
+	// surrounded by <mark> elements.
+	Snippet string `json:"snippet"`
+
+	// The relevance of the result among the results of its kind. Lower is more
+	// relevant.
+	Rank float64 `json:"rank"`
+}
+
+type Query struct {
+	// The words to search for. All words must match, either fully, by stem or
+	// by prefix.
+	Terms []string
+
+	// The kinds of entities to search. All kinds are searched if empty.
+	Kinds []Kind
+
+	// The maximum amount of results of each kind.
+	Limit int
+
+	// Whether or not to only include events, which are visible to the public
+	// at the given time.
+	PublicOnly bool
+	Now        time.Time
+}
+
+type CfgFunc func(q *Query) error
+
+func NewQuery(cfgs ...CfgFunc) (Query, error) {
+	q := &Query{
+		Terms: make([]string, 0),
+		Kinds: Kinds(),
+		Limit: DEFAULT_LIMIT,
+		Now:   time.Now(),
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(q); err != nil {
+			return Query{}, err
+		}
+	}
+
+	if len(q.Terms) <= 0 {
+		return Query{}, ErrEmptyQuery
+	}
+
+	return *q, nil
+}
+
+func WithText(text string) CfgFunc {
+	return func(q *Query) error {
+		q.Terms = Terms(text)
+		return nil
+	}
+}
+
+func WithKinds(kinds ...Kind) CfgFunc {
+	return func(q *Query) error {
+		if len(kinds) <= 0 {
+			return nil
+		}
+
+		q.Kinds = kinds
+		return nil
+	}
+}
+
+func WithLimit(limit int) CfgFunc {
+	return func(q *Query) error {
+		if limit <= 0 {
+			q.Limit = DEFAULT_LIMIT
+			return nil
+		}
+
+		q.Limit = min(limit, MAX_LIMIT)
+		return nil
+	}
+}
+
+func WithPublicOnly(publicOnly bool, now time.Time) CfgFunc {
+	return func(q *Query) error {
+		q.PublicOnly = publicOnly
+		q.Now = now
+		return nil
+	}
+}
+
+// Splits the text into lower-case words of letters and digits, omitting
+// duplicates. At most MAX_TERMS words are returned.
+func Terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0)
+	for _, word := range words {
+		if slices.Contains(terms, word) {
+			continue
+		}
+
+		terms = append(terms, word)
+
+		if len(terms) >= MAX_TERMS {
+			break
+		}
+	}
+
+	return terms
+}
+
+// Converts a raw snippet with HIGHLIGHT_START and HIGHLIGHT_END markers into
+// HTML, escaping the snippet text and marking the matched terms.
+func Highlight(raw string) string {
+	s := html.EscapeString(raw)
+	s = strings.ReplaceAll(s, HIGHLIGHT_START, "<mark>")
+	s = strings.ReplaceAll(s, HIGHLIGHT_END, "</mark>")
+
+	return s
+}
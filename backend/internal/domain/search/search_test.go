@@ -0,0 +1,96 @@
+package search_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/search"
+)
+
+func TestTerms(t *testing.T) {
+	type test struct {
+		text string
+		want []string
+	}
+
+	tests := map[string]test{
+		"Empty":           {text: "", want: []string{}},
+		"Only symbols":    {text: `"*()-:^`, want: []string{}},
+		"Single word":     {text: "Jazz", want: []string{"jazz"}},
+		"Multiple words":  {text: "jazz  night", want: []string{"jazz", "night"}},
+		"Query syntax":    {text: `"jazz" OR night*`, want: []string{"jazz", "or", "night"}},
+		"Duplicate words": {text: "jazz Jazz JAZZ", want: []string{"jazz"}},
+		"Non-ASCII":       {text: "Århus døgn", want: []string{"århus", "døgn"}},
+		"Digits":          {text: "Roskilde 2025", want: []string{"roskilde", "2025"}},
+		"Too many words": {
+			text: "a b c d e f g h i j",
+			want: []string{"a", "b", "c", "d", "e", "f", "g", "h"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := search.Terms(tt.text)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	mark := func(s string) string {
+		return search.HIGHLIGHT_START + s + search.HIGHLIGHT_END
+	}
+
+	type test struct {
+		raw  string
+		want string
+	}
+
+	tests := map[string]test{
+		"No match":   {raw: "jazz night", want: "jazz night"},
+		"Match":      {raw: mark("jazz") + " night", want: "<mark>jazz</mark> night"},
+		"Escaped":    {raw: "<b>" + mark("jazz") + "</b>", want: "&lt;b&gt;<mark>jazz</mark>&lt;/b&gt;"},
+		"Ampersands": {raw: "rock & " + mark("roll"), want: "rock &amp; <mark>roll</mark>"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := search.Highlight(tt.raw); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewQuery(t *testing.T) {
+	type test struct {
+		cfgs      []search.CfgFunc
+		wantLimit int
+		wantErr   error
+	}
+
+	tests := map[string]test{
+		"No text":        {wantErr: search.ErrEmptyQuery},
+		"Blank text":     {cfgs: []search.CfgFunc{search.WithText(strings.Repeat(" ", 4))}, wantErr: search.ErrEmptyQuery},
+		"Default limit":  {cfgs: []search.CfgFunc{search.WithText("jazz")}, wantLimit: search.DEFAULT_LIMIT},
+		"Exceeded limit": {cfgs: []search.CfgFunc{search.WithText("jazz"), search.WithLimit(1000)}, wantLimit: search.MAX_LIMIT},
+		"Custom limit":   {cfgs: []search.CfgFunc{search.WithText("jazz"), search.WithLimit(5)}, wantLimit: 5},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			q, err := search.NewQuery(tt.cfgs...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && q.Limit != tt.wantLimit {
+				t.Fatalf("got limit %d, want %d", q.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package series
+
+import (
+	"context"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+type Repository interface {
+	Insert(ctx context.Context, s Series) (int64, error)
+	Update(ctx context.Context, seriesID int64, s Series) error
+	SetImage(ctx context.Context, seriesID int64, img media.Image) error
+	ByID(ctx context.Context, seriesID int64) (Series, error)
+	BySlug(ctx context.Context, slug string) (Series, error)
+	List(ctx context.Context, q query.ListQuery) (query.ListResult[Series], error)
+	Delete(ctx context.Context, seriesID int64) error
+}
@@ -0,0 +1,166 @@
+package series
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+var (
+	ErrNoExist          = errors.New("Series does not exist")
+	ErrInvalidID        = errors.New("Series ID must be a positive integer")
+	ErrEmptyName        = errors.New("Series name must not be empty")
+	ErrInvalidSlug      = errors.New("Series slug must only contain lowercase letters, digits and dashes")
+	ErrSlugTaken        = errors.New("Series slug is already in use")
+	ErrInvalidImageURL  = errors.New("Series image URL must be valid")
+	ErrMediaImage       = errors.New("Series image must not be an image of the media library")
+	ErrEmptyDescription = errors.New("Series description must not be empty")
+)
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Transliterates the most common non-ASCII letters of names.
+var slugReplacer = strings.NewReplacer(
+	"æ", "ae", "ø", "oe", "å", "aa",
+	"ä", "a", "á", "a", "à", "a", "â", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ö", "o", "ó", "o", "ò", "o", "ô", "o",
+	"ü", "u", "ú", "u", "ù", "u", "û", "u",
+	"ñ", "n", "ç", "c", "ß", "ss",
+)
+
+// A series groups related events, such as the days of a festival or the
+// nights of a recurring club night.
+type Series struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Slug        string      `json:"slug"`
+	Description string      `json:"description"`
+	Image       media.Image `json:"image"`
+}
+
+type CfgFunc func(s *Series) error
+
+func NewSeries(cfgs ...CfgFunc) (*Series, error) {
+	s := &Series{}
+
+	if err := s.WithCfgs(cfgs...); err != nil {
+		return &Series{}, err
+	}
+
+	return s, nil
+}
+
+func (s *Series) WithCfgs(cfgs ...CfgFunc) error {
+	for _, cfg := range cfgs {
+		if err := cfg(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WithID(id int64) CfgFunc {
+	return func(s *Series) error {
+		if id <= 0 {
+			return ErrInvalidID
+		}
+
+		s.ID = id
+		return nil
+	}
+}
+
+func WithName(name string) CfgFunc {
+	return func(s *Series) error {
+		name = strings.TrimSpace(name)
+
+		if name == "" {
+			return ErrEmptyName
+		}
+
+		s.Name = name
+		return nil
+	}
+}
+
+// Sets the slug of the series, used in public URLs. If the slug is empty, it
+// is derived from the name of the series, which must therefore be set first.
+func WithSlug(slug string) CfgFunc {
+	return func(s *Series) error {
+		slug = strings.TrimSpace(slug)
+
+		if slug == "" {
+			slug = Slugify(s.Name)
+		}
+
+		if !slugRegex.MatchString(slug) {
+			return ErrInvalidSlug
+		}
+
+		s.Slug = slug
+		return nil
+	}
+}
+
+func WithDescription(description string) CfgFunc {
+	return func(s *Series) error {
+		description = strings.TrimSpace(description)
+
+		if description == "" {
+			return ErrEmptyDescription
+		}
+
+		s.Description = description
+		return nil
+	}
+}
+
+// Sets the image of the series. Series do not keep a reference to media
+// assets, so the objects of an asset would be deleted along with the series.
+// Images of the media library are therefore rejected.
+func WithImage(img media.Image) CfgFunc {
+	return func(s *Series) error {
+		if img.MediaID != 0 {
+			return ErrMediaImage
+		}
+
+		if err := img.Validate(); err != nil {
+			return ErrInvalidImageURL
+		}
+
+		s.Image = img
+		return nil
+	}
+}
+
+// Converts a name into a slug of lowercase letters, digits and dashes. Common
+// non-ASCII letters are transliterated, and all other characters separate
+// the words of the slug.
+func Slugify(name string) string {
+	name = slugReplacer.Replace(strings.ToLower(name))
+
+	var b strings.Builder
+	dash := false
+
+	for _, r := range name {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package series_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+)
+
+func TestSlugify(t *testing.T) {
+	type test struct {
+		name string
+		want string
+	}
+
+	tests := map[string]test{
+		"Simple name":         {name: "Summer Festival", want: "summer-festival"},
+		"Danish letters":      {name: "Grøn Koncert på Åen", want: "groen-koncert-paa-aaen"},
+		"Accents":             {name: "Café Noir", want: "cafe-noir"},
+		"Punctuation":         {name: "  Club Night #3 -- Vol. 2!  ", want: "club-night-3-vol-2"},
+		"Only punctuation":    {name: "!!!", want: ""},
+		"Digits are retained": {name: "2025", want: "2025"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := series.Slugify(tt.name); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithSlug(t *testing.T) {
+	type test struct {
+		name    string
+		slug    string
+		want    string
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Derived from name": {name: "Club Night", want: "club-night"},
+		"Explicit slug":     {name: "Club Night", slug: "cn-2025", want: "cn-2025"},
+		"Uppercase slug":    {name: "Club Night", slug: "Club-Night", wantErr: series.ErrInvalidSlug},
+		"Trailing dash":     {name: "Club Night", slug: "club-", wantErr: series.ErrInvalidSlug},
+		"Underivable name":  {name: "???", wantErr: series.ErrInvalidSlug},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := series.NewSeries(series.WithName(tt.name), series.WithSlug(tt.slug))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && s.Slug != tt.want {
+				t.Fatalf("got %q, want %q", s.Slug, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithImage(t *testing.T) {
+	type test struct {
+		img     media.Image
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Uploaded image": {img: media.Image{URL: "https://knnkt.dk/series/a-1080w.jpeg"}},
+		"Invalid URL":    {img: media.Image{URL: "series/a-1080w.jpeg"}, wantErr: series.ErrInvalidImageURL},
+		"Media library image": {
+			img:     media.Image{MediaID: 1, URL: "https://knnkt.dk/media/a-1080w.jpeg"},
+			wantErr: series.ErrMediaImage,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := series.NewSeries(series.WithName("Club Night"), series.WithImage(tt.img))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 )
 
 var (
+	ErrTeamNotFound = errors.New("Team not found")
+
 	ErrTeamIDInvalid          = errors.New("Team ID must be a valid positive integer")
 	ErrTeamNameInvalid        = errors.New("Team name must be a valid non-empty string")
 	ErrTeamDisplayNameInvalid = errors.New("Team display name must be a valid non-empty string")
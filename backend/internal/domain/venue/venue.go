@@ -2,6 +2,7 @@ package venue
 
 import (
 	"strings"
+	"time"
 
 	"github.com/mattismoel/konnekt/internal/query"
 )
@@ -11,6 +12,9 @@ type Venue struct {
 	Name        string `json:"name"`
 	CountryCode string `json:"countryCode"`
 	City        string `json:"city"`
+
+	// The time of the latest change to the venue.
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type Query struct {
@@ -0,0 +1,170 @@
+// Package exif reads the orientation of JPEG images from their EXIF metadata,
+// and turns images upright accordingly.
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+)
+
+var (
+	ErrNoEXIF             = errors.New("Image has no EXIF metadata")
+	ErrInvalidEXIF        = errors.New("EXIF metadata must be valid")
+	ErrInvalidOrientation = errors.New("EXIF orientation must be between 1 and 8")
+)
+
+const (
+	markerPrefix = 0xff
+	markerSOI    = 0xd8
+	markerEOI    = 0xd9
+	markerSOS    = 0xda
+	markerAPP1   = 0xe1
+
+	tagOrientation = 0x0112
+	typeShort      = 3
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// Decodes the image of r, turned upright as described by its EXIF
+// orientation. Missing or invalid EXIF metadata leaves the image as is.
+func Decode(r io.Reader) (image.Image, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	orientation, err := ReadOrientation(data)
+	if err != nil {
+		return img, format, nil
+	}
+
+	return orientation.Apply(img), format, nil
+}
+
+// Decodes the dimensions of the image of r, as displayed upright, without
+// decoding its pixels.
+func DecodeConfig(r io.Reader) (image.Config, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return image.Config{}, "", err
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return image.Config{}, "", err
+	}
+
+	orientation, err := ReadOrientation(data)
+	if err == nil && orientation.Transposed() {
+		cfg.Width, cfg.Height = cfg.Height, cfg.Width
+	}
+
+	return cfg, format, nil
+}
+
+// Returns the orientation of the given JPEG image. ErrNoEXIF is returned for
+// images without EXIF metadata, including images of other formats.
+func ReadOrientation(data []byte) (Orientation, error) {
+	tiff, err := exifSegment(data)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(tiff) < 8 {
+		return 0, ErrInvalidEXIF
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, ErrInvalidEXIF
+	}
+
+	if order.Uint16(tiff[2:4]) != 42 {
+		return 0, ErrInvalidEXIF
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0, ErrInvalidEXIF
+	}
+
+	entryCount := int(order.Uint16(tiff[ifd : ifd+2]))
+
+	for i := range entryCount {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0, ErrInvalidEXIF
+		}
+
+		if order.Uint16(tiff[entry:entry+2]) != tagOrientation {
+			continue
+		}
+
+		if order.Uint16(tiff[entry+2:entry+4]) != typeShort {
+			return 0, ErrInvalidEXIF
+		}
+
+		orientation := Orientation(order.Uint16(tiff[entry+8 : entry+10]))
+		if !orientation.Valid() {
+			return 0, ErrInvalidOrientation
+		}
+
+		return orientation, nil
+	}
+
+	return OrientationNormal, nil
+}
+
+// Returns the TIFF structure of the EXIF segment of the given JPEG image.
+func exifSegment(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != markerPrefix || data[1] != markerSOI {
+		return nil, ErrNoEXIF
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != markerPrefix {
+			return nil, ErrInvalidEXIF
+		}
+
+		marker := data[i+1]
+
+		// Markers may be padded by any amount of fill bytes.
+		if marker == markerPrefix {
+			i++
+			continue
+		}
+
+		// The metadata segments all precede the image data.
+		if marker == markerSOS || marker == markerEOI {
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return nil, ErrInvalidEXIF
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == markerAPP1 && bytes.HasPrefix(segment, exifHeader) {
+			return segment[len(exifHeader):], nil
+		}
+
+		i += 2 + length
+	}
+
+	return nil, ErrNoEXIF
+}
@@ -0,0 +1,166 @@
+package exif_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/exif"
+)
+
+// The fixtures each hold the same upright 32x16 image of a red, green, blue
+// and white quadrant, stored in one of the eight orientations, along with GPS
+// metadata.
+var quadrants = []struct {
+	x, y int
+	want color.RGBA
+}{
+	{x: 8, y: 4, want: color.RGBA{255, 0, 0, 255}},
+	{x: 24, y: 4, want: color.RGBA{0, 255, 0, 255}},
+	{x: 8, y: 12, want: color.RGBA{0, 0, 255, 255}},
+	{x: 24, y: 12, want: color.RGBA{255, 255, 255, 255}},
+}
+
+func readFixture(t *testing.T, orientation exif.Orientation) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(fmt.Sprintf("testdata/orientation_%d.jpg", orientation))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestReadOrientation(t *testing.T) {
+	type test struct {
+		data    []byte
+		want    exif.Orientation
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"No EXIF":     {data: encodeJPEG(t, image.NewRGBA(image.Rect(0, 0, 4, 4))), wantErr: exif.ErrNoEXIF},
+		"Not a JPEG":  {data: []byte("\x89PNG\r\n\x1a\n"), wantErr: exif.ErrNoEXIF},
+		"Empty":       {data: []byte{}, wantErr: exif.ErrNoEXIF},
+		"Truncated":   {data: readFixture(t, exif.OrientationRotate90)[:30], wantErr: exif.ErrInvalidEXIF},
+		"Bad segment": {data: []byte{0xff, 0xd8, 0x00, 0x00, 0x00, 0x00}, wantErr: exif.ErrInvalidEXIF},
+	}
+
+	for o := exif.OrientationNormal; o <= exif.OrientationRotate270; o++ {
+		tests[fmt.Sprintf("Orientation %d", o)] = test{data: readFixture(t, o), want: o}
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := exif.ReadOrientation(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for o := exif.OrientationNormal; o <= exif.OrientationRotate270; o++ {
+		t.Run(fmt.Sprintf("Orientation %d", o), func(t *testing.T) {
+			img, format, err := exif.Decode(bytes.NewReader(readFixture(t, o)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if format != "jpeg" {
+				t.Fatalf("got format %q, want jpeg", format)
+			}
+
+			if got := img.Bounds().Size(); got != image.Pt(32, 16) {
+				t.Fatalf("got size %v, want (32,16)", got)
+			}
+
+			for _, q := range quadrants {
+				got := color.RGBAModel.Convert(img.At(img.Bounds().Min.X+q.x, img.Bounds().Min.Y+q.y)).(color.RGBA)
+				if !similar(got, q.want) {
+					t.Fatalf("got %v at (%d,%d), want %v", got, q.x, q.y, q.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeConfig(t *testing.T) {
+	for o := exif.OrientationNormal; o <= exif.OrientationRotate270; o++ {
+		t.Run(fmt.Sprintf("Orientation %d", o), func(t *testing.T) {
+			cfg, format, err := exif.DecodeConfig(bytes.NewReader(readFixture(t, o)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if format != "jpeg" {
+				t.Fatalf("got format %q, want jpeg", format)
+			}
+
+			if cfg.Width != 32 || cfg.Height != 16 {
+				t.Fatalf("got size %dx%d, want 32x16", cfg.Width, cfg.Height)
+			}
+		})
+	}
+}
+
+// Images are stored by re-encoding their decoded pixels, which must leave no
+// metadata behind.
+func TestDecodeStripsMetadata(t *testing.T) {
+	for o := exif.OrientationNormal; o <= exif.OrientationRotate270; o++ {
+		t.Run(fmt.Sprintf("Orientation %d", o), func(t *testing.T) {
+			img, _, err := exif.Decode(bytes.NewReader(readFixture(t, o)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data := encodeJPEG(t, img)
+
+			if _, err := exif.ReadOrientation(data); !errors.Is(err, exif.ErrNoEXIF) {
+				t.Fatalf("got %v, want %v", err, exif.ErrNoEXIF)
+			}
+
+			if bytes.Contains(data, []byte("Exif")) {
+				t.Fatal("got EXIF header in output")
+			}
+		})
+	}
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// Returns whether or not the colours are equal, within the loss of JPEG
+// compression.
+func similar(a, b color.RGBA) bool {
+	const tolerance = 24
+
+	diff := func(x, y uint8) int {
+		if x > y {
+			return int(x - y)
+		}
+
+		return int(y - x)
+	}
+
+	return diff(a.R, b.R) <= tolerance && diff(a.G, b.G) <= tolerance && diff(a.B, b.B) <= tolerance
+}
@@ -0,0 +1,87 @@
+package exif
+
+import (
+	"image"
+	"image/draw"
+)
+
+// The orientation of the stored pixels of an image, describing how they must
+// be transformed to be displayed upright.
+type Orientation int
+
+const (
+	OrientationNormal Orientation = iota + 1
+	OrientationFlipHorizontal
+	OrientationRotate180
+	OrientationFlipVertical
+	OrientationTranspose
+	OrientationRotate90
+	OrientationTransverse
+	OrientationRotate270
+)
+
+func (o Orientation) Valid() bool {
+	return o >= OrientationNormal && o <= OrientationRotate270
+}
+
+// Returns whether or not the width and height of the image are swapped, when
+// displayed upright.
+func (o Orientation) Transposed() bool {
+	return o >= OrientationTranspose && o <= OrientationRotate270
+}
+
+// Returns the given image transformed to be displayed upright. Images of
+// normal or invalid orientation are returned as is.
+func (o Orientation) Apply(img image.Image) image.Image {
+	if o == OrientationNormal || !o.Valid() {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dstW, dstH := w, h
+	if o.Transposed() {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := range dstH {
+		for x := range dstW {
+			sx, sy := o.source(x, y, w, h)
+
+			s := src.PixOffset(sx, sy)
+			d := dst.PixOffset(x, y)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+
+	return dst
+}
+
+// Returns the stored pixel of an image of the given stored width and height,
+// which is displayed at x, y.
+func (o Orientation) source(x, y, w, h int) (int, int) {
+	switch o {
+	case OrientationFlipHorizontal:
+		return w - 1 - x, y
+	case OrientationRotate180:
+		return w - 1 - x, h - 1 - y
+	case OrientationFlipVertical:
+		return x, h - 1 - y
+	case OrientationTranspose:
+		return y, x
+	case OrientationRotate90:
+		return y, h - 1 - x
+	case OrientationTransverse:
+		return w - 1 - y, h - 1 - x
+	case OrientationRotate270:
+		return w - 1 - y, x
+	default:
+		return x, y
+	}
+}
@@ -0,0 +1,159 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// The maximum amount of octets on a single content line, excluding the
+	// line break, as per RFC 5545 section 3.1.
+	MAX_LINE_OCTETS = 75
+
+	dateTimeFormat = "20060102T150405Z"
+	lineBreak      = "\r\n"
+)
+
+// The status of a calendar event, as per RFC 5545 section 3.8.1.11.
+type EventStatus string
+
+const (
+	StatusTentative = EventStatus("TENTATIVE")
+	StatusConfirmed = EventStatus("CONFIRMED")
+	StatusCancelled = EventStatus("CANCELLED")
+)
+
+type Calendar struct {
+	// The product identifier of the application creating the calendar.
+	ProdID string
+
+	// The display name of the calendar, shown by most calendar clients when
+	// subscribing.
+	Name string
+
+	// The time at which the calendar was generated. Used as DTSTAMP for all
+	// events of the calendar.
+	Stamp time.Time
+
+	Events []Event
+}
+
+type Event struct {
+	// The globally unique identifier of the event. Calendar clients use the
+	// UID to replace previously imported entries, so it must remain stable
+	// across updates.
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      EventStatus
+}
+
+// Encodes the calendar as an iCalendar stream to the given writer.
+func (c Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + c.ProdID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+
+	if c.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		lines = append(lines, e.lines(c.Stamp)...)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := bw.WriteString(fold(line)); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func (e Event) lines(stamp time.Time) []string {
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + e.UID,
+		"DTSTAMP:" + formatTime(stamp),
+		"DTSTART:" + formatTime(e.Start),
+		"DTEND:" + formatTime(e.End),
+		"SUMMARY:" + escape(e.Summary),
+	}
+
+	if e.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escape(e.Description))
+	}
+
+	if e.Location != "" {
+		lines = append(lines, "LOCATION:"+escape(e.Location))
+	}
+
+	if e.URL != "" {
+		lines = append(lines, "URL:"+e.URL)
+	}
+
+	if e.Status != "" {
+		lines = append(lines, "STATUS:"+string(e.Status))
+	}
+
+	return append(lines, "END:VEVENT")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// Escapes a text value as per RFC 5545 section 3.3.11.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// Folds a content line into multiple lines of at most MAX_LINE_OCTETS octets,
+// never splitting a multi-byte character. Continuation lines are prefixed
+// with a single space. The returned string is terminated by a line break.
+func fold(line string) string {
+	var b strings.Builder
+
+	limit := MAX_LINE_OCTETS
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString(lineBreak + " ")
+		line = line[cut:]
+
+		// The leading space of continuation lines counts towards the limit.
+		limit = MAX_LINE_OCTETS - 1
+	}
+
+	b.WriteString(line)
+	b.WriteString(lineBreak)
+
+	return b.String()
+}
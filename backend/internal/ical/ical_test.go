@@ -0,0 +1,140 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/ical"
+)
+
+func TestEncode(t *testing.T) {
+	stamp := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	type test struct {
+		calendar  ical.Calendar
+		wantLines []string
+	}
+
+	tests := map[string]test{
+		"Empty calendar": {
+			calendar: ical.Calendar{ProdID: "-//Test//EN", Stamp: stamp},
+			wantLines: []string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//Test//EN",
+				"CALSCALE:GREGORIAN",
+				"METHOD:PUBLISH",
+				"END:VCALENDAR",
+			},
+		},
+		"Single event": {
+			calendar: ical.Calendar{
+				ProdID: "-//Test//EN",
+				Name:   "Shows",
+				Stamp:  stamp,
+				Events: []ical.Event{{
+					UID:      "event-1@example.com",
+					Start:    time.Date(2025, 7, 1, 20, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+					End:      time.Date(2025, 7, 1, 23, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+					Summary:  "Band; live, again",
+					Location: "Venue, City",
+				}},
+			},
+			wantLines: []string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//Test//EN",
+				"CALSCALE:GREGORIAN",
+				"METHOD:PUBLISH",
+				"X-WR-CALNAME:Shows",
+				"BEGIN:VEVENT",
+				"UID:event-1@example.com",
+				"DTSTAMP:20250601T120000Z",
+				"DTSTART:20250701T180000Z",
+				"DTEND:20250701T210000Z",
+				`SUMMARY:Band\; live\, again`,
+				`LOCATION:Venue\, City`,
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+		},
+		"Cancelled event": {
+			calendar: ical.Calendar{
+				ProdID: "-//Test//EN",
+				Stamp:  stamp,
+				Events: []ical.Event{{
+					UID:     "event-2@example.com",
+					Start:   time.Date(2025, 7, 2, 20, 0, 0, 0, time.UTC),
+					End:     time.Date(2025, 7, 2, 23, 0, 0, 0, time.UTC),
+					Summary: "Band",
+					Status:  ical.StatusCancelled,
+				}},
+			},
+			wantLines: []string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//Test//EN",
+				"CALSCALE:GREGORIAN",
+				"METHOD:PUBLISH",
+				"BEGIN:VEVENT",
+				"UID:event-2@example.com",
+				"DTSTAMP:20250601T120000Z",
+				"DTSTART:20250702T200000Z",
+				"DTEND:20250702T230000Z",
+				"SUMMARY:Band",
+				"STATUS:CANCELLED",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+			if err := tt.calendar.Encode(&b); err != nil {
+				t.Fatal(err)
+			}
+
+			want := strings.Join(tt.wantLines, "\r\n") + "\r\n"
+			if b.String() != want {
+				t.Fatalf("\ngot:\n%q\nwant:\n%q", b.String(), want)
+			}
+		})
+	}
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	cal := ical.Calendar{
+		Events: []ical.Event{{
+			UID:         "event-1@example.com",
+			Description: strings.Repeat("æ", 100),
+		}},
+	}
+
+	var b strings.Builder
+	if err := cal.Encode(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+
+	var unfolded strings.Builder
+	for _, line := range lines {
+		if len(line) > ical.MAX_LINE_OCTETS {
+			t.Fatalf("line exceeds %d octets: %q", ical.MAX_LINE_OCTETS, line)
+		}
+
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(strings.TrimPrefix(line, " "))
+			continue
+		}
+
+		unfolded.WriteString("\n" + line)
+	}
+
+	if !strings.Contains(unfolded.String(), "DESCRIPTION:"+strings.Repeat("æ", 100)) {
+		t.Fatalf("description was not preserved when unfolding:\n%s", unfolded.String())
+	}
+}
@@ -0,0 +1,77 @@
+package file
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/mailer"
+)
+
+var _ mailer.Mailer = (*FileMailer)(nil)
+
+// A mailer writing messages as .eml files to a directory instead of
+// delivering them. Used for local development and tests.
+type FileMailer struct {
+	dir  string
+	from *mail.Address
+}
+
+// Creates a mailer writing to the directory, which is created if it does not
+// exist.
+func NewFileMailer(dir string, from string) (*FileMailer, error) {
+	addr, err := mailer.ParseSender(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{
+		dir:  dir,
+		from: addr,
+	}, nil
+}
+
+func (m FileMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000Z"), hex.EncodeToString(suffix))
+	path := filepath.Join(m.dir, name)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err := msg.Encode(f, m.from, now); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	slog.Info("Wrote mail", "to", msg.To, "subject", msg.Subject, "path", path)
+
+	return nil
+}
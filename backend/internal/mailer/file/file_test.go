@@ -0,0 +1,92 @@
+package file_test
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/mailer"
+	"github.com/mattismoel/konnekt/internal/mailer/file"
+)
+
+func TestSend(t *testing.T) {
+	type test struct {
+		msgs    []mailer.Message
+		want    int
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Single message": {
+			msgs: []mailer.Message{{To: "a@example.com", Subject: "Hi", Body: "Hello"}},
+			want: 1,
+		},
+		"Many messages": {
+			msgs: []mailer.Message{
+				{To: "a@example.com", Subject: "Hi", Body: "Hello"},
+				{To: "a@example.com", Subject: "Hi", Body: "Hello"},
+			},
+			want: 2,
+		},
+		"Invalid message": {
+			msgs:    []mailer.Message{{To: "nope", Subject: "Hi"}},
+			wantErr: mailer.ErrInvalidRecipient,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "mail")
+
+			m, err := file.NewFileMailer(dir, "noreply@example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, msg := range tt.msgs {
+				err = errors.Join(err, m.Send(context.Background(), msg))
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != tt.want {
+				t.Fatalf("got %d files, want %d", len(entries), tt.want)
+			}
+
+			for _, entry := range entries {
+				f, err := os.Open(filepath.Join(dir, entry.Name()))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				defer f.Close()
+
+				parsed, err := mail.ReadMessage(f)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := parsed.Header.Get("Subject"); got != "Hi" {
+					t.Fatalf("got subject %q, want %q", got, "Hi")
+				}
+			}
+		})
+	}
+}
+
+func TestNewFileMailer(t *testing.T) {
+	_, err := file.NewFileMailer(t.TempDir(), "nope")
+	if !errors.Is(err, mailer.ErrInvalidSender) {
+		t.Fatalf("got %v, want %v", err, mailer.ErrInvalidSender)
+	}
+}
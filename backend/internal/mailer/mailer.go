@@ -0,0 +1,92 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRecipient = errors.New("Mail recipient must be a valid email address")
+	ErrInvalidSender    = errors.New("Mail sender must be a valid email address")
+	ErrInvalidSubject   = errors.New("Mail subject must be a single non-empty line")
+)
+
+type Mailer interface {
+	// Sends the message, returning once it has been handed over for
+	// delivery.
+	Send(ctx context.Context, msg Message) error
+}
+
+// A plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+func (m Message) Validate() error {
+	if _, err := mail.ParseAddress(m.To); err != nil {
+		return ErrInvalidRecipient
+	}
+
+	if strings.TrimSpace(m.Subject) == "" || strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidSubject
+	}
+
+	return nil
+}
+
+// Parses the sender address, such as "Konnekt <noreply@knnkt.dk>".
+func ParseSender(from string) (*mail.Address, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, ErrInvalidSender
+	}
+
+	return addr, nil
+}
+
+// Encodes the message as an RFC 5322 email from the given sender, sent at the
+// given date.
+func (m Message) Encode(w io.Writer, from *mail.Address, date time.Time) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	// The recipient is reformatted from its parsed address, so that no
+	// headers may be injected through it.
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return ErrInvalidRecipient
+	}
+
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + to.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date: " + date.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+	}
+
+	if _, err := fmt.Fprint(w, strings.Join(headers, "\r\n")+"\r\n\r\n"); err != nil {
+		return err
+	}
+
+	qw := quotedprintable.NewWriter(w)
+
+	body := strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := io.WriteString(qw, body); err != nil {
+		return err
+	}
+
+	return qw.Close()
+}
@@ -0,0 +1,86 @@
+package mailer_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/mailer"
+)
+
+func TestValidate(t *testing.T) {
+	type test struct {
+		msg     mailer.Message
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Valid message":         {msg: mailer.Message{To: "a@example.com", Subject: "Hi"}},
+		"Named recipient":       {msg: mailer.Message{To: "Ann <a@example.com>", Subject: "Hi"}},
+		"Invalid recipient":     {msg: mailer.Message{To: "nope", Subject: "Hi"}, wantErr: mailer.ErrInvalidRecipient},
+		"Empty subject":         {msg: mailer.Message{To: "a@example.com", Subject: " "}, wantErr: mailer.ErrInvalidSubject},
+		"Multiple line subject": {msg: mailer.Message{To: "a@example.com", Subject: "Hi\r\nBcc: b@example.com"}, wantErr: mailer.ErrInvalidSubject},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.msg.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	from, err := mailer.ParseSender("Konnekt <noreply@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := mailer.Message{
+		To:      "Åse <a@example.com>",
+		Subject: "Nulstil din adgangskode",
+		Body:    "Hej Åse,\n\nFølg linket.",
+	}
+
+	var buf bytes.Buffer
+	if err := msg.Encode(&buf, from, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := mail.ReadMessage(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := map[string]string{
+		"From":    `"Konnekt" <noreply@example.com>`,
+		"Subject": "Nulstil din adgangskode",
+		"Date":    "Sun, 01 Jun 2025 12:00:00 +0000",
+	}
+
+	for key, want := range headers {
+		if got := parsed.Header.Get(key); got != want {
+			t.Fatalf("got %s %q, want %q", key, got, want)
+		}
+	}
+
+	to, err := parsed.Header.AddressList("To")
+	if err != nil || len(to) != 1 || to[0].Name != "Åse" || to[0].Address != "a@example.com" {
+		t.Fatalf("got recipients %v (%v), want Åse <a@example.com>", to, err)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "Hej Åse,\r\n\r\nFølg linket."; strings.TrimSpace(string(body)) != want {
+		t.Fatalf("got body %q, want %q", body, want)
+	}
+}
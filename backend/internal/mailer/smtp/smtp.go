@@ -0,0 +1,139 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/mailer"
+)
+
+var (
+	ErrInvalidHost = errors.New("SMTP host must not be empty")
+	ErrInvalidPort = errors.New("SMTP port must be between 1 and 65535")
+)
+
+var _ mailer.Mailer = (*SMTPMailer)(nil)
+
+// A mailer delivering messages through an SMTP server. The connection is
+// upgraded with STARTTLS, when the server supports it.
+type SMTPMailer struct {
+	host string
+	addr string
+	from *mail.Address
+
+	// Authenticates with the server, if set.
+	auth smtp.Auth
+}
+
+type CfgFunc func(m *SMTPMailer) error
+
+func NewSMTPMailer(host string, port int, from string, cfgs ...CfgFunc) (*SMTPMailer, error) {
+	if host == "" {
+		return nil, ErrInvalidHost
+	}
+
+	if port <= 0 || port > 65535 {
+		return nil, ErrInvalidPort
+	}
+
+	addr, err := mailer.ParseSender(from)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SMTPMailer{
+		host: host,
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: addr,
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Authenticates with the given credentials using PLAIN authentication, which
+// is only attempted over TLS or to localhost.
+func WithCredentials(username, password string) CfgFunc {
+	return func(m *SMTPMailer) error {
+		m.auth = smtp.PlainAuth("", username, password, m.host)
+		return nil
+	}
+}
+
+func (m SMTPMailer) Send(ctx context.Context, msg mailer.Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return mailer.ErrInvalidRecipient
+	}
+
+	var body bytes.Buffer
+	if err := msg.Encode(&body, m.from, time.Now()); err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+
+	if m.auth != nil {
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(m.from.Address); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,178 @@
+package smtp_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/mailer"
+	"github.com/mattismoel/konnekt/internal/mailer/smtp"
+)
+
+// A received mail of the fake server.
+type received struct {
+	from string
+	to   string
+	data string
+}
+
+// Serves a single SMTP session on a local listener, without any extensions,
+// returning the port of the server and the mail received.
+func fakeServer(t *testing.T) (int, <-chan received) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { l.Close() })
+
+	out := make(chan received, 1)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var mail received
+		reply("220 localhost ready")
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			line = strings.TrimRight(line, "\r\n")
+			cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+			switch {
+			case cmd == "EHLO" || cmd == "HELO":
+				reply("250 localhost")
+			case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+				mail.from = line[len("MAIL FROM:"):]
+				reply("250 OK")
+			case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+				mail.to = line[len("RCPT TO:"):]
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 Go ahead")
+
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+
+					if l == ".\r\n" {
+						break
+					}
+
+					data.WriteString(l)
+				}
+
+				mail.data = data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				out <- mail
+				return
+			default:
+				reply("502 Not implemented")
+			}
+		}
+	}()
+
+	return l.Addr().(*net.TCPAddr).Port, out
+}
+
+func TestSend(t *testing.T) {
+	port, out := fakeServer(t)
+
+	m, err := smtp.NewSMTPMailer("127.0.0.1", port, "Konnekt <noreply@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = m.Send(ctx, mailer.Message{To: "Ann <a@example.com>", Subject: "Hi", Body: "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-out
+
+	if got.from != "<noreply@example.com>" {
+		t.Fatalf("got sender %q, want %q", got.from, "<noreply@example.com>")
+	}
+
+	if got.to != "<a@example.com>" {
+		t.Fatalf("got recipient %q, want %q", got.to, "<a@example.com>")
+	}
+
+	for _, want := range []string{"Subject: Hi\r\n", "\r\n\r\nHello"} {
+		if !strings.Contains(got.data, want) {
+			t.Fatalf("got data %q, want it to contain %q", got.data, want)
+		}
+	}
+}
+
+func TestNewSMTPMailer(t *testing.T) {
+	type test struct {
+		host    string
+		port    int
+		from    string
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Valid mailer":   {host: "localhost", port: 587, from: "noreply@example.com"},
+		"Empty host":     {port: 587, from: "noreply@example.com", wantErr: smtp.ErrInvalidHost},
+		"Zero port":      {host: "localhost", from: "noreply@example.com", wantErr: smtp.ErrInvalidPort},
+		"Too large port": {host: "localhost", port: 65536, from: "noreply@example.com", wantErr: smtp.ErrInvalidPort},
+		"Invalid sender": {host: "localhost", port: 587, from: "nope", wantErr: mailer.ErrInvalidSender},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := smtp.NewSMTPMailer(tt.host, tt.port, tt.from)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Sending to a server which is not listening fails rather than blocking.
+func TestSendUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	m, err := smtp.NewSMTPMailer("127.0.0.1", port, "noreply@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Send(context.Background(), mailer.Message{To: "a@example.com", Subject: "Hi"}); err == nil {
+		t.Fatal("got nil, want error")
+	}
+}
@@ -0,0 +1,208 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/mattismoel/konnekt/internal/object"
+)
+
+var (
+	ErrInvalidKey     = errors.New("Object key must be a non-empty path within the store")
+	ErrInvalidBaseURL = errors.New("Object base URL must be an absolute URL")
+)
+
+var _ object.Store = (*LocalObjectStore)(nil)
+
+// An object store keeping objects as files beneath a root directory. Objects
+// are served by the application beneath the base URL.
+type LocalObjectStore struct {
+	root    *os.Root
+	baseURL *url.URL
+}
+
+// Creates a store writing beneath the root directory, which is created if it
+// does not exist. The URLs of objects are their keys beneath the base URL,
+// such as "http://localhost:8080/objects/events/image.jpeg".
+func NewLocalObjectStore(root string, baseURL string) (*LocalObjectStore, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || !u.IsAbs() {
+		return nil, ErrInvalidBaseURL
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	r, err := os.OpenRoot(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalObjectStore{
+		root:    r,
+		baseURL: u,
+	}, nil
+}
+
+func (s LocalObjectStore) Upload(ctx context.Context, key string, body io.Reader) (string, error) {
+	name, err := s.name(key)
+	if err != nil {
+		return "", err
+	}
+
+	if dir := path.Dir(name); dir != "." {
+		if err := s.mkdirAll(dir); err != nil {
+			return "", err
+		}
+	}
+
+	f, err := s.root.Create(name)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		s.root.Remove(name)
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	return s.baseURL.JoinPath(name).String(), nil
+}
+
+func (s LocalObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.name(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := s.root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Deletes the object. Deleting an object, which does not exist, is not an
+// error, in line with S3.
+func (s LocalObjectStore) Delete(ctx context.Context, key string) error {
+	name, err := s.name(key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.root.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Lists the objects beneath the given directory. A directory, which does not
+// exist, has no objects.
+func (s LocalObjectStore) List(ctx context.Context, dir string) ([]object.Info, error) {
+	name, err := s.name(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]object.Info, 0)
+
+	err = fs.WalkDir(s.root.FS(), name, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		infos = append(infos, object.Info{
+			Key:     "/" + p,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// Returns the key of the object of the given URL.
+func (s LocalObjectStore) Key(objectURL string) (string, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", ErrInvalidKey
+	}
+
+	name, err := s.name(u.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return "/" + name, nil
+}
+
+// Returns the objects of the store as a file system, for serving them. The
+// file system cannot be used to access files outside the root directory.
+func (s LocalObjectStore) FS() fs.FS {
+	return s.root.FS()
+}
+
+// Returns the name of the file of the object with the given key, relative to
+// the root directory. Keys may also be given as the path of the object URL, as
+// the services do when deleting objects by their URL.
+func (s LocalObjectStore) name(key string) (string, error) {
+	name := path.Clean("/" + key)
+
+	if s.baseURL.Path != "" {
+		if trimmed, ok := strings.CutPrefix(name, s.baseURL.Path+"/"); ok {
+			name = "/" + trimmed
+		}
+	}
+
+	name = strings.TrimPrefix(name, "/")
+
+	if name == "" || !fs.ValidPath(name) {
+		return "", ErrInvalidKey
+	}
+
+	return name, nil
+}
+
+func (s LocalObjectStore) mkdirAll(dir string) error {
+	current := ""
+	for _, part := range strings.Split(dir, "/") {
+		current = path.Join(current, part)
+
+		if err := s.root.Mkdir(current, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/object/local"
+)
+
+const BASE_URL = "http://localhost:8080/objects"
+
+func TestUpload(t *testing.T) {
+	type test struct {
+		key     string
+		wantURL string
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Top level key":      {key: "image.jpeg", wantURL: BASE_URL + "/image.jpeg"},
+		"Nested key":         {key: "/events/image.jpeg", wantURL: BASE_URL + "/events/image.jpeg"},
+		"Unclean key":        {key: "events//a/../image.jpeg", wantURL: BASE_URL + "/events/image.jpeg"},
+		"Traversal confined": {key: "../../image.jpeg", wantURL: BASE_URL + "/image.jpeg"},
+		"URL path as key":    {key: "/objects/events/image.jpeg", wantURL: BASE_URL + "/events/image.jpeg"},
+		"Empty key":          {key: "", wantErr: local.ErrInvalidKey},
+		"Root key":           {key: "/", wantErr: local.ErrInvalidKey},
+		"Parent of root key": {key: "..", wantErr: local.ErrInvalidKey},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+
+			store, err := local.NewLocalObjectStore(root, BASE_URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			url, err := store.Upload(context.Background(), tt.key, strings.NewReader("content"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if url != tt.wantURL {
+				t.Fatalf("got %q, want %q", url, tt.wantURL)
+			}
+
+			if err != nil {
+				return
+			}
+
+			// The object must be written beneath the root.
+			rel := strings.TrimPrefix(tt.wantURL, BASE_URL+"/")
+			if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel))); err != nil {
+				t.Fatalf("got %v, want object beneath root", err)
+			}
+		})
+	}
+}
+
+func TestGetDelete(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := local.NewLocalObjectStore(t.TempDir(), BASE_URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Upload(ctx, "/events/image.jpeg", strings.NewReader("content")); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := store.Get(ctx, "/events/image.jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := io.ReadAll(body)
+	body.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "content" {
+		t.Fatalf("got %q, want %q", content, "content")
+	}
+
+	// Objects are deleted by the path of their URL.
+	if err := store.Delete(ctx, "/objects/events/image.jpeg"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get(ctx, "/events/image.jpeg"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("got %v, want %v", err, fs.ErrNotExist)
+	}
+
+	if err := store.Delete(ctx, "/events/image.jpeg"); err != nil {
+		t.Fatalf("got %v, want deleting a missing object to succeed", err)
+	}
+}
+
+func TestSymlinkEscape(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skip(err)
+	}
+
+	store, err := local.NewLocalObjectStore(root, BASE_URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get(context.Background(), "link/secret"); err == nil {
+		t.Fatal("got object outside root, want error")
+	}
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := local.NewLocalObjectStore(t.TempDir(), BASE_URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"/events/a.jpeg", "/events/nested/b.jpeg", "/artists/c.jpeg"} {
+		if _, err := store.Upload(ctx, key, strings.NewReader("content")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type test struct {
+		dir  string
+		want []string
+	}
+
+	tests := map[string]test{
+		"Directory":         {dir: "/events", want: []string{"/events/a.jpeg", "/events/nested/b.jpeg"}},
+		"URL path as dir":   {dir: "/objects/artists", want: []string{"/artists/c.jpeg"}},
+		"Missing directory": {dir: "/members", want: []string{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			infos, err := store.List(ctx, tt.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0, len(infos))
+			for _, info := range infos {
+				if info.Size != int64(len("content")) {
+					t.Fatalf("got size %d, want %d", info.Size, len("content"))
+				}
+
+				got = append(got, info.Key)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	type test struct {
+		url     string
+		want    string
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Object URL":  {url: BASE_URL + "/events/image.jpeg", want: "/events/image.jpeg"},
+		"Unclean URL": {url: BASE_URL + "//events/../events/image.jpeg", want: "/events/image.jpeg"},
+		"Invalid URL": {url: "http://[::1", wantErr: local.ErrInvalidKey},
+	}
+
+	store, err := local.NewLocalObjectStore(t.TempDir(), BASE_URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := store.Key(tt.url)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,16 @@ package s3
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -16,6 +20,12 @@ import (
 
 var DEFAULT_CACHE_CONTROL_MS = 2 * time.Hour.Milliseconds()
 
+var (
+	ErrInvalidEndpoint      = errors.New("S3 endpoint must be an absolute URL")
+	ErrInvalidPublicBaseURL = errors.New("S3 public base URL must be an absolute URL")
+	ErrEmptyCredentials     = errors.New("S3 access key ID and secret access key must not be empty")
+)
+
 var _ object.Store = (*S3ObjectStore)(nil)
 
 type S3ObjectStore struct {
@@ -24,25 +34,111 @@ type S3ObjectStore struct {
 	uploader   *s3manager.Uploader
 	downloader *s3manager.Downloader
 	client     *s3.S3
+
+	// The endpoint of an S3-compatible provider, such as MinIO. AWS is used
+	// if nil.
+	endpoint *url.URL
+
+	// Whether or not the bucket is addressed by the path of URLs rather than
+	// by their host name.
+	pathStyle bool
+
+	credentials *credentials.Credentials
+
+	// The base URL of returned object URLs, such as the domain of a CDN in
+	// front of the bucket.
+	publicBaseURL *url.URL
 }
 
-func NewS3ObjectStore(region string, bucket string) (*S3ObjectStore, error) {
+type CfgFunc func(s *S3ObjectStore) error
+
+func NewS3ObjectStore(region string, bucket string, cfgs ...CfgFunc) (*S3ObjectStore, error) {
+	s := &S3ObjectStore{
+		region: region,
+		bucket: bucket,
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(s); err != nil {
+			return nil, err
+		}
+	}
+
 	config := aws.NewConfig().
-		WithRegion(region)
+		WithRegion(region).
+		WithS3ForcePathStyle(s.pathStyle)
+
+	if s.endpoint != nil {
+		config = config.WithEndpoint(s.endpoint.String())
+	}
+
+	if s.credentials != nil {
+		config = config.WithCredentials(s.credentials)
+	}
 
 	sess, err := session.NewSession(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &S3ObjectStore{
-		region: region,
-		bucket: bucket,
+	s.client = s3.New(sess)
+	s.uploader = s3manager.NewUploader(sess)
+	s.downloader = s3manager.NewDownloader(sess)
 
-		client:     s3.New(sess),
-		uploader:   s3manager.NewUploader(sess),
-		downloader: s3manager.NewDownloader(sess),
-	}, nil
+	return s, nil
+}
+
+// Uses the S3-compatible provider at the given endpoint, such as
+// "http://localhost:9000" for a local MinIO.
+func WithEndpoint(endpoint string) CfgFunc {
+	return func(s *S3ObjectStore) error {
+		u, err := url.Parse(endpoint)
+		if err != nil || !u.IsAbs() || u.Host == "" {
+			return ErrInvalidEndpoint
+		}
+
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		s.endpoint = u
+
+		return nil
+	}
+}
+
+// Addresses the bucket by the path of URLs, as required by most S3-compatible
+// providers, rather than by a subdomain.
+func WithPathStyle(pathStyle bool) CfgFunc {
+	return func(s *S3ObjectStore) error {
+		s.pathStyle = pathStyle
+		return nil
+	}
+}
+
+// Uses the given static credentials rather than those of the environment.
+func WithCredentials(accessKeyID, secretAccessKey string) CfgFunc {
+	return func(s *S3ObjectStore) error {
+		if strings.TrimSpace(accessKeyID) == "" || strings.TrimSpace(secretAccessKey) == "" {
+			return ErrEmptyCredentials
+		}
+
+		s.credentials = credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "")
+		return nil
+	}
+}
+
+// Returns object URLs beneath the given base URL rather than those of the
+// bucket.
+func WithPublicBaseURL(baseURL string) CfgFunc {
+	return func(s *S3ObjectStore) error {
+		u, err := url.Parse(baseURL)
+		if err != nil || !u.IsAbs() || u.Host == "" {
+			return ErrInvalidPublicBaseURL
+		}
+
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		s.publicBaseURL = u
+
+		return nil
+	}
 }
 
 func (s S3ObjectStore) Upload(ctx context.Context, key string, body io.Reader) (string, error) {
@@ -57,12 +153,16 @@ func (s S3ObjectStore) Upload(ctx context.Context, key string, body io.Reader) (
 		return "", err
 	}
 
+	if s.publicBaseURL != nil {
+		return s.ObjectPath(key), nil
+	}
+
 	return output.Location, nil
 }
 
 func (s S3ObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
 	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
-		Key:    aws.String(key),
+		Key:    aws.String(s.key(key)),
 		Bucket: aws.String(s.bucket),
 	})
 
@@ -75,7 +175,7 @@ func (s S3ObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, erro
 
 func (s S3ObjectStore) Delete(ctx context.Context, key string) error {
 	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
-		Key:    aws.String(key),
+		Key:    aws.String(s.key(key)),
 		Bucket: aws.String(s.bucket),
 	})
 
@@ -86,8 +186,89 @@ func (s S3ObjectStore) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Lists the objects beneath the given directory.
+func (s S3ObjectStore) List(ctx context.Context, dir string) ([]object.Info, error) {
+	prefix := strings.Trim(path.Clean("/"+s.key(dir)), "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	infos := make([]object.Info, 0)
+
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			infos = append(infos, object.Info{
+				Key:     "/" + strings.TrimPrefix(aws.StringValue(obj.Key), "/"),
+				Size:    aws.Int64Value(obj.Size),
+				ModTime: aws.TimeValue(obj.LastModified),
+			})
+		}
+
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// Returns the key of the object of the given URL.
+func (s S3ObjectStore) Key(objectURL string) (string, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Clean("/" + s.key(u.Path)), nil
+}
+
+// Returns the public URL of the object with the given key.
 func (s S3ObjectStore) ObjectPath(key string) string {
-	key = path.Clean(key)
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+
+	switch {
+	case s.publicBaseURL != nil:
+		return s.publicBaseURL.JoinPath(key).String()
+	case s.endpoint != nil && s.pathStyle:
+		return s.endpoint.JoinPath(s.bucket, key).String()
+	case s.endpoint != nil:
+		u := *s.endpoint
+		u.Host = s.bucket + "." + u.Host
+		return u.JoinPath(key).String()
+	case s.pathStyle:
+		return fmt.Sprintf("https://s3.%s.amazonaws.com/%s/%s", s.region, s.bucket, key)
+	default:
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, key)
+	}
+}
+
+// Returns the key of an object. Keys may also be given as the path of the
+// object URL, as the services do when deleting objects by their URL, in which
+// case the path of the base URL and bucket is removed.
+func (s S3ObjectStore) key(key string) string {
+	prefix := ""
+
+	switch {
+	case s.publicBaseURL != nil:
+		prefix = s.publicBaseURL.Path
+	case s.pathStyle && s.endpoint != nil:
+		prefix = s.endpoint.Path + "/" + s.bucket
+	case s.pathStyle:
+		prefix = "/" + s.bucket
+	}
+
+	if prefix == "" {
+		return key
+	}
+
+	if trimmed, ok := strings.CutPrefix(key, prefix+"/"); ok {
+		return "/" + trimmed
+	}
 
-	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, key)
+	return key
 }
@@ -0,0 +1,182 @@
+//go:build integration
+
+// The integration tests run against an S3-compatible provider, such as a local
+// MinIO:
+//
+//	docker run --rm -p 9000:9000 minio/minio server /data
+//	S3_TEST_ENDPOINT=http://localhost:9000 \
+//	S3_TEST_ACCESS_KEY=minioadmin \
+//	S3_TEST_SECRET_KEY=minioadmin \
+//	go test -tags integration ./internal/object/s3/
+//
+// The bucket, which defaults to konnekt-test, is created if it does not exist.
+package s3_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/google/uuid"
+	"github.com/mattismoel/konnekt/internal/object"
+	"github.com/mattismoel/konnekt/internal/object/s3"
+)
+
+const TEST_REGION = "us-east-1"
+
+type integrationEnv struct {
+	endpoint  string
+	bucket    string
+	accessKey string
+	secretKey string
+}
+
+func newIntegrationEnv(t *testing.T) integrationEnv {
+	t.Helper()
+
+	env := integrationEnv{
+		endpoint:  os.Getenv("S3_TEST_ENDPOINT"),
+		bucket:    os.Getenv("S3_TEST_BUCKET"),
+		accessKey: os.Getenv("S3_TEST_ACCESS_KEY"),
+		secretKey: os.Getenv("S3_TEST_SECRET_KEY"),
+	}
+
+	if env.endpoint == "" || env.accessKey == "" || env.secretKey == "" {
+		t.Skip("S3_TEST_ENDPOINT, S3_TEST_ACCESS_KEY and S3_TEST_SECRET_KEY must be set")
+	}
+
+	if env.bucket == "" {
+		env.bucket = "konnekt-test"
+	}
+
+	sess, err := session.NewSession(aws.NewConfig().
+		WithRegion(TEST_REGION).
+		WithEndpoint(env.endpoint).
+		WithS3ForcePathStyle(true).
+		WithCredentials(credentials.NewStaticCredentials(env.accessKey, env.secretKey, "")),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = awss3.New(sess).CreateBucket(&awss3.CreateBucketInput{Bucket: aws.String(env.bucket)})
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && (awsErr.Code() == awss3.ErrCodeBucketAlreadyOwnedByYou || awsErr.Code() == awss3.ErrCodeBucketAlreadyExists) {
+		err = nil
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env
+}
+
+func (env integrationEnv) store(t *testing.T, cfgs ...s3.CfgFunc) *s3.S3ObjectStore {
+	t.Helper()
+
+	store, err := s3.NewS3ObjectStore(TEST_REGION, env.bucket, append([]s3.CfgFunc{
+		s3.WithEndpoint(env.endpoint),
+		s3.WithPathStyle(true),
+		s3.WithCredentials(env.accessKey, env.secretKey),
+	}, cfgs...)...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func TestIntegrationRoundTrip(t *testing.T) {
+	env := newIntegrationEnv(t)
+
+	type test struct {
+		cfgs []s3.CfgFunc
+	}
+
+	tests := map[string]test{
+		"Bucket URL":      {},
+		"Public base URL": {cfgs: []s3.CfgFunc{s3.WithPublicBaseURL("https://cdn.konnekt.dk/media")}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := env.store(t, tt.cfgs...)
+
+			key := "/events/" + uuid.NewString() + ".txt"
+			content := []byte("konnekt")
+
+			objectURL, err := store.Upload(ctx, key, bytes.NewReader(content))
+			if err != nil {
+				t.Fatalf("upload: got %v, want nil", err)
+			}
+
+			if want := store.ObjectPath(key); objectURL != want {
+				t.Fatalf("got URL %q, want %q", objectURL, want)
+			}
+
+			u, err := url.Parse(objectURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotKey, err := store.Key(objectURL)
+			if err != nil || gotKey != key {
+				t.Fatalf("key: got %q, %v, want %q", gotKey, err, key)
+			}
+
+			infos, err := store.List(ctx, "/events")
+			if err != nil {
+				t.Fatalf("list: got %v, want nil", err)
+			}
+
+			if !slices.ContainsFunc(infos, func(info object.Info) bool { return info.Key == key }) {
+				t.Fatalf("list: got %v, want %q", infos, key)
+			}
+
+			// Objects are retrieved and deleted by the path of their URL, as
+			// done by the services.
+			rc, err := store.Get(ctx, u.Path)
+			if err != nil {
+				t.Fatalf("get: got %v, want nil", err)
+			}
+
+			got, err := io.ReadAll(rc)
+			rc.Close()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, content) {
+				t.Fatalf("got content %q, want %q", got, content)
+			}
+
+			if err := store.Delete(ctx, u.Path); err != nil {
+				t.Fatalf("delete: got %v, want nil", err)
+			}
+
+			_, err = store.Get(ctx, u.Path)
+
+			var reqErr awserr.RequestFailure
+			if !errors.As(err, &reqErr) || reqErr.StatusCode() != http.StatusNotFound {
+				t.Fatalf("get after delete: got %v, want not found", err)
+			}
+		})
+	}
+}
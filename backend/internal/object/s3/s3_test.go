@@ -0,0 +1,135 @@
+package s3_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/object/s3"
+)
+
+func TestObjectPath(t *testing.T) {
+	type test struct {
+		cfgs    []s3.CfgFunc
+		key     string
+		wantURL string
+	}
+
+	tests := map[string]test{
+		"AWS": {
+			key:     "/events/image.jpeg",
+			wantURL: "https://bucket.s3.eu-north-1.amazonaws.com/events/image.jpeg",
+		},
+		"AWS path style": {
+			cfgs:    []s3.CfgFunc{s3.WithPathStyle(true)},
+			key:     "/events/image.jpeg",
+			wantURL: "https://s3.eu-north-1.amazonaws.com/bucket/events/image.jpeg",
+		},
+		"Endpoint path style": {
+			cfgs:    []s3.CfgFunc{s3.WithEndpoint("http://localhost:9000"), s3.WithPathStyle(true)},
+			key:     "/events/image.jpeg",
+			wantURL: "http://localhost:9000/bucket/events/image.jpeg",
+		},
+		"Endpoint virtual host": {
+			cfgs:    []s3.CfgFunc{s3.WithEndpoint("https://fra1.digitaloceanspaces.com/")},
+			key:     "events/image.jpeg",
+			wantURL: "https://bucket.fra1.digitaloceanspaces.com/events/image.jpeg",
+		},
+		"Public base URL": {
+			cfgs:    []s3.CfgFunc{s3.WithEndpoint("http://localhost:9000"), s3.WithPublicBaseURL("https://cdn.konnekt.dk/media/")},
+			key:     "/events/image.jpeg",
+			wantURL: "https://cdn.konnekt.dk/media/events/image.jpeg",
+		},
+		"Unclean key": {
+			cfgs:    []s3.CfgFunc{s3.WithPublicBaseURL("https://cdn.konnekt.dk")},
+			key:     "events//a/../image.jpeg",
+			wantURL: "https://cdn.konnekt.dk/events/image.jpeg",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, err := s3.NewS3ObjectStore("eu-north-1", "bucket", tt.cfgs...)
+			if err != nil {
+				t.Fatalf("got %v, want nil", err)
+			}
+
+			if got := store.ObjectPath(tt.key); got != tt.wantURL {
+				t.Fatalf("got %q, want %q", got, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	type test struct {
+		cfgs []s3.CfgFunc
+		url  string
+		want string
+	}
+
+	tests := map[string]test{
+		"AWS": {
+			url:  "https://bucket.s3.eu-north-1.amazonaws.com/events/image.jpeg",
+			want: "/events/image.jpeg",
+		},
+		"AWS path style": {
+			cfgs: []s3.CfgFunc{s3.WithPathStyle(true)},
+			url:  "https://s3.eu-north-1.amazonaws.com/bucket/events/image.jpeg",
+			want: "/events/image.jpeg",
+		},
+		"Endpoint path style": {
+			cfgs: []s3.CfgFunc{s3.WithEndpoint("http://localhost:9000"), s3.WithPathStyle(true)},
+			url:  "http://localhost:9000/bucket/events/image.jpeg",
+			want: "/events/image.jpeg",
+		},
+		"Public base URL": {
+			cfgs: []s3.CfgFunc{s3.WithPublicBaseURL("https://cdn.konnekt.dk/media/")},
+			url:  "https://cdn.konnekt.dk/media/events/image.jpeg",
+			want: "/events/image.jpeg",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, err := s3.NewS3ObjectStore("eu-north-1", "bucket", tt.cfgs...)
+			if err != nil {
+				t.Fatalf("got %v, want nil", err)
+			}
+
+			got, err := store.Key(tt.url)
+			if err != nil {
+				t.Fatalf("got %v, want nil", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewS3ObjectStore(t *testing.T) {
+	type test struct {
+		cfgs    []s3.CfgFunc
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"No options":          {},
+		"Valid options":       {cfgs: []s3.CfgFunc{s3.WithEndpoint("http://localhost:9000"), s3.WithPathStyle(true), s3.WithCredentials("key", "secret"), s3.WithPublicBaseURL("https://cdn.konnekt.dk")}},
+		"Relative endpoint":   {cfgs: []s3.CfgFunc{s3.WithEndpoint("localhost:9000/objects")}, wantErr: s3.ErrInvalidEndpoint},
+		"Invalid endpoint":    {cfgs: []s3.CfgFunc{s3.WithEndpoint("http://local host")}, wantErr: s3.ErrInvalidEndpoint},
+		"Empty access key":    {cfgs: []s3.CfgFunc{s3.WithCredentials("", "secret")}, wantErr: s3.ErrEmptyCredentials},
+		"Empty secret key":    {cfgs: []s3.CfgFunc{s3.WithCredentials("key", " ")}, wantErr: s3.ErrEmptyCredentials},
+		"Relative public URL": {cfgs: []s3.CfgFunc{s3.WithPublicBaseURL("/objects")}, wantErr: s3.ErrInvalidPublicBaseURL},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := s3.NewS3ObjectStore("eu-north-1", "bucket", tt.cfgs...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
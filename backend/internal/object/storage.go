@@ -3,8 +3,17 @@ package object
 import (
 	"context"
 	"io"
+	"time"
 )
 
+// Information of a stored object.
+type Info struct {
+	// The key of the object, such as "/events/image.jpeg".
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
 type Store interface {
 	// Uploads the input reader stream to the specified path, returning the
 	// location of where the object was uploaded to.
@@ -15,4 +24,10 @@ type Store interface {
 
 	//Deletes an object given its path.
 	Delete(context.Context, string) error
+
+	// Lists all objects beneath the given directory, such as "/events".
+	List(ctx context.Context, dir string) ([]Info, error)
+
+	// Returns the key of the object of the given URL, as returned by Upload.
+	Key(objectURL string) (string, error)
 }
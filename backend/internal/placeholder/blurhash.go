@@ -0,0 +1,156 @@
+// Package placeholder computes compact representations of images, which are
+// shown while the images themselves load.
+package placeholder
+
+import (
+	"errors"
+	"image"
+	"math"
+	"strings"
+)
+
+const (
+	// The default amount of horizontal and vertical components of BlurHashes.
+	DEFAULT_X_COMPONENTS = 4
+	DEFAULT_Y_COMPONENTS = 3
+
+	MAX_COMPONENTS = 9
+)
+
+var (
+	ErrInvalidComponents = errors.New("BlurHash components must be between 1 and 9")
+	ErrEmptyImage        = errors.New("Image must not be empty")
+)
+
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Encodes the image as a BlurHash of the given amount of components, as
+// described at https://blurha.sh. Images should be scaled down beforehand, as
+// every pixel is visited once per component.
+func BlurHash(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > MAX_COMPONENTS || yComponents < 1 || yComponents > MAX_COMPONENTS {
+		return "", ErrInvalidComponents
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= 0 || height <= 0 {
+		return "", ErrEmptyImage
+	}
+
+	// The linear colour of every pixel is computed once, rather than once per
+	// component.
+	pixels := make([][3]float64, 0, width*height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			pixels = append(pixels, [3]float64{
+				sRGBToLinear(r >> 8),
+				sRGBToLinear(g >> 8),
+				sRGBToLinear(b >> 8),
+			})
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+
+	for j := range yComponents {
+		for i := range xComponents {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+
+			var factor [3]float64
+			for y := range height {
+				for x := range width {
+					basis := normalisation *
+						math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) *
+						math.Cos(math.Pi*float64(j)*float64(y)/float64(height))
+
+					pixel := pixels[y*width+x]
+					factor[0] += basis * pixel[0]
+					factor[1] += basis * pixel[1]
+					factor[2] += basis * pixel[2]
+				}
+			}
+
+			scale := 1 / float64(width*height)
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	dc, ac := factors[0], factors[1:]
+
+	var hash strings.Builder
+	hash.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	maximumValue := 1.0
+	if len(ac) > 0 {
+		actualMaximumValue := 0.0
+		for _, factor := range ac {
+			actualMaximumValue = max(actualMaximumValue, math.Abs(factor[0]), math.Abs(factor[1]), math.Abs(factor[2]))
+		}
+
+		quantisedMaximumValue := int(max(0, min(82, math.Floor(actualMaximumValue*166-0.5))))
+		maximumValue = float64(quantisedMaximumValue+1) / 166
+
+		hash.WriteString(encode83(quantisedMaximumValue, 1))
+	} else {
+		hash.WriteString(encode83(0, 1))
+	}
+
+	hash.WriteString(encode83(encodeDC(dc), 4))
+
+	for _, factor := range ac {
+		hash.WriteString(encode83(encodeAC(factor, maximumValue), 2))
+	}
+
+	return hash.String(), nil
+}
+
+func encodeDC(value [3]float64) int {
+	return linearToSRGB(value[0])<<16 + linearToSRGB(value[1])<<8 + linearToSRGB(value[2])
+}
+
+func encodeAC(value [3]float64, maximumValue float64) int {
+	quantise := func(v float64) int {
+		return int(max(0, min(18, math.Floor(signPow(v/maximumValue, 0.5)*9+9.5))))
+	}
+
+	return quantise(value[0])*19*19 + quantise(value[1])*19 + quantise(value[2])
+}
+
+func encode83(value int, length int) string {
+	var b strings.Builder
+
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		b.WriteByte(base83Chars[digit])
+	}
+
+	return b.String()
+}
+
+func sRGBToLinear(value uint32) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+	v := max(0, min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(value float64, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
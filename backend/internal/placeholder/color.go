@@ -0,0 +1,58 @@
+package placeholder
+
+import (
+	"fmt"
+	"image"
+)
+
+// Returns the dominant colour of the image as a hexadecimal string of format
+// #rrggbb. Colours are grouped into buckets of similar colours, and the
+// average colour of the most common bucket is returned. Mostly transparent
+// pixels are ignored. Returns an empty string, if all pixels are transparent.
+func DominantColor(img image.Image) string {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+
+	buckets := make(map[uint32]*bucket)
+	var dominant *bucket
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+
+			// Colours are alpha-premultiplied.
+			r, g, b = (r*0xffff/a)>>8, (g*0xffff/a)>>8, (b*0xffff/a)>>8
+
+			// Buckets are made of the four most significant bits of every
+			// channel.
+			key := (r>>4)<<8 | (g>>4)<<4 | b>>4
+
+			bk, ok := buckets[key]
+			if !ok {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+
+			bk.count++
+			bk.r += int(r)
+			bk.g += int(g)
+			bk.b += int(b)
+
+			if dominant == nil || bk.count > dominant.count {
+				dominant = bk
+			}
+		}
+	}
+
+	if dominant == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", dominant.r/dominant.count, dominant.g/dominant.count, dominant.b/dominant.count)
+}
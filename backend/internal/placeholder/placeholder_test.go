@@ -0,0 +1,130 @@
+package placeholder_test
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/placeholder"
+)
+
+func uniform(c color.Color, width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+// Returns an image, whose left columns are of the first colour and remaining
+// columns are of the second colour.
+func split(a, b color.Color, aColumns, width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			c := b
+			if x < aColumns {
+				c = a
+			}
+
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func TestBlurHash(t *testing.T) {
+	type test struct {
+		img         image.Image
+		xComponents int
+		yComponents int
+		want        string
+		wantDC      string
+		wantLen     int
+		wantErr     error
+	}
+
+	tests := map[string]test{
+		"Black": {
+			img:         uniform(color.Black, 16, 16),
+			xComponents: 4,
+			yComponents: 3,
+			want:        "L00000fQfQfQfQfQfQfQfQfQfQfQ",
+		},
+		"White": {
+			img:         uniform(color.White, 16, 16),
+			xComponents: 4,
+			yComponents: 3,
+			wantDC:      "TSUA",
+			wantLen:     28,
+		},
+		"Single component": {
+			img:         uniform(color.Black, 8, 8),
+			xComponents: 1,
+			yComponents: 1,
+			want:        "000000",
+		},
+		"Varying image": {
+			img:         split(color.Black, color.White, 8, 32, 16),
+			xComponents: 4,
+			yComponents: 3,
+			wantLen:     28,
+		},
+		"Too few components":  {img: uniform(color.Black, 8, 8), xComponents: 0, yComponents: 3, wantErr: placeholder.ErrInvalidComponents},
+		"Too many components": {img: uniform(color.Black, 8, 8), xComponents: 4, yComponents: 10, wantErr: placeholder.ErrInvalidComponents},
+		"Empty image":         {img: image.NewRGBA(image.Rect(0, 0, 0, 0)), xComponents: 4, yComponents: 3, wantErr: placeholder.ErrEmptyImage},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := placeholder.BlurHash(tt.img, tt.xComponents, tt.yComponents)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.want != "" && got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+
+			// The average colour is encoded by the four characters
+			// following the size and maximum value.
+			if tt.wantDC != "" && got[2:6] != tt.wantDC {
+				t.Fatalf("got average colour %q, want %q", got[2:6], tt.wantDC)
+			}
+
+			if tt.wantLen > 0 && len(got) != tt.wantLen {
+				t.Fatalf("got length %d, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	type test struct {
+		img  image.Image
+		want string
+	}
+
+	red := color.RGBA{R: 0xe0, G: 0x10, B: 0x20, A: 0xff}
+	blue := color.RGBA{R: 0x10, G: 0x20, B: 0xe0, A: 0xff}
+
+	tests := map[string]test{
+		"Uniform":          {img: uniform(red, 8, 8), want: "#e01020"},
+		"Most common":      {img: split(red, blue, 3, 10, 4), want: "#1020e0"},
+		"Transparent":      {img: uniform(color.Transparent, 8, 8), want: ""},
+		"Half transparent": {img: split(color.Transparent, red, 7, 10, 4), want: "#e01020"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := placeholder.DominantColor(tt.img); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -49,6 +49,10 @@ type ListQuery struct {
 
 	// The filters part of this query.
 	Filters FilterCollection
+
+	// The free text to search for. Only records matching all words of the
+	// text are included. Empty means no search.
+	Search string
 }
 
 type ListResult[T any] struct {
@@ -128,6 +132,13 @@ func WithLimit(limit int) CfgFunc {
 	}
 }
 
+func WithSearch(search string) CfgFunc {
+	return func(q *ListQuery) error {
+		q.Search = strings.TrimSpace(search)
+		return nil
+	}
+}
+
 // Applies ordering to a ListQuery.
 //
 // Example:
@@ -186,6 +197,10 @@ func (q1 ListQuery) Equals(q2 ListQuery) bool {
 		return false
 	}
 
+	if q1.Search != q2.Search {
+		return false
+	}
+
 	if len(q1.Filters) != len(q2.Filters) {
 		return false
 	}
@@ -3,6 +3,7 @@ package server
 import (
 	"encoding/json"
 	"github.com/mattismoel/konnekt/internal/domain/artist"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/service"
 	"net/http"
 )
@@ -50,12 +51,12 @@ func (s Server) handleGetArtistByID() http.HandlerFunc {
 
 func (s Server) handleCreateArtist() http.HandlerFunc {
 	type createArtistLoad struct {
-		Name        string   `json:"name"`
-		Description string   `json:"description"`
-		ImageURL    string   `json:"imageUrl"`
-		GenreIDs    []int64  `json:"genreIds"`
-		PreviewURL  string   `json:"previewUrl"`
-		Socials     []string `json:"socials"`
+		Name        string      `json:"name"`
+		Description string      `json:"description"`
+		Image       media.Image `json:"image"`
+		GenreIDs    []int64     `json:"genreIds"`
+		PreviewURL  string      `json:"previewUrl"`
+		Socials     []string    `json:"socials"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -72,13 +73,18 @@ func (s Server) handleCreateArtist() http.HandlerFunc {
 		artistID, err := s.artistService.Create(ctx, service.CreateArtist{
 			Name:        load.Name,
 			Description: load.Description,
-			ImageURL:    load.ImageURL,
+			Image:       load.Image,
 			PreviewURL:  load.PreviewURL,
 			GenreIDs:    load.GenreIDs,
 			Socials:     load.Socials,
 		})
 
 		if err != nil {
+			if isImageError(err) {
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+				return
+			}
+
 			writeError(w, err)
 			return
 		}
@@ -95,12 +101,12 @@ func (s Server) handleCreateArtist() http.HandlerFunc {
 
 func (s Server) handleUpdateArtist() http.HandlerFunc {
 	type updateArtistLoad struct {
-		Name        string   `json:"name"`
-		Description string   `json:"description"`
-		ImageURL    string   `json:"imageUrl"`
-		PreviewURL  string   `json:"previewUrl"`
-		GenreIDs    []int64  `json:"genreIds"`
-		Socials     []string `json:"socials"`
+		Name        string      `json:"name"`
+		Description string      `json:"description"`
+		Image       media.Image `json:"image"`
+		PreviewURL  string      `json:"previewUrl"`
+		GenreIDs    []int64     `json:"genreIds"`
+		Socials     []string    `json:"socials"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -124,12 +130,17 @@ func (s Server) handleUpdateArtist() http.HandlerFunc {
 			Name:        load.Name,
 			Description: load.Description,
 			PreviewURL:  load.PreviewURL,
-			ImageURL:    load.ImageURL,
+			Image:       load.Image,
 			GenreIDs:    load.GenreIDs,
 			Socials:     load.Socials,
 		})
 
 		if err != nil {
+			if isImageError(err) {
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+				return
+			}
+
 			writeError(w, err)
 			return
 		}
@@ -148,7 +159,7 @@ func (s Server) handleUploadArtistImage() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		file, _, err := r.FormFile("image")
+		file, err := s.formImage(w, r, "image")
 		if err != nil {
 			writeError(w, err)
 			return
@@ -156,13 +167,25 @@ func (s Server) handleUploadArtistImage() http.HandlerFunc {
 
 		defer file.Close()
 
-		url, err := s.artistService.UploadImage(ctx, file)
+		framing, err := framingFromForm(r)
+		if err != nil {
+			writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			return
+		}
+
+		attribution, err := attributionFromForm(r)
+		if err != nil {
+			writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			return
+		}
+
+		img, err := s.artistService.UploadImage(ctx, file, framing, attribution)
 		if err != nil {
 			writeError(w, err)
 			return
 		}
 
-		writeText(w, http.StatusOK, url)
+		writeJSON(w, http.StatusOK, img)
 	}
 }
 
@@ -0,0 +1,13 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+// Returns the attribution of an uploaded image, given by the optional form
+// values altText, credit and license.
+func attributionFromForm(r *http.Request) (media.Attribution, error) {
+	return media.NewAttribution(r.FormValue("altText"), r.FormValue("credit"), r.FormValue("license"))
+}
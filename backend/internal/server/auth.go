@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
-	"net/url"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/mattismoel/konnekt/internal/domain/auth"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/domain/member"
 	"github.com/mattismoel/konnekt/internal/service"
 )
@@ -22,16 +24,34 @@ var (
 	ErrMemberAlreadyExists      = APIError{Message: "Member already exists", Status: http.StatusConflict}
 	ErrMemberInvalidCredentials = APIError{Message: "Member credentials are invalid", Status: http.StatusBadRequest}
 	ErrUnauthorized             = APIError{Message: "Member unauthorized", Status: http.StatusUnauthorized}
+	ErrSessionNoExist           = APIError{Message: "Session does not exist", Status: http.StatusNotFound}
+	ErrEmailNotVerified         = APIError{Message: "Member email is not verified", Status: http.StatusForbidden}
+	ErrMemberInactive           = APIError{Message: "Member is not yet approved", Status: http.StatusForbidden}
+	ErrRegistrationClosed       = APIError{Message: "Registration is closed", Status: http.StatusForbidden}
 )
 
+// Responds whether or not anyone may register, so that clients may hide
+// registration when members only join by invitation.
+func (s Server) handleGetRegistration() http.HandlerFunc {
+	type registrationResponse struct {
+		Open bool `json:"open"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, registrationResponse{
+			Open: s.authService.RegistrationOpen(),
+		})
+	}
+}
+
 func (s Server) handleRegister() http.HandlerFunc {
 	type RegisterLoad struct {
-		Email             string `json:"email"`
-		FirstName         string `json:"firstName"`
-		LastName          string `json:"lastName"`
-		Password          string `json:"password"`
-		PasswordConfirm   string `json:"passwordConfirm"`
-		ProfilePictureURL string `json:"profilePictureUrl"`
+		Email           string      `json:"email"`
+		FirstName       string      `json:"firstName"`
+		LastName        string      `json:"lastName"`
+		Password        string      `json:"password"`
+		PasswordConfirm string      `json:"passwordConfirm"`
+		ProfilePicture  media.Image `json:"profilePicture"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -45,33 +65,25 @@ func (s Server) handleRegister() http.HandlerFunc {
 		}
 
 		userID, err := s.authService.Register(ctx, service.RegisterLoad{
-			FirstName:         load.FirstName,
-			LastName:          load.LastName,
-			Email:             load.Email,
-			Password:          []byte(load.Password),
-			PasswordConfirm:   []byte(load.PasswordConfirm),
-			ProfilePictureURL: load.ProfilePictureURL,
+			FirstName:       load.FirstName,
+			LastName:        load.LastName,
+			Email:           load.Email,
+			Password:        []byte(load.Password),
+			PasswordConfirm: []byte(load.PasswordConfirm),
+			ProfilePicture:  load.ProfilePicture,
 		})
 
 		if err != nil {
 			switch {
 			case errors.Is(err, member.ErrAlreadyExists):
-				// Delete profile picuture from S3.
-				u, err := url.Parse(load.ProfilePictureURL)
-				if err != nil {
-					writeError(w, err)
-					return
-				}
-
-				err = s.memberService.DeleteProfilePicture(ctx, u.Path)
-				if err != nil {
-					writeError(w, err)
-					return
-				}
-
 				writeError(w, ErrMemberAlreadyExists)
 			case errors.Is(err, auth.ErrPasswordsNoMatch):
 				writeError(w, ErrPasswordsNoMatch)
+			case errors.Is(err, service.ErrRegistrationClosed):
+				writeError(w, ErrRegistrationClosed)
+			case errors.Is(err, media.ErrForeignImage),
+				errors.Is(err, member.ErrProfileImageMedia):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
 			default:
 				writeError(w, err)
 			}
@@ -92,12 +104,19 @@ func (s Server) handleRegister() http.HandlerFunc {
 	}
 }
 
+// Logs in the member. If the member must complete two-factor authentication,
+// the response is 202 Accepted with the pending step, and the session cookie
+// only allows completing it.
 func (s Server) handleLogin() http.HandlerFunc {
 	type LoginLoad struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
 	}
 
+	type pendingResponse struct {
+		Pending auth.PendingStep `json:"pending"`
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		var load LoginLoad
 
@@ -109,13 +128,19 @@ func (s Server) handleLogin() http.HandlerFunc {
 
 		ctx := r.Context()
 
-		token, expiry, err := s.authService.Login(ctx, load.Email, []byte(load.Password))
+		client := auth.NewClient(r.UserAgent(), clientIP(r))
+
+		token, expiry, err := s.authService.Login(ctx, load.Email, []byte(load.Password), client)
 		if err != nil {
 			switch {
 			case errors.Is(err, member.ErrNotFound):
 				writeError(w, ErrMemberInvalidCredentials)
 			case errors.Is(err, auth.ErrPasswordsNoMatch):
 				writeError(w, ErrMemberInvalidCredentials)
+			case errors.Is(err, service.ErrEmailNotVerified):
+				writeError(w, ErrEmailNotVerified)
+			case errors.Is(err, service.ErrMemberInactive):
+				writeError(w, ErrMemberInactive)
 			default:
 				writeError(w, err)
 			}
@@ -130,6 +155,11 @@ func (s Server) handleLogin() http.HandlerFunc {
 			return
 		}
 
+		if session.IsPending() {
+			writeJSON(w, http.StatusAccepted, pendingResponse{Pending: session.Pending})
+			return
+		}
+
 		usr, err := s.memberService.ByID(ctx, session.MemberID)
 		if err != nil {
 			writeError(w, err)
@@ -193,7 +223,15 @@ func (s Server) handleGetSession() http.HandlerFunc {
 
 		newExpiry, err := s.authService.ValidateSession(ctx, token)
 		if err != nil {
-			writeError(w, err)
+			switch {
+			case errors.Is(err, auth.ErrNoSession), errors.Is(err, auth.ErrInvalidSession):
+				clearSessionCookie(w)
+				writeError(w, ErrUnauthorized)
+			case errors.Is(err, auth.ErrTwoFactorPending):
+				writeError(w, ErrTwoFactorPending)
+			default:
+				writeError(w, err)
+			}
 			return
 		}
 
@@ -215,6 +253,241 @@ func (s Server) handleGetSession() http.HandlerFunc {
 	}
 }
 
+// Verifies the email of a member with the token of an emailed verification
+// link.
+func (s Server) handleVerifyEmail() http.HandlerFunc {
+	type verifyEmailLoad struct {
+		Token string `json:"token"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var load verifyEmailLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err := s.authService.VerifyEmail(r.Context(), auth.VerificationToken(load.Token))
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrInvalidVerificationToken):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			case errors.Is(err, service.ErrEmailVerificationUnavailable):
+				writeError(w, newAPIError(err.Error(), http.StatusServiceUnavailable))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Resends the verification email to the member of the given email. Responds
+// equally whether or not the member exists.
+func (s Server) handleResendVerification() http.HandlerFunc {
+	type resendVerificationLoad struct {
+		Email string `json:"email"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var load resendVerificationLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err := s.authService.ResendVerification(r.Context(), load.Email)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrEmailVerificationUnavailable):
+				writeError(w, newAPIError(err.Error(), http.StatusServiceUnavailable))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Emails a password reset link to the member of the given email. Responds
+// equally whether or not the member exists.
+func (s Server) handleForgotPassword() http.HandlerFunc {
+	type forgotPasswordLoad struct {
+		Email string `json:"email"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var load forgotPasswordLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err := s.authService.ForgotPassword(r.Context(), load.Email)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrPasswordResetUnavailable):
+				writeError(w, newAPIError(err.Error(), http.StatusServiceUnavailable))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Sets a new password with the token of a password reset link, logging the
+// member out everywhere.
+func (s Server) handleResetPassword() http.HandlerFunc {
+	type resetPasswordLoad struct {
+		Token           string `json:"token"`
+		Password        string `json:"password"`
+		PasswordConfirm string `json:"passwordConfirm"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var load resetPasswordLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err := s.authService.ResetPassword(
+			r.Context(),
+			auth.ResetToken(load.Token),
+			auth.Password(load.Password),
+			auth.Password(load.PasswordConfirm),
+		)
+
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrPasswordsNoMatch):
+				writeError(w, ErrPasswordsNoMatch)
+			case errors.Is(err, auth.ErrInvalidResetToken),
+				errors.Is(err, auth.ErrPasswordTooShort),
+				errors.Is(err, auth.ErrPasswordTooLong):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		clearSessionCookie(w)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Lists the sessions of the requesting member, marking the session of the
+// request as current.
+func (s Server) handleListSessions() http.HandlerFunc {
+	type sessionResponse struct {
+		auth.Session
+		Current bool `json:"current"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		current, err := s.memberSession(ctx, w, r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		sessions, err := s.authService.MemberSessions(ctx, current.MemberID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		res := make([]sessionResponse, 0)
+		for _, session := range sessions {
+			res = append(res, sessionResponse{
+				Session: session,
+				Current: session.ID == current.ID,
+			})
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// Revokes a session of the requesting member. Revoking the current session
+// logs the member out.
+func (s Server) handleRevokeSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		current, err := s.memberSession(ctx, w, r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		sessionID := auth.SessionID(chi.URLParam(r, "sessionID"))
+
+		err = s.authService.RevokeSession(ctx, current.MemberID, sessionID)
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrNoSession):
+				writeError(w, ErrSessionNoExist)
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		if sessionID == current.ID {
+			clearSessionCookie(w)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Logs the requesting member out of every session but the current.
+func (s Server) handleLogOutOthers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		current, err := s.memberSession(ctx, w, r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		err = s.authService.RevokeOtherSessions(ctx, current.MemberID, current.ID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Returns the IP address of the client of the request. Behind a proxy, the
+// RealIP middleware sets the remote address to the forwarded address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
 func writeSessionCookie(w http.ResponseWriter, token auth.SessionToken, expiresAt time.Time) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     SESSION_COOKIE_NAME,
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/concert"
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/ical"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+const (
+	CALENDAR_PROD_ID = "-//Konnekt//Events//DA"
+	CALENDAR_NAME    = "Konnekt"
+)
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+func (s Server) handleGetEventsCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.writeEventsCalendar(w, r, CALENDAR_NAME, make(query.FilterCollection), nil)
+	}
+}
+
+func (s Server) handleGetArtistEventsCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artistID, err := paramID("artistID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		a, err := s.artistService.ByID(r.Context(), artistID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		filters := query.FilterCollection{
+			"artist_id": []query.Filter{{Cmp: query.Equal, Value: strconv.FormatInt(artistID, 10)}},
+		}
+
+		// Only include the concerts of the artist, as the other artists of the
+		// event are of no interest to subscribers of the artist.
+		includeConcert := func(c concert.Concert) bool {
+			return c.Artist.ID == artistID
+		}
+
+		name := fmt.Sprintf("%s - %s", CALENDAR_NAME, a.Name)
+		s.writeEventsCalendar(w, r, name, filters, includeConcert)
+	}
+}
+
+func (s Server) handleGetVenueEventsCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		venueID, err := paramID("venueID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		v, err := s.venueService.ByID(r.Context(), venueID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		filters := query.FilterCollection{
+			"venue_id": []query.Filter{{Cmp: query.Equal, Value: strconv.FormatInt(venueID, 10)}},
+		}
+
+		name := fmt.Sprintf("%s - %s", CALENDAR_NAME, v.Name)
+		s.writeEventsCalendar(w, r, name, filters, nil)
+	}
+}
+
+// Writes an iCalendar feed of all public events matching the given filters.
+// If includeConcert is non-nil, only concerts for which it returns true are
+// part of the feed.
+func (s Server) writeEventsCalendar(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	filters query.FilterCollection,
+	includeConcert func(c concert.Concert) bool,
+) {
+	ctx := r.Context()
+
+	err := filters.Add("is_public", query.Filter{Cmp: query.Equal, Value: "true"})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	q, err := query.NewListQuery(query.WithFilters(filters))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, err := s.eventService.List(ctx, q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	cal := ical.Calendar{
+		ProdID: CALENDAR_PROD_ID,
+		Name:   name,
+		Stamp:  time.Now(),
+	}
+
+	for _, e := range result.Records {
+		cal.Events = append(cal.Events, calendarEvents(s.siteURL, e, includeConcert)...)
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="events.ics"`)
+
+	if err := cal.Encode(w); err != nil {
+		writeError(w, err)
+		return
+	}
+}
+
+// Converts an event into calendar events. The event itself spans all of its
+// concerts, and each concert is added as a separate calendar event.
+func calendarEvents(siteURL string, e event.Event, includeConcert func(c concert.Concert) bool) []ical.Event {
+	if len(e.Concerts) <= 0 {
+		return nil
+	}
+
+	eventURL := fmt.Sprintf("%s/events/%d", siteURL, e.ID)
+	location := fmt.Sprintf("%s, %s", e.Venue.Name, e.Venue.City)
+	status := calendarStatus(e.Status)
+
+	description := plainText(e.Description)
+	if e.StatusNotice != "" {
+		description = strings.TrimSpace(e.StatusNotice + "\n\n" + description)
+	}
+
+	start, end := e.Concerts[0].From, e.Concerts[0].To
+	for _, c := range e.Concerts {
+		if c.From.Before(start) {
+			start = c.From
+		}
+
+		if c.To.After(end) {
+			end = c.To
+		}
+	}
+
+	events := []ical.Event{{
+		UID:         fmt.Sprintf("event-%d@%s", e.ID, calendarUIDDomain(siteURL)),
+		Start:       start,
+		End:         end,
+		Summary:     e.Title,
+		Description: description,
+		Location:    location,
+		URL:         eventURL,
+		Status:      status,
+	}}
+
+	for _, c := range e.Concerts {
+		if includeConcert != nil && !includeConcert(c) {
+			continue
+		}
+
+		events = append(events, ical.Event{
+			// Concerts are recreated whenever their event is updated, so the
+			// concert ID is not stable. The event, artist and start time are,
+			// and tell apart concerts of an artist playing twice in the event.
+			UID: fmt.Sprintf(
+				"event-%d-artist-%d-%s@%s",
+				e.ID, c.Artist.ID, c.From.UTC().Format("20060102T150405Z"), calendarUIDDomain(siteURL),
+			),
+			Start:       c.From,
+			End:         c.To,
+			Summary:     c.Artist.Name,
+			Description: e.Title,
+			Location:    location,
+			URL:         eventURL,
+			Status:      status,
+		})
+	}
+
+	return events
+}
+
+// Maps an event status to its calendar equivalent. Postponed events are
+// tentative, as their concerts are not yet rescheduled.
+func calendarStatus(status event.Status) ical.EventStatus {
+	switch status {
+	case event.StatusCancelled:
+		return ical.StatusCancelled
+	case event.StatusPostponed:
+		return ical.StatusTentative
+	default:
+		return ical.StatusConfirmed
+	}
+}
+
+func calendarUIDDomain(siteURL string) string {
+	u, err := url.Parse(siteURL)
+	if err != nil || u.Host == "" {
+		return "konnekt"
+	}
+
+	return u.Host
+}
+
+// Strips HTML tags from rich text, as calendar descriptions are plain text.
+func plainText(s string) string {
+	s = strings.NewReplacer("</p>", "\n", "<br>", "\n", "<br/>", "\n").Replace(s)
+	s = htmlTagRegex.ReplaceAllString(s, "")
+
+	return strings.TrimSpace(html.UnescapeString(s))
+}
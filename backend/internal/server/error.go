@@ -14,6 +14,9 @@ const (
 type APIError struct {
 	Message string `json:"message"`
 	Status  int    `json:"-"`
+
+	// Optional structured details, allowing clients to act on the error.
+	Details any `json:"details,omitempty"`
 }
 
 func newAPIError(msg string, status int) APIError {
@@ -7,26 +7,52 @@ import (
 	"time"
 
 	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+	"github.com/mattismoel/konnekt/internal/query"
 	"github.com/mattismoel/konnekt/internal/service"
 )
 
 const COVER_IMAGE_WIDTH = 2048
 
+// The time range audited for conflicts, if no end time is given.
+const DEFAULT_CONFLICT_AUDIT_RANGE = 365 * 24 * time.Hour
+
+// The scopes of an update of a recurring event.
+const (
+	UPDATE_SCOPE_THIS      = "this"
+	UPDATE_SCOPE_FOLLOWING = "following"
+)
+
 var (
-	ErrEventNoExist = APIError{Message: "Event does not exist", Status: http.StatusNotFound}
+	ErrEventNoExist       = APIError{Message: "Event does not exist", Status: http.StatusNotFound}
+	ErrInvalidUpdateScope = APIError{Message: "Scope must be either this or following", Status: http.StatusBadRequest}
 )
 
+// Restricts a query to public events, unless the requesting member is allowed
+// to view all events.
+func (s Server) restrictToPublicEvents(r *http.Request, q query.ListQuery) query.ListQuery {
+	if s.hasPermissions(r, "view:event") {
+		return q
+	}
+
+	q.Filters["is_public"] = []query.Filter{{Cmp: query.Equal, Value: "true"}}
+	return q
+}
+
 func (s Server) handleListEvents() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		query, err := NewListQueryFromURL(r.URL.Query())
+		q, err := NewListQueryFromURL(r.URL.Query())
 		if err != nil {
 			writeError(w, err)
 			return
 		}
 
-		result, err := s.eventService.List(ctx, query)
+		q = s.restrictToPublicEvents(r, q)
+
+		result, err := s.eventService.List(ctx, q)
 		if err != nil {
 			writeError(w, err)
 			return
@@ -57,6 +83,12 @@ func (s Server) handleEventByID() http.HandlerFunc {
 			return
 		}
 
+		// Non-public events must not leak to members without access.
+		if !e.IsVisible(time.Now()) && !s.hasPermissions(r, "view:event") {
+			writeError(w, ErrEventNoExist)
+			return
+		}
+
 		writeJSON(w, http.StatusOK, e)
 	}
 }
@@ -71,11 +103,15 @@ func (s Server) handleCreateEvent() http.HandlerFunc {
 	type createEventLoad struct {
 		Title       string              `json:"title"`
 		Description string              `json:"description"`
-		ImageURL    string              `json:"imageUrl"`
+		Image       media.Image         `json:"image"`
 		TicketURL   string              `json:"ticketUrl"`
 		VenueID     int64               `json:"venueId"`
 		Concerts    []createConcertLoad `json:"concerts"`
-		IsPublic    bool                `json:"isPublic"`
+		Status      string              `json:"status"`
+		PublishAt   *time.Time          `json:"publishAt"`
+		UnpublishAt *time.Time          `json:"unpublishAt"`
+		SeriesID    int64               `json:"seriesId"`
+		Force       bool                `json:"force"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -86,6 +122,17 @@ func (s Server) handleCreateEvent() http.HandlerFunc {
 			return
 		}
 
+		var status event.Status
+		if load.Status != "" {
+			var err error
+
+			status, err = event.ParseStatus(load.Status)
+			if err != nil {
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+				return
+			}
+		}
+
 		concerts := make([]service.CreateConcert, 0)
 
 		for _, conc := range load.Concerts {
@@ -100,14 +147,31 @@ func (s Server) handleCreateEvent() http.HandlerFunc {
 			Title:       load.Title,
 			Description: load.Description,
 			TicketURL:   load.TicketURL,
-			ImageURL:    load.ImageURL,
+			Image:       load.Image,
 			VenueID:     load.VenueID,
 			Concerts:    concerts,
-			IsPublic:    load.IsPublic,
+			Status:      status,
+			PublishAt:   load.PublishAt,
+			UnpublishAt: load.UnpublishAt,
+			SeriesID:    load.SeriesID,
+			Force:       load.Force,
 		})
 
+		var conflictErr event.ConflictError
+
 		if err != nil {
-			writeError(w, err)
+			switch {
+			case errors.As(err, &conflictErr):
+				writeError(w, newConflictAPIError(conflictErr))
+			case errors.Is(err, series.ErrNoExist):
+				writeError(w, ErrSeriesNoExist)
+			case errors.Is(err, event.ErrInvalidInitialStatus),
+				errors.Is(err, event.ErrInvalidPublishSchedule),
+				isImageError(err):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
 			return
 		}
 
@@ -126,10 +190,13 @@ func (s Server) handleUpdateEvent() http.HandlerFunc {
 		Title       string              `json:"title"`
 		Description string              `json:"description"`
 		TicketURL   string              `json:"ticketURL"`
-		ImageURL    string              `json:"imageUrl"`
+		Image       media.Image         `json:"image"`
 		Concerts    []updateConcertLoad `json:"concerts"`
 		VenueID     int64               `json:"venueId"`
-		IsPublic    bool                `json:"isPublic"`
+		PublishAt   *time.Time          `json:"publishAt"`
+		UnpublishAt *time.Time          `json:"unpublishAt"`
+		SeriesID    int64               `json:"seriesId"`
+		Force       bool                `json:"force"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -149,6 +216,19 @@ func (s Server) handleUpdateEvent() http.HandlerFunc {
 
 		ctx := r.Context()
 
+		// Recurring events are updated either as the single occurrence, or as
+		// the occurrence and all following occurrences.
+		update := s.eventService.Update
+
+		switch r.URL.Query().Get("scope") {
+		case "", UPDATE_SCOPE_THIS:
+		case UPDATE_SCOPE_FOLLOWING:
+			update = s.eventService.UpdateFollowing
+		default:
+			writeError(w, ErrInvalidUpdateScope)
+			return
+		}
+
 		concerts := make([]service.UpdateConcert, 0)
 
 		for _, c := range load.Concerts {
@@ -159,28 +239,184 @@ func (s Server) handleUpdateEvent() http.HandlerFunc {
 			})
 		}
 
-		e, err := s.eventService.Update(ctx, eventID, service.UpdateEvent{
+		e, err := update(ctx, eventID, service.UpdateEvent{
 			Title:       load.Title,
 			Description: load.Description,
 			TicketURL:   load.TicketURL,
-			ImageURL:    load.ImageURL,
+			Image:       load.Image,
 			VenueID:     load.VenueID,
 			Concerts:    concerts,
-			IsPublic:    load.IsPublic,
+			PublishAt:   load.PublishAt,
+			UnpublishAt: load.UnpublishAt,
+			SeriesID:    load.SeriesID,
+			Force:       load.Force,
+		})
+
+		var conflictErr event.ConflictError
+
+		if err != nil {
+			switch {
+			case errors.As(err, &conflictErr):
+				writeError(w, newConflictAPIError(conflictErr))
+			case errors.Is(err, series.ErrNoExist):
+				writeError(w, ErrSeriesNoExist)
+			case errors.Is(err, event.ErrInvalidPublishSchedule),
+				errors.Is(err, event.ErrNotRecurring),
+				isImageError(err):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		writeJSON(w, http.StatusOK, e)
+	}
+}
+
+func (s Server) handleCloneEvent() http.HandlerFunc {
+	type cloneEventLoad struct {
+		OffsetDays int        `json:"offsetDays"`
+		BaseDate   *time.Time `json:"baseDate"`
+		Force      bool       `json:"force"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventID, err := paramID("eventID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var load cloneEventLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		e, err := s.eventService.Clone(r.Context(), eventID, service.CloneEvent{
+			OffsetDays: load.OffsetDays,
+			BaseDate:   load.BaseDate,
+			Force:      load.Force,
 		})
 
+		var conflictErr event.ConflictError
+
+		if err != nil {
+			switch {
+			case errors.As(err, &conflictErr):
+				writeError(w, newConflictAPIError(conflictErr))
+			case errors.Is(err, event.ErrNoExist):
+				writeError(w, ErrEventNoExist)
+			case errors.Is(err, event.ErrInvalidCloneShift):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		writeJSON(w, http.StatusOK, e)
+	}
+}
+
+func (s Server) handleListEventConflicts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from := time.Now()
+		if v := r.URL.Query().Get("from"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, newAPIError("From must be a valid RFC 3339 timestamp", http.StatusBadRequest))
+				return
+			}
+
+			from = t
+		}
+
+		to := from.Add(DEFAULT_CONFLICT_AUDIT_RANGE)
+		if v := r.URL.Query().Get("to"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, newAPIError("To must be a valid RFC 3339 timestamp", http.StatusBadRequest))
+				return
+			}
+
+			to = t
+		}
+
+		if !to.After(from) {
+			writeError(w, newAPIError("To must be after from", http.StatusBadRequest))
+			return
+		}
+
+		conflicts, err := s.eventService.Conflicts(r.Context(), from, to)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, conflicts)
+	}
+}
+
+func newConflictAPIError(err event.ConflictError) APIError {
+	apiErr := newAPIError(err.Error(), http.StatusConflict)
+	apiErr.Details = err.Conflicts
+
+	return apiErr
+}
+
+func (s Server) handleSetEventStatus() http.HandlerFunc {
+	type setEventStatusLoad struct {
+		Status string `json:"status"`
+		Notice string `json:"notice"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventID, err := paramID("eventID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var load setEventStatusLoad
+
+		err = json.NewDecoder(r.Body).Decode(&load)
 		if err != nil {
 			writeError(w, err)
 			return
 		}
 
+		status, err := event.ParseStatus(load.Status)
+		if err != nil {
+			writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			return
+		}
+
+		e, err := s.eventService.SetStatus(r.Context(), eventID, status, load.Notice)
+		if err != nil {
+			switch {
+			case errors.Is(err, event.ErrNoExist):
+				writeError(w, ErrEventNoExist)
+			case errors.Is(err, event.ErrStatusTransition):
+				writeError(w, newAPIError(err.Error(), http.StatusConflict))
+			case errors.Is(err, event.ErrStatusNoticeRequired),
+				errors.Is(err, media.ErrMissingAltText):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
 		writeJSON(w, http.StatusOK, e)
 	}
 }
 
 func (s Server) handleUploadEventImage() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		file, _, err := r.FormFile("image")
+		file, err := s.formImage(w, r, "image")
 		if err != nil {
 			writeError(w, err)
 			return
@@ -190,13 +426,25 @@ func (s Server) handleUploadEventImage() http.HandlerFunc {
 
 		ctx := r.Context()
 
-		url, err := s.eventService.UploadImage(ctx, file)
+		framing, err := framingFromForm(r)
+		if err != nil {
+			writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			return
+		}
+
+		attribution, err := attributionFromForm(r)
+		if err != nil {
+			writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			return
+		}
+
+		img, err := s.eventService.UploadImage(ctx, file, framing, attribution)
 		if err != nil {
 			writeError(w, err)
 			return
 		}
 
-		writeText(w, http.StatusOK, url)
+		writeJSON(w, http.StatusOK, img)
 	}
 }
 
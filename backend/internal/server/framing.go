@@ -0,0 +1,67 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+// Returns the framing of an uploaded image, given by the optional form values
+// focalX and focalY, and cropX, cropY, cropWidth and cropHeight, all relative
+// to the width and height of the image.
+func framingFromForm(r *http.Request) (media.Framing, error) {
+	var framing media.Framing
+
+	if r.FormValue("focalX") != "" || r.FormValue("focalY") != "" {
+		values, err := parseFormFloats(r, "focalX", "focalY")
+		if err != nil {
+			return media.Framing{}, media.ErrInvalidFocalPoint
+		}
+
+		framing.FocalPoint = &media.FocalPoint{X: values[0], Y: values[1]}
+	}
+
+	if r.FormValue("cropX") != "" || r.FormValue("cropY") != "" || r.FormValue("cropWidth") != "" || r.FormValue("cropHeight") != "" {
+		values, err := parseFormFloats(r, "cropX", "cropY", "cropWidth", "cropHeight")
+		if err != nil {
+			return media.Framing{}, media.ErrInvalidCrop
+		}
+
+		framing.Crop = &media.Crop{X: values[0], Y: values[1], Width: values[2], Height: values[3]}
+	}
+
+	if err := framing.Validate(); err != nil {
+		return media.Framing{}, err
+	}
+
+	return framing, nil
+}
+
+func parseFormFloats(r *http.Request, keys ...string) ([]float64, error) {
+	values := make([]float64, 0)
+
+	for _, key := range keys {
+		v, err := strconv.ParseFloat(r.FormValue(key), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// Returns whether or not the error is caused by an invalid image, such as an
+// invalid framing, a missing attribution, an unknown media asset or objects
+// uploaded for another purpose.
+func isImageError(err error) bool {
+	return errors.Is(err, media.ErrInvalidFocalPoint) ||
+		errors.Is(err, media.ErrInvalidCrop) ||
+		errors.Is(err, media.ErrMissingAltText) ||
+		errors.Is(err, media.ErrInvalidLicense) ||
+		errors.Is(err, media.ErrAssetNoExist) ||
+		errors.Is(err, media.ErrForeignImage)
+}
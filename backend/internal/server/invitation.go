@@ -0,0 +1,158 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+	"github.com/mattismoel/konnekt/internal/domain/invitation"
+	"github.com/mattismoel/konnekt/internal/domain/member"
+	"github.com/mattismoel/konnekt/internal/domain/team"
+	"github.com/mattismoel/konnekt/internal/service"
+)
+
+var (
+	ErrInvitationNoExist = APIError{Message: "Invitation does not exist", Status: http.StatusNotFound}
+)
+
+func (s Server) handleListInvitations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query, err := NewListQueryFromURL(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := s.invitationService.List(r.Context(), query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := writeJSON(w, http.StatusOK, result); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+// Invites an email to join the given teams on behalf of the requesting
+// member.
+func (s Server) handleCreateInvitation() http.HandlerFunc {
+	type createInvitationLoad struct {
+		Email   string  `json:"email"`
+		TeamIDs []int64 `json:"teamIds"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		session, err := s.memberSession(ctx, w, r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		var load createInvitationLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		inv, err := s.invitationService.Invite(ctx, session.MemberID, load.Email, load.TeamIDs...)
+		if err != nil {
+			switch {
+			case errors.Is(err, member.ErrAlreadyExists):
+				writeError(w, ErrMemberAlreadyExists)
+			case errors.Is(err, invitation.ErrEmailInvalid),
+				errors.Is(err, invitation.ErrNoTeams),
+				errors.Is(err, team.ErrTeamNotFound):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		if err := writeJSON(w, http.StatusCreated, inv); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+func (s Server) handleRevokeInvitation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invitationID, err := paramID("invitationID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err = s.invitationService.Revoke(r.Context(), invitationID)
+		if err != nil {
+			switch {
+			case errors.Is(err, invitation.ErrNotFound):
+				writeError(w, ErrInvitationNoExist)
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Accepts the invitation of the token, responding with the created member,
+// who may log in right away.
+func (s Server) handleAcceptInvitation() http.HandlerFunc {
+	type acceptInvitationLoad struct {
+		Token           string `json:"token"`
+		FirstName       string `json:"firstName"`
+		LastName        string `json:"lastName"`
+		Password        string `json:"password"`
+		PasswordConfirm string `json:"passwordConfirm"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var load acceptInvitationLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		m, err := s.invitationService.Accept(r.Context(), invitation.Token(load.Token), service.AcceptInvitationLoad{
+			FirstName:       load.FirstName,
+			LastName:        load.LastName,
+			Password:        auth.Password(load.Password),
+			PasswordConfirm: auth.Password(load.PasswordConfirm),
+		})
+
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrPasswordsNoMatch):
+				writeError(w, ErrPasswordsNoMatch)
+			case errors.Is(err, member.ErrAlreadyExists):
+				writeError(w, ErrMemberAlreadyExists)
+			case errors.Is(err, invitation.ErrInvalidToken),
+				errors.Is(err, auth.ErrPasswordTooShort),
+				errors.Is(err, auth.ErrPasswordTooLong),
+				errors.Is(err, member.ErrFirstNameInvalid),
+				errors.Is(err, member.ErrLastNameInvalid):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		if err := writeJSON(w, http.StatusCreated, m); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
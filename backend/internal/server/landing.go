@@ -1,10 +1,13 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/mattismoel/konnekt/internal/domain/content"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 )
 
 func (s Server) handleLandingImages() http.HandlerFunc {
@@ -24,20 +27,61 @@ func (s Server) handleLandingImages() http.HandlerFunc {
 	}
 }
 
+// Adds a landing image, either uploaded as the form file "file" along with
+// its attribution, or taken from the media library by the query parameter
+// "mediaId".
 func (s Server) handleUploadLandingImage() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		file, _, err := r.FormFile("file")
-		if err != nil {
-			writeError(w, err)
-			return
-		}
+		var id int64
 
-		id, err := s.contentService.UploadLandingImage(ctx, file)
-		if err != nil {
-			writeError(w, err)
-			return
+		if r.URL.Query().Has("mediaId") {
+			assetID, err := strconv.ParseInt(r.URL.Query().Get("mediaId"), 10, 64)
+			if err != nil {
+				writeError(w, newAPIError(media.ErrInvalidAssetID.Error(), http.StatusBadRequest))
+				return
+			}
+
+			id, err = s.contentService.AddLandingImageFromMedia(ctx, assetID)
+			if err != nil {
+				switch {
+				case errors.Is(err, media.ErrAssetNoExist),
+					errors.Is(err, media.ErrMissingAltText):
+					writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+				case errors.Is(err, content.ErrLandingImageExists):
+					writeError(w, newAPIError(err.Error(), http.StatusConflict))
+				default:
+					writeError(w, err)
+				}
+
+				return
+			}
+		} else {
+			file, err := s.formImage(w, r, "file")
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+
+			defer file.Close()
+
+			attribution, err := attributionFromForm(r)
+			if err != nil {
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+				return
+			}
+
+			id, err = s.contentService.UploadLandingImage(ctx, file, attribution)
+			if err != nil {
+				if errors.Is(err, media.ErrMissingAltText) {
+					writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+					return
+				}
+
+				writeError(w, err)
+				return
+			}
 		}
 
 		img, err := s.contentService.LandingImageByID(ctx, id)
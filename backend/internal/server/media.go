@@ -0,0 +1,186 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/service"
+)
+
+var (
+	ErrAssetNoExist = APIError{Message: "Media asset does not exist", Status: http.StatusNotFound}
+)
+
+// Lists the assets of the media library. Assets may be filtered by tag with
+// filter=tag=<tag>, and searched by title, alt text and credit with q.
+func (s Server) handleListMedia() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, err := NewListQueryFromURL(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := s.mediaService.List(r.Context(), q)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func (s Server) handleMediaByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assetID, err := paramID("mediaID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		a, err := s.mediaService.ByID(r.Context(), assetID)
+		if err != nil {
+			writeMediaError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, a)
+	}
+}
+
+// Uploads the form file "file" to the media library, described by the form
+// values title, altText, credit and the comma separated tags.
+func (s Server) handleUploadMedia() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, err := s.formImage(w, r, "file")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer file.Close()
+
+		a, err := s.mediaService.Upload(r.Context(), file, service.AssetLoad{
+			Title:   r.FormValue("title"),
+			AltText: r.FormValue("altText"),
+			Credit:  r.FormValue("credit"),
+			Tags:    strings.Split(r.FormValue("tags"), ","),
+		})
+
+		if err != nil {
+			writeMediaError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, a)
+	}
+}
+
+func (s Server) handleUpdateMedia() http.HandlerFunc {
+	type updateMediaLoad struct {
+		Title   string   `json:"title"`
+		AltText string   `json:"altText"`
+		Credit  string   `json:"credit"`
+		Tags    []string `json:"tags"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var load updateMediaLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		assetID, err := paramID("mediaID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		a, err := s.mediaService.Update(r.Context(), assetID, service.AssetLoad{
+			Title:   load.Title,
+			AltText: load.AltText,
+			Credit:  load.Credit,
+			Tags:    load.Tags,
+		})
+
+		if err != nil {
+			writeMediaError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, a)
+	}
+}
+
+// Lists the artists, events and landing images using the asset.
+func (s Server) handleListMediaReferences() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assetID, err := paramID("mediaID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		refs, err := s.mediaService.References(r.Context(), assetID)
+		if err != nil {
+			writeMediaError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, refs)
+	}
+}
+
+// Deletes the asset, unless it is in use, in which case the entities using
+// it are given as details of the error.
+func (s Server) handleDeleteMedia() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		assetID, err := paramID("mediaID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err = s.mediaService.Delete(ctx, assetID)
+		if errors.Is(err, media.ErrAssetInUse) {
+			refs, refErr := s.mediaService.References(ctx, assetID)
+			if refErr != nil {
+				writeError(w, refErr)
+				return
+			}
+
+			apiErr := newAPIError(err.Error(), http.StatusConflict)
+			apiErr.Details = refs
+
+			writeError(w, apiErr)
+			return
+		}
+
+		if err != nil {
+			writeMediaError(w, err)
+			return
+		}
+	}
+}
+
+func writeMediaError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, media.ErrAssetNoExist):
+		writeError(w, ErrAssetNoExist)
+	case errors.Is(err, media.ErrEmptyTitle),
+		errors.Is(err, media.ErrInvalidTag),
+		errors.Is(err, media.ErrTooManyTags),
+		errors.Is(err, media.ErrInvalidDimensions):
+		writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+	default:
+		writeError(w, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/domain/member"
 )
 
@@ -32,6 +33,8 @@ func (s Server) handleListMembers() http.HandlerFunc {
 	}
 }
 
+// Approves the member, responding with the approved member, whose
+// emailVerifiedAt shows whether or not the member has verified the email.
 func (s Server) handleApproveMember() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		memberID, err := paramID("memberID", r)
@@ -42,13 +45,13 @@ func (s Server) handleApproveMember() http.HandlerFunc {
 
 		ctx := r.Context()
 
-		err = s.memberService.Approve(ctx, memberID)
+		m, err := s.memberService.Approve(ctx, memberID)
 		if err != nil {
 			writeError(w, err)
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
+		writeJSON(w, http.StatusOK, m)
 	}
 }
 
@@ -97,11 +100,11 @@ func (s Server) handleMemberByID() http.HandlerFunc {
 
 func (s Server) handleUpdateMember() http.HandlerFunc {
 	type UpdateMemberLoad struct {
-		Email             string  `json:"email"`
-		FirstName         string  `json:"firstName"`
-		LastName          string  `json:"lastName"`
-		ProfilePictureURL string  `json:"profilePictureUrl"`
-		MemberTeamIDs     []int64 `json:"memberTeams"`
+		Email          string      `json:"email"`
+		FirstName      string      `json:"firstName"`
+		LastName       string      `json:"lastName"`
+		ProfilePicture media.Image `json:"profilePicture"`
+		MemberTeamIDs  []int64     `json:"memberTeams"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -156,8 +159,8 @@ func (s Server) handleUpdateMember() http.HandlerFunc {
 			return
 		}
 
-		if strings.TrimSpace(load.ProfilePictureURL) != "" {
-			err := m.WithCfgs(member.WithProfilePictureURL(load.ProfilePictureURL))
+		if strings.TrimSpace(load.ProfilePicture.URL) != "" {
+			err := m.WithCfgs(member.WithProfilePicture(load.ProfilePicture))
 			if err != nil {
 				writeError(w, err)
 				return
@@ -216,7 +219,7 @@ func (srv Server) handleSetMemberTeams() http.HandlerFunc {
 
 func (srv Server) handleUploadMemberProfilePicture() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		file, _, err := r.FormFile("file")
+		file, err := srv.formImage(w, r, "file")
 		if err != nil {
 			writeError(w, err)
 			return
@@ -226,14 +229,14 @@ func (srv Server) handleUploadMemberProfilePicture() http.HandlerFunc {
 
 		ctx := r.Context()
 
-		profilePictureUrl, err := srv.memberService.UploadProfilePicture(ctx, file)
+		profilePicture, err := srv.memberService.UploadProfilePicture(ctx, file)
 
 		if err != nil {
 			writeError(w, err)
 			return
 		}
 
-		if err := writeText(w, http.StatusOK, profilePictureUrl); err != nil {
+		if err := writeJSON(w, http.StatusOK, profilePicture); err != nil {
 			writeError(w, err)
 			return
 		}
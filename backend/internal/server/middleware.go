@@ -32,3 +32,20 @@ func (s Server) withPermissions(next http.HandlerFunc, perms ...string) http.Han
 		next(w, r)
 	})
 }
+
+// Returns whether or not the requesting member has all of the given
+// permissions. Requests without a valid session have no permissions.
+func (s Server) hasPermissions(r *http.Request, perms ...string) bool {
+	ctx := r.Context()
+
+	session, err := s.memberSession(ctx, nil, r)
+	if err != nil {
+		return false
+	}
+
+	if err := s.authService.HasPermission(ctx, session.MemberID, perms...); err != nil {
+		return false
+	}
+
+	return true
+}
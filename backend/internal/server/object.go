@@ -0,0 +1,62 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// The time objects may be cached by clients. Object keys are random and never
+// reused, so objects do not change during this time.
+const OBJECT_CACHE_MAX_AGE = 2 * time.Hour
+
+var (
+	ErrObjectNoExist = APIError{Message: "Object does not exist", Status: http.StatusNotFound}
+)
+
+// Serves the objects of the object file system. The content type is derived
+// from the file extension, or sniffed from the content if there is none.
+func (s Server) handleGetObject() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := chi.URLParam(r, "*")
+		if !fs.ValidPath(key) {
+			writeError(w, ErrObjectNoExist)
+			return
+		}
+
+		f, err := s.objectFS.Open(key)
+		if err != nil {
+			switch {
+			case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrInvalid):
+				writeError(w, ErrObjectNoExist)
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		content, ok := f.(io.ReadSeeker)
+		if info.IsDir() || !ok {
+			writeError(w, ErrObjectNoExist)
+			return
+		}
+
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(OBJECT_CACHE_MAX_AGE.Seconds())))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		http.ServeContent(w, r, info.Name(), info.ModTime(), content)
+	}
+}
@@ -33,6 +33,7 @@ func NewListQueryFromURL(vals url.Values) (query.ListQuery, error) {
 		query.WithLimit(limit),
 		query.WithOrders(orderMap),
 		query.WithFilters(filters),
+		query.WithSearch(vals.Get("q")),
 	)
 
 	if err != nil {
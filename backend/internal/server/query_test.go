@@ -60,6 +60,16 @@ func TestNewListQueryFromRequest(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		"Search text": {
+			params: map[string]string{
+				"q": "  jazz night ",
+			},
+			wantQueryMod: func(q query.ListQuery) query.ListQuery {
+				q.Search = "jazz night"
+				return q
+			},
+			wantErr: nil,
+		},
 	}
 
 	for name, tt := range tests {
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+	"github.com/mattismoel/konnekt/internal/service"
+)
+
+func (s Server) handleCreateRecurringEvent() http.HandlerFunc {
+	type createConcertLoad struct {
+		ArtistID int64     `json:"artistID"`
+		From     time.Time `json:"from"`
+		To       time.Time `json:"to"`
+	}
+
+	type recurrenceLoad struct {
+		Rule       string   `json:"rule"`
+		TimeZone   string   `json:"timeZone"`
+		Exceptions []string `json:"exceptions"`
+	}
+
+	type createRecurringEventLoad struct {
+		Title       string              `json:"title"`
+		Description string              `json:"description"`
+		Image       media.Image         `json:"image"`
+		TicketURL   string              `json:"ticketUrl"`
+		VenueID     int64               `json:"venueId"`
+		Concerts    []createConcertLoad `json:"concerts"`
+		Status      string              `json:"status"`
+		PublishAt   *time.Time          `json:"publishAt"`
+		UnpublishAt *time.Time          `json:"unpublishAt"`
+		SeriesID    int64               `json:"seriesId"`
+		Recurrence  recurrenceLoad      `json:"recurrence"`
+		Force       bool                `json:"force"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var load createRecurringEventLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var status event.Status
+		if load.Status != "" {
+			var err error
+
+			status, err = event.ParseStatus(load.Status)
+			if err != nil {
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+				return
+			}
+		}
+
+		concerts := make([]service.CreateConcert, 0)
+
+		for _, conc := range load.Concerts {
+			concerts = append(concerts, service.CreateConcert{
+				ArtistID: conc.ArtistID,
+				From:     conc.From,
+				To:       conc.To,
+			})
+		}
+
+		events, err := s.eventService.CreateRecurring(r.Context(), service.CreateRecurringEvent{
+			CreateEvent: service.CreateEvent{
+				Title:       load.Title,
+				Description: load.Description,
+				TicketURL:   load.TicketURL,
+				Image:       load.Image,
+				VenueID:     load.VenueID,
+				Concerts:    concerts,
+				Status:      status,
+				PublishAt:   load.PublishAt,
+				UnpublishAt: load.UnpublishAt,
+				SeriesID:    load.SeriesID,
+				Force:       load.Force,
+			},
+			Rule:       load.Recurrence.Rule,
+			TimeZone:   load.Recurrence.TimeZone,
+			Exceptions: load.Recurrence.Exceptions,
+		})
+
+		var conflictErr event.ConflictError
+
+		if err != nil {
+			switch {
+			case errors.As(err, &conflictErr):
+				writeError(w, newConflictAPIError(conflictErr))
+			case errors.Is(err, series.ErrNoExist):
+				writeError(w, ErrSeriesNoExist)
+			case errors.Is(err, event.ErrInvalidInitialStatus),
+				errors.Is(err, event.ErrInvalidPublishSchedule),
+				errors.Is(err, event.ErrInvalidRule),
+				errors.Is(err, event.ErrRuleUnbounded),
+				errors.Is(err, event.ErrRuleByDayUnsupported),
+				errors.Is(err, event.ErrInvalidTimeZone),
+				errors.Is(err, event.ErrInvalidException),
+				isImageError(err),
+				errors.Is(err, event.ErrTooManyOccurrences),
+				errors.Is(err, event.ErrNoOccurrences),
+				errors.Is(err, event.ErrRecurrenceNoConcerts):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		writeJSON(w, http.StatusOK, events)
+	}
+}
@@ -16,6 +16,13 @@ func (s *Server) setupRoutes() {
 	s.mux.Use(middleware.Timeout(60 * time.Second))
 
 	s.mux.Get("/sitemap", s.handleGetSitemap())
+	s.mux.Get("/sitemap/{page}", s.handleGetSitemapPage())
+	s.mux.Get("/events.ics", s.handleGetEventsCalendar())
+	s.mux.Get("/search", s.handleSearch())
+
+	if s.objectFS != nil {
+		s.mux.Get("/objects/*", s.handleGetObject())
+	}
 
 	s.mux.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -32,6 +39,13 @@ func (s *Server) setupRoutes() {
 	s.mux.Route("/members", func(r chi.Router) {
 		r.Get("/", s.handleListMembers())
 
+		r.Route("/invitations", func(r chi.Router) {
+			r.Get("/", s.withPermissions(s.handleListInvitations(), "view:member"))
+			r.Post("/", s.withPermissions(s.handleCreateInvitation(), "view:team", "edit:member"))
+			r.Delete("/{invitationID}", s.withPermissions(s.handleRevokeInvitation(), "edit:member"))
+			r.Post("/accept", s.handleAcceptInvitation())
+		})
+
 		r.Get("/{memberID}", s.withPermissions(s.handleMemberByID(), "view:member"))
 		r.Put("/{memberID}", s.handleUpdateMember())
 		r.Delete("/{memberID}", s.withPermissions(s.handleDeleteMember(), "delete:member"))
@@ -58,10 +72,36 @@ func (s *Server) setupRoutes() {
 
 	s.mux.Route("/auth", func(r chi.Router) {
 		r.Post("/login", s.handleLogin())
+		r.Get("/register", s.handleGetRegistration())
 		r.Post("/register", s.handleRegister())
 		r.Post("/log-out", s.handleLogOut())
+		r.Post("/log-out-others", s.handleLogOutOthers())
 		r.Get("/session", s.handleGetSession())
 
+		r.Route("/verify-email", func(r chi.Router) {
+			r.Post("/", s.handleVerifyEmail())
+			r.Post("/resend", s.handleResendVerification())
+		})
+
+		r.Route("/password", func(r chi.Router) {
+			r.Post("/forgot", s.handleForgotPassword())
+			r.Post("/reset", s.handleResetPassword())
+		})
+
+		r.Route("/two-factor", func(r chi.Router) {
+			r.Get("/", s.handleGetTwoFactor())
+			r.Delete("/", s.handleDisableTwoFactor())
+			r.Post("/enrol", s.handleEnrolTwoFactor())
+			r.Post("/confirm", s.handleConfirmTwoFactor())
+			r.Post("/verify", s.handleVerifyTwoFactor())
+			r.Post("/recovery-codes", s.handleRegenerateRecoveryCodes())
+		})
+
+		r.Route("/sessions", func(r chi.Router) {
+			r.Get("/", s.handleListSessions())
+			r.Delete("/{sessionID}", s.handleRevokeSession())
+		})
+
 		r.Route("/permissions", func(r chi.Router) {
 			r.Get("/{teamID}", s.withPermissions(s.handleListTeamPermissions(), "view:team", "view:permission"))
 			// r.Get("/", s.withPermissions(s.handleListPermissions(), "view:permission"))
@@ -71,16 +111,33 @@ func (s *Server) setupRoutes() {
 
 	s.mux.Route("/events", func(r chi.Router) {
 		r.Get("/", s.handleListEvents())
+		r.Get("/conflicts", s.withPermissions(s.handleListEventConflicts(), "view:event"))
 		r.Get("/{eventID}", s.handleEventByID())
 
 		r.Post("/", s.withPermissions(s.handleCreateEvent(), "edit:event"))
+		r.Post("/recurring", s.withPermissions(s.handleCreateRecurringEvent(), "edit:event"))
 		r.Put("/{eventID}", s.withPermissions(s.handleUpdateEvent(), "edit:event"))
 		r.Delete("/{eventID}", s.withPermissions(s.handleDeleteEvent(), "delete:event"))
+		r.Post("/{eventID}/status", s.withPermissions(s.handleSetEventStatus(), "edit:event"))
+		r.Post("/{eventID}/clone", s.withPermissions(s.handleCloneEvent(), "edit:event"))
 		r.Post("/image", s.withPermissions(s.handleUploadEventImage(), "edit:event"))
 	})
 
+	s.mux.Route("/series", func(r chi.Router) {
+		r.Get("/", s.handleListSeries())
+		r.Get("/{seriesID}", s.handleSeriesByID())
+		r.Get("/{seriesID}/events", s.handleListSeriesEvents())
+		r.Get("/{seriesID}/lineup", s.handleGetSeriesLineup())
+
+		r.Post("/", s.withPermissions(s.handleCreateSeries(), "edit:event"))
+		r.Put("/{seriesID}", s.withPermissions(s.handleUpdateSeries(), "edit:event"))
+		r.Delete("/{seriesID}", s.withPermissions(s.handleDeleteSeries(), "delete:event"))
+		r.Post("/image", s.withPermissions(s.handleUploadSeriesImage(), "edit:event"))
+	})
+
 	s.mux.Route("/artists", func(r chi.Router) {
 		r.Get("/{artistID}", s.handleGetArtistByID())
+		r.Get("/{artistID}/events.ics", s.handleGetArtistEventsCalendar())
 		r.Get("/", s.handleListArtists())
 		r.Post("/", s.withPermissions(s.handleCreateArtist(), "edit:artist"))
 		r.Put("/{artistID}", s.withPermissions(s.handleUpdateArtist(), "edit:artist"))
@@ -91,12 +148,28 @@ func (s *Server) setupRoutes() {
 	s.mux.Route("/venues", func(r chi.Router) {
 		r.Get("/", s.withPermissions(s.handleListVenues(), "view:venue"))
 		r.Get("/{venueID}", s.withPermissions(s.handleVenueByID(), "view:venue"))
+		r.Get("/{venueID}/events.ics", s.handleGetVenueEventsCalendar())
 
 		r.Post("/", s.withPermissions(s.handleCreateVenue(), "edit:venue"))
 		r.Put("/{venueID}", s.withPermissions(s.handleUpdateVenue(), "edit:venue"))
 		r.Delete("/{venueID}", s.withPermissions(s.handleDeleteVenue(), "delete:venue"))
 	})
 
+	s.mux.Route("/media", func(r chi.Router) {
+		r.Get("/", s.withPermissions(s.handleListMedia(), "view:media"))
+		r.Get("/{mediaID}", s.withPermissions(s.handleMediaByID(), "view:media"))
+		r.Get("/{mediaID}/references", s.withPermissions(s.handleListMediaReferences(), "view:media"))
+
+		r.Post("/", s.withPermissions(s.handleUploadMedia(), "edit:media"))
+		r.Put("/{mediaID}", s.withPermissions(s.handleUpdateMedia(), "edit:media"))
+		r.Delete("/{mediaID}", s.withPermissions(s.handleDeleteMedia(), "delete:media"))
+	})
+
+	s.mux.Route("/storage", func(r chi.Router) {
+		r.Get("/orphans", s.withPermissions(s.handleListOrphans(), "delete:storage"))
+		r.Delete("/orphans", s.withPermissions(s.handleDeleteOrphans(), "delete:storage"))
+	})
+
 	s.mux.Route("/genres", func(r chi.Router) {
 		r.Post("/", s.withPermissions(s.handleCreateGenre(), "edit:genre"))
 		r.Get("/", s.handleListGenres())
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/search"
+)
+
+// The permissions required to search entities of a kind, matching the
+// permissions guarding the routes of the kind.
+var searchKindPermissions = map[search.Kind]string{
+	search.KindVenue: "view:venue",
+}
+
+func (s Server) handleSearch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vals := r.URL.Query()
+
+		kinds := search.Kinds()
+		if v := vals.Get("kind"); v != "" {
+			kinds = make([]search.Kind, 0)
+
+			for _, part := range strings.Split(v, ",") {
+				kind, err := search.ParseKind(part)
+				if err != nil {
+					writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+					return
+				}
+
+				kinds = append(kinds, kind)
+			}
+		}
+
+		// Kinds, whose routes are guarded, are only searched by members with
+		// access to them.
+		kinds = slices.DeleteFunc(kinds, func(kind search.Kind) bool {
+			perm, ok := searchKindPermissions[kind]
+			return ok && !s.hasPermissions(r, perm)
+		})
+
+		limit, _ := strconv.Atoi(vals.Get("limit"))
+
+		// Non-public events must not leak to members without access.
+		publicOnly := !s.hasPermissions(r, "view:event")
+
+		q, err := search.NewQuery(
+			search.WithText(vals.Get("q")),
+			search.WithKinds(kinds...),
+			search.WithLimit(limit),
+			search.WithPublicOnly(publicOnly, time.Now()),
+		)
+
+		if err != nil {
+			switch {
+			case errors.Is(err, search.ErrEmptyQuery):
+				writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+			default:
+				writeError(w, err)
+			}
+			return
+		}
+
+		// A query without kinds would search all kinds.
+		if len(kinds) <= 0 {
+			writeJSON(w, http.StatusOK, []search.Result{})
+			return
+		}
+
+		results, err := s.searchService.Search(r.Context(), q)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, results)
+	}
+}
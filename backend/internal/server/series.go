@@ -0,0 +1,223 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+	"github.com/mattismoel/konnekt/internal/service"
+)
+
+var (
+	ErrSeriesNoExist = APIError{Message: "Series does not exist", Status: http.StatusNotFound}
+)
+
+func (s Server) handleListSeries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, err := NewListQueryFromURL(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := s.seriesService.List(r.Context(), q)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func (s Server) handleSeriesByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ser, err := s.seriesFromParam(r)
+		if err != nil {
+			writeSeriesError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, ser)
+	}
+}
+
+func (s Server) handleListSeriesEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ser, err := s.seriesFromParam(r)
+		if err != nil {
+			writeSeriesError(w, err)
+			return
+		}
+
+		result, err := s.seriesService.Events(r.Context(), ser.ID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func (s Server) handleGetSeriesLineup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ser, err := s.seriesFromParam(r)
+		if err != nil {
+			writeSeriesError(w, err)
+			return
+		}
+
+		artists, err := s.seriesService.Lineup(r.Context(), ser.ID)
+		if err != nil {
+			writeSeriesError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, artists)
+	}
+}
+
+func (s Server) handleCreateSeries() http.HandlerFunc {
+	type createSeriesLoad struct {
+		Name        string      `json:"name"`
+		Slug        string      `json:"slug"`
+		Description string      `json:"description"`
+		Image       media.Image `json:"image"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var load createSeriesLoad
+
+		err := json.NewDecoder(r.Body).Decode(&load)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		ser, err := s.seriesService.Create(r.Context(), service.CreateSeries{
+			Name:        load.Name,
+			Slug:        load.Slug,
+			Description: load.Description,
+			Image:       load.Image,
+		})
+
+		if err != nil {
+			writeSeriesError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, ser)
+	}
+}
+
+func (s Server) handleUpdateSeries() http.HandlerFunc {
+	type updateSeriesLoad struct {
+		Name        string      `json:"name"`
+		Slug        string      `json:"slug"`
+		Description string      `json:"description"`
+		Image       media.Image `json:"image"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		seriesID, err := paramID("seriesID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var load updateSeriesLoad
+
+		err = json.NewDecoder(r.Body).Decode(&load)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		ser, err := s.seriesService.Update(r.Context(), seriesID, service.UpdateSeries{
+			Name:        load.Name,
+			Slug:        load.Slug,
+			Description: load.Description,
+			Image:       load.Image,
+		})
+
+		if err != nil {
+			writeSeriesError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, ser)
+	}
+}
+
+func (s Server) handleDeleteSeries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		seriesID, err := paramID("seriesID", r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err = s.seriesService.Delete(r.Context(), seriesID)
+		if err != nil {
+			writeSeriesError(w, err)
+			return
+		}
+	}
+}
+
+func (s Server) handleUploadSeriesImage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, err := s.formImage(w, r, "image")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		defer file.Close()
+
+		img, err := s.seriesService.UploadImage(r.Context(), file)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, img)
+	}
+}
+
+// Returns the series referenced by the "seriesID" URL parameter, which is
+// either the ID or the slug of the series. Slugs allow for readable URLs on
+// public series pages.
+func (s Server) seriesFromParam(r *http.Request) (series.Series, error) {
+	param := chi.URLParam(r, "seriesID")
+
+	seriesID, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return s.seriesService.BySlug(r.Context(), param)
+	}
+
+	return s.seriesService.ByID(r.Context(), seriesID)
+}
+
+func writeSeriesError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, series.ErrNoExist):
+		writeError(w, ErrSeriesNoExist)
+	case errors.Is(err, series.ErrSlugTaken):
+		writeError(w, newAPIError(err.Error(), http.StatusConflict))
+	case errors.Is(err, series.ErrEmptyName),
+		errors.Is(err, series.ErrInvalidSlug),
+		errors.Is(err, series.ErrEmptyDescription),
+		errors.Is(err, series.ErrInvalidImageURL),
+		errors.Is(err, series.ErrMediaImage),
+		errors.Is(err, media.ErrForeignImage):
+		writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+	default:
+		writeError(w, err)
+	}
+}
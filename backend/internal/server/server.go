@@ -1,12 +1,18 @@
 package server
 
 import (
+	"fmt"
+	"io/fs"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 	"github.com/mattismoel/konnekt/internal/service"
+	"github.com/mattismoel/konnekt/internal/sitemap"
+	"github.com/mattismoel/konnekt/internal/upload"
 )
 
 type ListReponse struct {
@@ -28,13 +34,39 @@ type Server struct {
 	artistService  *service.ArtistService
 	memberService  *service.MemberService
 	venueService   *service.VenueService
+	seriesService  *service.SeriesService
+	searchService  *service.SearchService
+	storageService *service.StorageService
+	mediaService   *service.MediaService
+
+	invitationService *service.InvitationService
+
+	// Guards the image uploads of all upload endpoints.
+	uploads *upload.Guard
+
+	// The objects served beneath /objects, if objects are stored locally.
+	objectFS fs.FS
+
+	// The public URL of the site, to which the sitemap and calendars link.
+	siteURL string
+
+	// The public URL of the sitemap, to which the sitemap index links.
+	sitemapURL string
+
+	// The maximum amount of URLs of a single sitemap, before the sitemap is
+	// split into an index.
+	sitemapPageSize int
 }
 
 type CfgFunc func(s *Server) error
 
+const DEFAULT_SITE_URL = "https://knnkt.dk"
+
 func New(cfgs ...CfgFunc) (*Server, error) {
 	s := &Server{
-		mux: chi.NewMux(),
+		mux:             chi.NewMux(),
+		siteURL:         DEFAULT_SITE_URL,
+		sitemapPageSize: sitemap.MAX_URLS,
 	}
 
 	for _, cfg := range cfgs {
@@ -43,6 +75,19 @@ func New(cfgs ...CfgFunc) (*Server, error) {
 		}
 	}
 
+	if s.uploads == nil {
+		uploads, err := upload.NewGuard()
+		if err != nil {
+			return nil, err
+		}
+
+		s.uploads = uploads
+	}
+
+	if s.sitemapURL == "" {
+		s.sitemapURL = s.siteURL + "/api/sitemap"
+	}
+
 	s.setupRoutes()
 
 	return s, nil
@@ -105,6 +150,57 @@ func WithMemberService(memberService *service.MemberService) CfgFunc {
 	}
 }
 
+func WithSeriesService(seriesService *service.SeriesService) CfgFunc {
+	return func(s *Server) error {
+		s.seriesService = seriesService
+		return nil
+	}
+}
+
+func WithSearchService(searchService *service.SearchService) CfgFunc {
+	return func(s *Server) error {
+		s.searchService = searchService
+		return nil
+	}
+}
+
+func WithStorageService(storageService *service.StorageService) CfgFunc {
+	return func(s *Server) error {
+		s.storageService = storageService
+		return nil
+	}
+}
+
+func WithMediaService(mediaService *service.MediaService) CfgFunc {
+	return func(s *Server) error {
+		s.mediaService = mediaService
+		return nil
+	}
+}
+
+func WithInvitationService(invitationService *service.InvitationService) CfgFunc {
+	return func(s *Server) error {
+		s.invitationService = invitationService
+		return nil
+	}
+}
+
+func WithUploadGuard(uploads *upload.Guard) CfgFunc {
+	return func(s *Server) error {
+		s.uploads = uploads
+		return nil
+	}
+}
+
+// Serves the objects of the file system beneath /objects. Used when objects
+// are not stored with an external provider.
+func WithObjectFS(fsys fs.FS) CfgFunc {
+	return func(s *Server) error {
+		s.objectFS = fsys
+		return nil
+	}
+}
+
 func WithVenueService(venueService *service.VenueService) CfgFunc {
 	return func(s *Server) error {
 		s.venueService = venueService
@@ -112,6 +208,54 @@ func WithVenueService(venueService *service.VenueService) CfgFunc {
 	}
 }
 
+func WithSiteURL(siteURL string) CfgFunc {
+	return func(s *Server) error {
+		siteURL, err := parsePublicURL(siteURL)
+		if err != nil {
+			return err
+		}
+
+		s.siteURL = siteURL
+		return nil
+	}
+}
+
+// Sets the public URL of the sitemap. Defaults to /api/sitemap of the site
+// URL.
+func WithSitemapURL(sitemapURL string) CfgFunc {
+	return func(s *Server) error {
+		sitemapURL, err := parsePublicURL(sitemapURL)
+		if err != nil {
+			return err
+		}
+
+		s.sitemapURL = sitemapURL
+		return nil
+	}
+}
+
+func WithSitemapPageSize(size int) CfgFunc {
+	return func(s *Server) error {
+		if size <= 0 || size > sitemap.MAX_URLS {
+			return sitemap.ErrInvalidPageSize
+		}
+
+		s.sitemapPageSize = size
+		return nil
+	}
+}
+
+// Parses the absolute URL, trimming any trailing slash, so that paths may be
+// appended.
+func parsePublicURL(rawURL string) (string, error) {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("Invalid public URL %q", rawURL)
+	}
+
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
 func WithAddress(addr string) CfgFunc {
 	return func(s *Server) error {
 		s.addr = addr
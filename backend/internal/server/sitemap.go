@@ -1,96 +1,223 @@
 package server
 
 import (
-	"encoding/xml"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/mattismoel/konnekt/internal/domain/artist"
+	"github.com/go-chi/chi/v5"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/query"
+	"github.com/mattismoel/konnekt/internal/sitemap"
 )
 
-var baseURL = "https://knnkt.dk"
-
-var baseURLs = []string{
-	"/",
-	"/about",
-	"/events",
-	"/artists",
-}
-
-type sitemap struct {
-	XMLName xml.Name     `xml:"urlset"`
-	XMLNs   string       `xml:"xmlns,attr"`
-	URL     []SitemapURL `xml:"url"`
-}
-
-type SitemapURL struct {
-	XMLName xml.Name `xml:"url"`
-	Loc     string   `xml:"loc"`
-	LastMod string   `xml:"lastmod"`
-}
+var (
+	ErrSitemapNoExist = APIError{Message: "Sitemap does not exist", Status: http.StatusNotFound}
+)
 
+// Serves the sitemap of the site, or a sitemap index linking to each page of
+// the sitemap at /sitemap/{page}, if the site has more URLs than fit on a
+// single page.
 func (s Server) handleGetSitemap() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		var sitemap sitemap
+		pages, err := s.sitemapPages(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
 
-		sitemap.XMLNs = "http://www.sitemaps.org/schemas/sitemap/0.9"
+		if len(pages) == 1 {
+			serveSitemap(w, r, pages[0].LastMod(), pages[0].Encode)
+			return
+		}
 
-		eventFilters := query.FilterCollection{
-			"is_public": []query.Filter{{Cmp: query.Equal, Value: "true"}},
+		var index sitemap.Index
+		var lastMod time.Time
+
+		for i, page := range pages {
+			index.Sitemaps = append(index.Sitemaps, sitemap.Sitemap{
+				Loc:     fmt.Sprintf("%s/%d", s.sitemapURL, i+1),
+				LastMod: page.LastMod(),
+			})
+
+			if page.LastMod().After(lastMod) {
+				lastMod = page.LastMod()
+			}
 		}
 
-		eventQuery, err := query.NewListQuery(
-			query.WithFilters(eventFilters),
-		)
+		serveSitemap(w, r, lastMod, index.Encode)
+	}
+}
+
+// Serves a single page of the sitemap, starting from page 1.
+func (s Server) handleGetSitemapPage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(chi.URLParam(r, "page"))
 		if err != nil {
-			writeError(w, err)
+			writeError(w, ErrSitemapNoExist)
 			return
 		}
 
-		eventResult, err := s.eventService.List(ctx, eventQuery)
+		pages, err := s.sitemapPages(r.Context())
 		if err != nil {
 			writeError(w, err)
 			return
 		}
 
-		artists := make(map[int64]artist.Artist)
-		for _, e := range eventResult.Records {
-			for _, c := range e.Concerts {
-				artists[c.Artist.ID] = c.Artist
-			}
+		if page < 1 || page > len(pages) {
+			writeError(w, ErrSitemapNoExist)
+			return
 		}
 
-		urls := baseURLs
-		for _, event := range eventResult.Records {
-			urls = append(urls, fmt.Sprintf("/events/%d", event.ID))
+		serveSitemap(w, r, pages[page-1].LastMod(), pages[page-1].Encode)
+	}
+}
+
+// Serves the encoded sitemap with caching headers. Conditional requests are
+// answered with 304 Not Modified, if neither the content nor the last
+// modification time has changed.
+func serveSitemap(w http.ResponseWriter, r *http.Request, lastMod time.Time, encode func(w io.Writer) error) {
+	var buf bytes.Buffer
+	if err := encode(&buf); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+
+	http.ServeContent(w, r, "", lastMod, bytes.NewReader(buf.Bytes()))
+}
+
+// Returns the URLs of the public pages of the site, split into pages of the
+// configured size.
+func (s Server) sitemapPages(ctx context.Context) ([]sitemap.URLSet, error) {
+	eventQuery, err := query.NewListQuery(
+		query.WithFilters(query.FilterCollection{
+			"is_public": []query.Filter{{Cmp: query.Equal, Value: "true"}},
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	eventResult, err := s.eventService.List(ctx, eventQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	artistQuery, err := query.NewListQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	artistResult, err := s.artistService.List(ctx, artistQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	landingImages, err := s.contentService.LandingImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Artist pages show the public events of the artist, and so change with
+	// them.
+	artistLastMods := make(map[int64]time.Time)
+
+	eventURLs := make([]sitemap.URL, 0, len(eventResult.Records))
+	var eventsLastMod time.Time
+
+	for _, e := range eventResult.Records {
+		lastMod := latest(e.UpdatedAt, e.Venue.UpdatedAt)
+		for _, c := range e.Concerts {
+			lastMod = latest(lastMod, c.Artist.UpdatedAt)
 		}
 
-		for _, artist := range artists {
-			urls = append(urls, fmt.Sprintf("/artists/%d", artist.ID))
+		for _, c := range e.Concerts {
+			artistLastMods[c.Artist.ID] = latest(artistLastMods[c.Artist.ID], lastMod)
 		}
 
-		y, m, _ := time.Now().Date()
-		lastmod := time.Date(y, m, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		eventsLastMod = latest(eventsLastMod, lastMod)
 
-		for _, url := range urls {
-			u := SitemapURL{
-				Loc:     baseURL + url,
-				LastMod: lastmod,
-			}
+		eventURLs = append(eventURLs, sitemap.URL{
+			Loc:     fmt.Sprintf("%s/events/%d", s.siteURL, e.ID),
+			LastMod: lastMod,
+			Images:  sitemapImages(e.Image),
+		})
+	}
+
+	artistURLs := make([]sitemap.URL, 0, len(artistResult.Records))
+	var artistsLastMod time.Time
+
+	for _, a := range artistResult.Records {
+		lastMod := latest(a.UpdatedAt, artistLastMods[a.ID])
+		artistsLastMod = latest(artistsLastMod, lastMod)
+
+		artistURLs = append(artistURLs, sitemap.URL{
+			Loc:     fmt.Sprintf("%s/artists/%d", s.siteURL, a.ID),
+			LastMod: lastMod,
+			Images:  sitemapImages(a.Image),
+		})
+	}
 
-			sitemap.URL = append(sitemap.URL, u)
+	landing := make([]media.Image, 0, len(landingImages))
+	for _, l := range landingImages {
+		landing = append(landing, l.Image)
+	}
+
+	urls := []sitemap.URL{
+		{Loc: s.siteURL + "/", LastMod: eventsLastMod, Images: sitemapImages(landing...)},
+		{Loc: s.siteURL + "/about"},
+		{Loc: s.siteURL + "/events", LastMod: eventsLastMod},
+		{Loc: s.siteURL + "/artists", LastMod: artistsLastMod},
+	}
+
+	urls = append(urls, eventURLs...)
+	urls = append(urls, artistURLs...)
+
+	return sitemap.Paginate(urls, s.sitemapPageSize)
+}
+
+// Returns the sitemap entries of the given images, whose captions are their
+// alt text followed by their credit, if any. Empty images are skipped.
+func sitemapImages(imgs ...media.Image) []sitemap.Image {
+	images := make([]sitemap.Image, 0, len(imgs))
+
+	for _, img := range imgs {
+		if img.URL == "" {
+			continue
 		}
 
-		outXML, _ := xml.MarshalIndent(sitemap, "", "  ")
-		sitemapXML := append([]byte(xml.Header), outXML...)
-		w.Header().Set("Content-Type", "application/xml")
-		_, err = w.Write(sitemapXML)
-		if err != nil {
-			writeError(w, err)
-			return
+		caption := img.AltText
+		if img.Credit != "" {
+			caption = strings.TrimSpace(fmt.Sprintf("%s (Credit: %s)", img.AltText, img.Credit))
 		}
+
+		images = append(images, sitemap.Image{
+			Loc:     img.URL,
+			Caption: caption,
+			License: img.License,
+		})
+	}
+
+	return images
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
 	}
+
+	return a
 }
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/object"
+)
+
+type orphansResponse struct {
+	Count int `json:"count"`
+
+	// The total size of the objects in bytes.
+	Size    int64         `json:"size"`
+	Objects []object.Info `json:"objects"`
+}
+
+func newOrphansResponse(objects []object.Info) orphansResponse {
+	res := orphansResponse{Count: len(objects), Objects: objects}
+	for _, o := range objects {
+		res.Size += o.Size
+	}
+
+	return res
+}
+
+// Reports the orphaned objects, which would be deleted, without deleting
+// them.
+func (s Server) handleListOrphans() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orphans, err := s.storageService.Orphans(r.Context(), time.Now())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := writeJSON(w, http.StatusOK, newOrphansResponse(orphans)); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+func (s Server) handleDeleteOrphans() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deleted, err := s.storageService.DeleteOrphans(r.Context(), time.Now())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := writeJSON(w, http.StatusOK, newOrphansResponse(deleted)); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+)
+
+var (
+	ErrTwoFactorPending = APIError{Message: "Two-factor authentication must be completed", Status: http.StatusUnauthorized}
+)
+
+type twoFactorCodeLoad struct {
+	Code string `json:"code"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []auth.RecoveryCode `json:"recoveryCodes"`
+}
+
+func (s Server) handleGetTwoFactor() http.HandlerFunc {
+	type twoFactorResponse struct {
+		Enabled           bool `json:"enabled"`
+		Required          bool `json:"required"`
+		RecoveryCodesLeft int  `json:"recoveryCodesLeft"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		session, err := s.memberSession(ctx, w, r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		status, err := s.authService.TwoFactorStatus(ctx, session.MemberID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, twoFactorResponse{
+			Enabled:           status.Enabled,
+			Required:          status.Required,
+			RecoveryCodesLeft: status.RecoveryCodesLeft,
+		})
+	}
+}
+
+// Begins setting up two-factor authentication, responding with the secret
+// and the otpauth URI to be shown as a QR code. Allowed for valid sessions and
+// for sessions awaiting enrolment.
+func (s Server) handleEnrolTwoFactor() http.HandlerFunc {
+	type enrolmentResponse struct {
+		Secret auth.TOTPSecret `json:"secret"`
+		URI    string          `json:"uri"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := sessionToken(r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		enrolment, err := s.authService.EnrolTwoFactor(r.Context(), token)
+		if err != nil {
+			writeTwoFactorError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, enrolmentResponse{
+			Secret: enrolment.Secret,
+			URI:    enrolment.URI,
+		})
+	}
+}
+
+// Enables two-factor authentication with a code of the enrolled secret,
+// responding with the recovery codes of the member. A session awaiting
+// enrolment is replaced by a valid session.
+func (s Server) handleConfirmTwoFactor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := sessionToken(r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		var load twoFactorCodeLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		confirmation, err := s.authService.ConfirmTwoFactor(r.Context(), token, load.Code)
+		if err != nil {
+			writeTwoFactorError(w, err)
+			return
+		}
+
+		if confirmation.Token != "" {
+			writeSessionCookie(w, confirmation.Token, confirmation.ExpiresAt)
+		}
+
+		writeJSON(w, http.StatusOK, recoveryCodesResponse{
+			RecoveryCodes: confirmation.RecoveryCodes,
+		})
+	}
+}
+
+// Completes a login awaiting a two-factor code with a two-factor code or a
+// recovery code, responding with the logged in member.
+func (s Server) handleVerifyTwoFactor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := sessionToken(r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		var load twoFactorCodeLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		ctx := r.Context()
+
+		newToken, expiry, err := s.authService.VerifyTwoFactor(ctx, token, load.Code)
+		if err != nil {
+			writeTwoFactorError(w, err)
+			return
+		}
+
+		writeSessionCookie(w, newToken, expiry)
+
+		session, err := s.authService.Session(ctx, newToken.SessionID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		m, err := s.memberService.ByID(ctx, session.MemberID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, m)
+	}
+}
+
+// Replaces the recovery codes of the requesting member, given a two-factor
+// code.
+func (s Server) handleRegenerateRecoveryCodes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		session, err := s.memberSession(ctx, w, r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		var load twoFactorCodeLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		codes, err := s.authService.RegenerateRecoveryCodes(ctx, session.MemberID, load.Code)
+		if err != nil {
+			writeTwoFactorError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+	}
+}
+
+// Disables two-factor authentication of the requesting member, given a
+// two-factor code or a recovery code.
+func (s Server) handleDisableTwoFactor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		session, err := s.memberSession(ctx, w, r)
+		if err != nil {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		var load twoFactorCodeLoad
+
+		if err := json.NewDecoder(r.Body).Decode(&load); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err = s.authService.DisableTwoFactor(ctx, session.MemberID, load.Code)
+		if err != nil {
+			writeTwoFactorError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Returns the token of the session cookie of the request, whether or not the
+// session is valid.
+func sessionToken(r *http.Request) (auth.SessionToken, error) {
+	cookie, err := r.Cookie(SESSION_COOKIE_NAME)
+	if err != nil {
+		return "", err
+	}
+
+	return auth.SessionToken(cookie.Value), nil
+}
+
+func writeTwoFactorError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrNoSession),
+		errors.Is(err, auth.ErrInvalidSession):
+		writeError(w, ErrUnauthorized)
+	case errors.Is(err, auth.ErrTwoFactorPending):
+		writeError(w, ErrTwoFactorPending)
+	case errors.Is(err, auth.ErrTooManyTwoFactorAttempts):
+		clearSessionCookie(w)
+		writeError(w, newAPIError(err.Error(), http.StatusTooManyRequests))
+	case errors.Is(err, auth.ErrTwoFactorAlreadyEnabled):
+		writeError(w, newAPIError(err.Error(), http.StatusConflict))
+	case errors.Is(err, auth.ErrTwoFactorRequired):
+		writeError(w, newAPIError(err.Error(), http.StatusForbidden))
+	case errors.Is(err, auth.ErrInvalidTwoFactorCode),
+		errors.Is(err, auth.ErrTwoFactorNotEnrolled):
+		writeError(w, newAPIError(err.Error(), http.StatusBadRequest))
+	default:
+		writeError(w, err)
+	}
+}
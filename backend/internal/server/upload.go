@@ -0,0 +1,46 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/mattismoel/konnekt/internal/upload"
+)
+
+// Returns the image uploaded as the given form field, guarded by the upload
+// guard of the server. Rejected uploads are returned as API errors.
+func (s Server) formImage(w http.ResponseWriter, r *http.Request, field string) (multipart.File, error) {
+	file, err := s.uploads.FormImage(w, r, field)
+	if err == nil {
+		return file, nil
+	}
+
+	switch {
+	case errors.Is(err, upload.ErrTooLarge):
+		return nil, APIError{
+			Message: fmt.Sprintf("Upload must not exceed %d bytes", s.uploads.MaxBytes()),
+			Status:  http.StatusRequestEntityTooLarge,
+			Details: map[string]int64{"maxBytes": s.uploads.MaxBytes()},
+		}
+	case errors.Is(err, upload.ErrTooManyPixels):
+		return nil, APIError{
+			Message: fmt.Sprintf("Image must not exceed %d pixels", s.uploads.MaxPixels()),
+			Status:  http.StatusRequestEntityTooLarge,
+			Details: map[string]int{"maxPixels": s.uploads.MaxPixels()},
+		}
+	case errors.Is(err, upload.ErrUnsupportedType):
+		return nil, APIError{
+			Message: err.Error(),
+			Status:  http.StatusUnsupportedMediaType,
+			Details: map[string][]string{"allowedTypes": s.uploads.ImageTypes()},
+		}
+	case errors.Is(err, upload.ErrInvalidImage),
+		errors.Is(err, http.ErrMissingFile),
+		errors.Is(err, http.ErrNotMultipart):
+		return nil, newAPIError(err.Error(), http.StatusBadRequest)
+	default:
+		return nil, err
+	}
+}
@@ -3,53 +3,38 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
-	"image"
 	"io"
-	"net/url"
-	"path"
 	"strconv"
 	"strings"
 
 	"github.com/mattismoel/konnekt/internal/domain/artist"
 	"github.com/mattismoel/konnekt/internal/domain/event"
-	"github.com/mattismoel/konnekt/internal/object"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/query"
-	"github.com/nfnt/resize"
-
-	_ "image/jpeg"
-	_ "image/png"
-)
-
-const ARTIST_IMAGE_WIDTH_PX = 2048
-
-var (
-	AllowedImageFiletypes = []string{".png", ".jpeg", ".jpg"}
 )
 
 var (
-	ErrInvalidImageFiletype = errors.New(fmt.Sprintf("Image file must be of format %s", strings.Join(AllowedImageFiletypes, ", ")))
-	ErrArtistInEvent        = errors.New("Artist must not be part of an event to be deleted")
+	ErrArtistInEvent = errors.New("Artist must not be part of an event to be deleted")
 )
 
 type ArtistService struct {
-	artistRepo  artist.Repository
-	eventRepo   event.Repository
-	objectStore object.Store
+	artistRepo artist.Repository
+	eventRepo  event.Repository
+	images     *ImageService
 }
 
-func NewArtistService(artistRepo artist.Repository, eventRepo event.Repository, objectStore object.Store) (*ArtistService, error) {
+func NewArtistService(artistRepo artist.Repository, eventRepo event.Repository, images *ImageService) (*ArtistService, error) {
 	return &ArtistService{
-		artistRepo:  artistRepo,
-		eventRepo:   eventRepo,
-		objectStore: objectStore,
+		artistRepo: artistRepo,
+		eventRepo:  eventRepo,
+		images:     images,
 	}, nil
 }
 
 type CreateArtist struct {
 	Name        string
 	Description string
-	ImageURL    string
+	Image       media.Image
 	PreviewURL  string
 	GenreIDs    []int64
 	Socials     []string
@@ -58,7 +43,7 @@ type CreateArtist struct {
 type UpdateArtist struct {
 	Name        string
 	Description string
-	ImageURL    string
+	Image       media.Image
 	PreviewURL  string
 	GenreIDs    []int64
 	Socials     []string
@@ -83,6 +68,11 @@ func (s ArtistService) List(ctx context.Context, q query.ListQuery) (query.ListR
 }
 
 func (s ArtistService) Create(ctx context.Context, load CreateArtist) (int64, error) {
+	img, err := s.images.Frame(ctx, media.PurposeArtist, load.Image)
+	if err != nil {
+		return 0, err
+	}
+
 	socials := make([]artist.Social, 0)
 	for _, social := range load.Socials {
 		s, err := artist.NewSocial(social)
@@ -106,7 +96,7 @@ func (s ArtistService) Create(ctx context.Context, load CreateArtist) (int64, er
 	a, err := artist.NewArtist(
 		artist.WithName(load.Name),
 		artist.WithDescription(load.Description),
-		artist.WithImageURL(load.ImageURL),
+		artist.WithImage(img),
 		artist.WithGenres(genres...),
 		artist.WithSocials(socials...),
 	)
@@ -174,29 +164,37 @@ func (s ArtistService) Update(ctx context.Context, artistID int64, load UpdateAr
 		}
 	}
 
-	if strings.TrimSpace(load.ImageURL) != "" {
-		// Delete previous artist image from object store.
-		url, err := url.Parse(prevArtist.ImageURL)
+	if strings.TrimSpace(load.Image.URL) != "" || load.Image.MediaID != 0 {
+		img, err := s.images.Frame(ctx, media.PurposeArtist, load.Image)
 		if err != nil {
 			return artist.Artist{}, err
 		}
 
-		if err := s.objectStore.Delete(ctx, url.Path); err != nil {
+		// Set the new artist image.
+		if err := a.WithCfgs(artist.WithImage(img)); err != nil {
 			return artist.Artist{}, err
 		}
+	}
+
+	err = s.artistRepo.Update(ctx, artistID, *a)
+	if err != nil {
+		return artist.Artist{}, nil
+	}
 
-		// Set the new artist image url.
-		if err := a.WithCfgs(artist.WithImageURL(load.ImageURL)); err != nil {
+	// Delete the objects of the previous artist image, which are no longer
+	// used, once the new image is saved.
+	if a.Image.URL != "" {
+		if err := s.images.DeleteReplaced(ctx, prevArtist.Image, a.Image); err != nil {
 			return artist.Artist{}, err
 		}
 	}
 
-	err = s.artistRepo.Update(ctx, artistID, *a)
+	updatedArtist, err := s.artistRepo.ByID(ctx, artistID)
 	if err != nil {
-		return artist.Artist{}, nil
+		return artist.Artist{}, err
 	}
 
-	return *a, nil
+	return updatedArtist, nil
 }
 
 func (s ArtistService) Delete(ctx context.Context, artistID int64) error {
@@ -214,12 +212,7 @@ func (s ArtistService) Delete(ctx context.Context, artistID int64) error {
 		return err
 	}
 
-	url, err := url.Parse(a.ImageURL)
-	if err != nil {
-		return err
-	}
-
-	err = s.objectStore.Delete(ctx, url.Path)
+	err = s.images.Delete(ctx, a.Image)
 	if err != nil {
 		return err
 	}
@@ -250,30 +243,16 @@ func (s ArtistService) CreateGenre(ctx context.Context, name string) (int64, err
 	return genreID, nil
 }
 
-func (s ArtistService) UploadImage(ctx context.Context, r io.Reader) (string, error) {
-	img, _, err := image.Decode(r)
+// Uploads the given artist image, cropped by the given framing.
+func (s ArtistService) UploadImage(ctx context.Context, r io.Reader, framing media.Framing, attribution media.Attribution) (media.Image, error) {
+	img, err := s.images.Upload(ctx, media.PurposeArtist, r, framing)
 	if err != nil {
-		return "", err
+		return media.Image{}, err
 	}
 
-	fileName := createRandomImageFileName("jpeg")
-
-	// Resize if too high resolution.
-	if img.Bounds().Max.X > ARTIST_IMAGE_WIDTH_PX {
-		img = resize.Resize(ARTIST_IMAGE_WIDTH_PX, 0, img, resize.Lanczos2)
-	}
-
-	formattedImg, err := formatJPEG(img)
-	if err != nil {
-		return "", err
-	}
-
-	url, err := s.objectStore.Upload(ctx, path.Join("/artists", fileName), formattedImg)
-	if err != nil {
-		return "", err
-	}
+	img.Attribution = attribution
 
-	return url, nil
+	return img, nil
 }
 
 func (s ArtistService) ArtistEvents(ctx context.Context, artistID int64) (query.ListResult[event.Event], error) {
@@ -3,12 +3,17 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/mattismoel/konnekt/internal/domain/auth"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/domain/member"
 	"github.com/mattismoel/konnekt/internal/domain/team"
+	"github.com/mattismoel/konnekt/internal/mailer"
 	"github.com/mattismoel/konnekt/internal/query"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -17,36 +22,191 @@ const (
 	SESSION_LIFETIME       = 30 * 24 * time.Hour // 30 day expiry.
 	SESSION_REFRESH_BUFFER = 15 * 24 * time.Hour // 15 day refresh buffer.
 
+	// The interval at which the last seen time of a session is updated, so
+	// that not every request writes to the session.
+	SESSION_LAST_SEEN_INTERVAL = 5 * time.Minute
+
+	PASSWORD_RESET_LIFETIME = time.Hour
+
+	EMAIL_VERIFICATION_LIFETIME = 48 * time.Hour
+
+	// The minimum interval between verification emails sent to a member.
+	VERIFICATION_RESEND_INTERVAL = 5 * time.Minute
 )
 
 var (
-	ErrMemberInactive = errors.New("Member is not active or needs approval")
+	ErrMemberInactive           = errors.New("Member is not active or needs approval")
+	ErrPasswordResetUnavailable = errors.New("Password reset is unavailable, as no mailer is configured")
+	ErrInvalidResetURL          = errors.New("Password reset URL must be an absolute URL")
+
+	ErrEmailNotVerified             = errors.New("Member email must be verified before logging in")
+	ErrEmailVerificationUnavailable = errors.New("Email verification is unavailable, as no mailer or signing key is configured")
+	ErrInvalidVerificationURL       = errors.New("Email verification URL must be an absolute URL")
+
+	ErrRegistrationClosed = errors.New("Registration is closed. Members join by invitation")
 )
 
 type AuthService struct {
 	memberRepo member.Repository
 	teamRepo   team.Repository
 	authRepo   auth.Repository
+
+	// Delivers password reset links. Passwords cannot be reset without it.
+	mailer mailer.Mailer
+
+	// The URL of the page, on which members set a new password. The reset
+	// token is appended as the token query parameter.
+	resetURL string
+
+	// Signs the tokens of email verification links.
+	verifier *auth.Verifier
+
+	// The URL of the page, on which members verify their email. The
+	// verification token is appended as the token query parameter.
+	verificationURL string
+
+	// Whether or not members must verify their email before logging in.
+	emailVerification bool
+
+	// Whether or not anyone may register, rather than only join by
+	// invitation.
+	openRegistration bool
+
+	// The names of the teams, whose members must log in with two-factor
+	// authentication.
+	twoFactorTeams []string
+
+	// Verifies the profile pictures of registrants.
+	images *ImageService
 }
 
-func NewAuthService(memberRepo member.Repository, authRepo auth.Repository, teamRepo team.Repository) (*AuthService, error) {
-	return &AuthService{
+type AuthCfgFunc func(s *AuthService) error
+
+func NewAuthService(memberRepo member.Repository, authRepo auth.Repository, teamRepo team.Repository, cfgs ...AuthCfgFunc) (*AuthService, error) {
+	s := &AuthService{
 		memberRepo: memberRepo,
 		teamRepo:   teamRepo,
 		authRepo:   authRepo,
-	}, nil
+
+		emailVerification: true,
+		openRegistration:  true,
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Verifies the profile pictures of registrants with the given image service.
+// Without it, registrants cannot set a profile picture.
+func WithImageService(images *ImageService) AuthCfgFunc {
+	return func(s *AuthService) error {
+		s.images = images
+		return nil
+	}
+}
+
+func WithMailer(m mailer.Mailer) AuthCfgFunc {
+	return func(s *AuthService) error {
+		s.mailer = m
+		return nil
+	}
+}
+
+// Sets the URL of the page, on which members set a new password, such as
+// "https://knnkt.dk/auth/reset-password".
+func WithPasswordResetURL(resetURL string) AuthCfgFunc {
+	return func(s *AuthService) error {
+		u, err := url.Parse(resetURL)
+		if err != nil || !u.IsAbs() {
+			return ErrInvalidResetURL
+		}
+
+		s.resetURL = resetURL
+		return nil
+	}
 }
 
 type RegisterLoad struct {
-	Email             string
-	Password          auth.Password
-	PasswordConfirm   auth.Password
-	FirstName         string
-	LastName          string
-	ProfilePictureURL string
+	Email           string
+	Password        auth.Password
+	PasswordConfirm auth.Password
+	FirstName       string
+	LastName        string
+	ProfilePicture  media.Image
+}
+
+func WithVerifier(verifier *auth.Verifier) AuthCfgFunc {
+	return func(s *AuthService) error {
+		s.verifier = verifier
+		return nil
+	}
+}
+
+// Sets the URL of the page, on which members verify their email, such as
+// "https://knnkt.dk/auth/verify-email".
+func WithVerificationURL(verificationURL string) AuthCfgFunc {
+	return func(s *AuthService) error {
+		u, err := url.Parse(verificationURL)
+		if err != nil || !u.IsAbs() {
+			return ErrInvalidVerificationURL
+		}
+
+		s.verificationURL = verificationURL
+		return nil
+	}
+}
+
+// Sets whether or not members must verify their email before logging in.
+// Verification is required by default, and relies on a mailer and a verifier.
+func WithEmailVerification(required bool) AuthCfgFunc {
+	return func(s *AuthService) error {
+		s.emailVerification = required
+		return nil
+	}
 }
 
+// Sets whether or not anyone may register. If not, members may only join by
+// invitation. Registration is open by default.
+func WithOpenRegistration(open bool) AuthCfgFunc {
+	return func(s *AuthService) error {
+		s.openRegistration = open
+		return nil
+	}
+}
+
+// Requires members of the teams of the given names, such as "admin", to log in
+// with two-factor authentication. Members without it set up must set it up
+// on their next login.
+func WithTwoFactorTeams(teamNames ...string) AuthCfgFunc {
+	return func(s *AuthService) error {
+		for _, name := range teamNames {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				s.twoFactorTeams = append(s.twoFactorTeams, name)
+			}
+		}
+
+		return nil
+	}
+}
+
+// Returns whether or not anyone may register.
+func (srv AuthService) RegistrationOpen() bool {
+	return srv.openRegistration
+}
+
+// Registers a member, who must be approved before logging in, and verify the
+// email through the emailed link, if email verification is required.
 func (srv AuthService) Register(ctx context.Context, load RegisterLoad) (int64, error) {
+	if !srv.openRegistration {
+		return 0, ErrRegistrationClosed
+	}
+
 	// Return if member already exists.
 	_, err := srv.memberRepo.ByEmail(ctx, load.Email)
 	if err == nil {
@@ -77,8 +237,16 @@ func (srv AuthService) Register(ctx context.Context, load RegisterLoad) (int64,
 		return 0, err
 	}
 
-	if strings.TrimSpace(load.ProfilePictureURL) != "" {
-		err := m.WithCfgs(member.WithProfilePictureURL(load.ProfilePictureURL))
+	if strings.TrimSpace(load.ProfilePicture.URL) != "" {
+		if srv.images == nil {
+			return 0, media.ErrForeignImage
+		}
+
+		if err := srv.images.Verify(media.PurposeMember, load.ProfilePicture); err != nil {
+			return 0, err
+		}
+
+		err := m.WithCfgs(member.WithProfilePicture(load.ProfilePicture))
 		if err != nil {
 			return 0, err
 		}
@@ -99,21 +267,155 @@ func (srv AuthService) Register(ctx context.Context, load RegisterLoad) (int64,
 		return 0, err
 	}
 
+	if !srv.emailVerification {
+		return memberID, nil
+	}
+
+	// The member is registered regardless of whether the email is sent, as
+	// the verification email may be resent.
+	m.ID = memberID
+	if err := srv.sendVerification(ctx, m); err != nil {
+		slog.Error("Could not send verification email", "memberID", memberID, "error", err)
+	}
+
 	return memberID, nil
 }
 
-func (srv AuthService) Login(ctx context.Context, email string, password []byte) (auth.SessionToken, time.Time, error) {
+// Resends the verification email to the member of the given email. Succeeds
+// without sending anything if there is no such unverified member, or if an
+// email was sent recently, so that callers cannot tell whether an email is
+// registered.
+func (srv AuthService) ResendVerification(ctx context.Context, email string) error {
+	if srv.mailer == nil || srv.verifier == nil || srv.verificationURL == "" {
+		return ErrEmailVerificationUnavailable
+	}
+
+	m, err := srv.memberRepo.ByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, member.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if m.IsEmailVerified() {
+		return nil
+	}
+
+	if m.VerificationSentAt != nil && time.Since(*m.VerificationSentAt) < VERIFICATION_RESEND_INTERVAL {
+		return nil
+	}
+
+	return srv.sendVerification(ctx, m)
+}
+
+// Marks the email of the member of the verification token as verified. The
+// token is invalid, if the email of the member has changed since it was sent.
+func (srv AuthService) VerifyEmail(ctx context.Context, token auth.VerificationToken) error {
+	if srv.verifier == nil {
+		return ErrEmailVerificationUnavailable
+	}
+
+	claims, err := srv.verifier.Verify(token, time.Now())
+	if err != nil {
+		return err
+	}
+
+	m, err := srv.memberRepo.ByID(ctx, claims.MemberID)
+	if err != nil {
+		if errors.Is(err, member.ErrNotFound) {
+			return auth.ErrInvalidVerificationToken
+		}
+
+		return err
+	}
+
+	if !strings.EqualFold(m.Email, claims.Email) {
+		return auth.ErrInvalidVerificationToken
+	}
+
+	if m.IsEmailVerified() {
+		return nil
+	}
+
+	return srv.memberRepo.SetEmailVerified(ctx, m.ID, time.Now())
+}
+
+func (srv AuthService) sendVerification(ctx context.Context, m member.Member) error {
+	if srv.mailer == nil || srv.verifier == nil || srv.verificationURL == "" {
+		return ErrEmailVerificationUnavailable
+	}
+
+	now := time.Now()
+
+	token := srv.verifier.Sign(auth.Verification{
+		MemberID:  m.ID,
+		Email:     m.Email,
+		ExpiresAt: now.Add(EMAIL_VERIFICATION_LIFETIME),
+	})
+
+	link, err := tokenURL(srv.verificationURL, string(token))
+	if err != nil {
+		return err
+	}
+
+	err = srv.mailer.Send(ctx, mailer.Message{
+		To:      m.Email,
+		Subject: "Bekræft din email",
+		Body: fmt.Sprintf(
+			"Hej %s,\n\n"+
+				"Tak for din registrering hos Konnekt. "+
+				"Følg linket herunder for at bekræfte din email. Linket udløber om to dage.\n\n"+
+				"%s\n\n"+
+				"Har du ikke oprettet en konto, kan du se bort fra denne mail.\n",
+			m.FirstName, link,
+		),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return srv.memberRepo.SetVerificationSentAt(ctx, m.ID, now)
+}
+
+// Returns the URL with the token set as its token query parameter.
+func tokenURL(rawURL string, token string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Logs in the member from the given client. Existing sessions of the member
+// are kept, so that the member may be logged in on several devices at once.
+//
+// If the member must enter a two-factor code or set up two-factor
+// authentication, a short-lived pending session is created instead, which
+// grants no permissions until the step is completed.
+func (srv AuthService) Login(ctx context.Context, email string, password []byte, client auth.Client) (auth.SessionToken, time.Time, error) {
 	m, err := srv.validateMember(ctx, email, password)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
-	err = srv.clearMemberSession(ctx, m.ID)
+	step, err := srv.pendingStep(ctx, m.ID)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
-	token, expiry, err := srv.createSession(ctx, m.ID)
+	if step != "" {
+		return srv.createPendingSession(ctx, m.ID, client, step)
+	}
+
+	token, expiry, err := srv.createSession(ctx, m.ID, client)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -121,19 +423,196 @@ func (srv AuthService) Login(ctx context.Context, email string, password []byte)
 	return token, expiry, nil
 }
 
+// Logs out the session of the token. Other sessions of the member are kept.
 func (srv AuthService) LogOut(ctx context.Context, token auth.SessionToken) error {
 	sessionID := token.SessionID()
 
+	_, err := srv.authRepo.Session(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	err = srv.authRepo.DeleteSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns the unexpired sessions of the member, most recently seen first.
+func (srv AuthService) MemberSessions(ctx context.Context, memberID int64) ([]auth.Session, error) {
+	sessions, err := srv.authRepo.MemberSessions(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]auth.Session, 0)
+	for _, session := range sessions {
+		if !session.IsExpired() {
+			active = append(active, session)
+		}
+	}
+
+	return active, nil
+}
+
+// Revokes the session of the member. Returns auth.ErrNoSession if the session
+// belongs to another member.
+func (srv AuthService) RevokeSession(ctx context.Context, memberID int64, sessionID auth.SessionID) error {
 	session, err := srv.authRepo.Session(ctx, sessionID)
 	if err != nil {
 		return err
 	}
 
-	err = srv.authRepo.DeleteMemberSession(ctx, session.MemberID)
+	if session.MemberID != memberID {
+		return auth.ErrNoSession
+	}
+
+	err = srv.authRepo.DeleteSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Revokes every session of the member, except for the current session, logging
+// the member out everywhere else.
+func (srv AuthService) RevokeOtherSessions(ctx context.Context, memberID int64, current auth.SessionID) error {
+	err := srv.authRepo.DeleteMemberSessions(ctx, memberID, current)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Deletes every session expired at the given time, returning the amount of
+// deleted sessions.
+func (srv AuthService) PruneSessions(ctx context.Context, now time.Time) (int64, error) {
+	count, err := srv.authRepo.DeleteExpiredSessions(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Prunes expired sessions at the given interval, until the context is done.
+func (srv AuthService) RunSessionPruner(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		count, err := srv.PruneSessions(ctx, time.Now())
+		if err != nil {
+			slog.Error("Could not prune expired sessions", "error", err)
+		}
+
+		if count > 0 {
+			slog.Info("Pruned expired sessions", "count", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Emails the member of the given email a link, with which a new password may
+// be set. Succeeds without sending anything if there is no such member, so
+// that it cannot be used to find the emails of members.
+func (srv AuthService) ForgotPassword(ctx context.Context, email string) error {
+	if srv.mailer == nil || srv.resetURL == "" {
+		return ErrPasswordResetUnavailable
+	}
+
+	m, err := srv.memberRepo.ByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, member.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	token, err := auth.NewResetToken()
+	if err != nil {
+		return err
+	}
+
+	// Only the latest link may be used.
+	if err := srv.authRepo.DeleteMemberPasswordResets(ctx, m.ID); err != nil {
+		return err
+	}
+
+	reset := auth.NewPasswordReset(token, m.ID, PASSWORD_RESET_LIFETIME)
+	if err := srv.authRepo.InsertPasswordReset(ctx, reset); err != nil {
+		return err
+	}
+
+	link, err := tokenURL(srv.resetURL, string(token))
+	if err != nil {
+		return err
+	}
+
+	return srv.mailer.Send(ctx, mailer.Message{
+		To:      m.Email,
+		Subject: "Nulstil din adgangskode",
+		Body: fmt.Sprintf(
+			"Hej %s,\n\n"+
+				"Vi har modtaget en anmodning om at nulstille adgangskoden til din Konnekt-konto. "+
+				"Følg linket herunder for at vælge en ny adgangskode. Linket udløber om en time og kan kun bruges én gang.\n\n"+
+				"%s\n\n"+
+				"Har du ikke bedt om at nulstille din adgangskode, kan du se bort fra denne mail.\n",
+			m.FirstName, link,
+		),
+	})
+}
+
+// Sets a new password of the member of the reset token, which is used up.
+// Every session of the member is revoked, so that anyone logged in with the
+// previous password is logged out.
+func (srv AuthService) ResetPassword(ctx context.Context, token auth.ResetToken, password, passwordConfirm auth.Password) error {
+	// The password is validated before the token is used, so that the link
+	// may be retried with a valid password.
+	if err := password.Validate(); err != nil {
+		return err
+	}
+
+	if err := password.Matches(passwordConfirm); err != nil {
+		return err
+	}
+
+	reset, err := srv.authRepo.ConsumePasswordReset(ctx, token.ResetID())
+	if err != nil {
+		return err
+	}
+
+	if reset.IsExpired() {
+		return auth.ErrInvalidResetToken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
+	if err := srv.memberRepo.SetPasswordHash(ctx, reset.MemberID, hash); err != nil {
+		return err
+	}
+
+	if err := srv.authRepo.DeleteMemberPasswordResets(ctx, reset.MemberID); err != nil {
+		return err
+	}
+
+	if err := srv.authRepo.DeleteMemberSessions(ctx, reset.MemberID); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -149,6 +628,17 @@ func (srv AuthService) ValidateSession(ctx context.Context, token auth.SessionTo
 		return time.Time{}, auth.ErrInvalidSession
 	}
 
+	if session.IsPending() {
+		return time.Time{}, auth.ErrTwoFactorPending
+	}
+
+	if !session.SeenWithin(SESSION_LAST_SEEN_INTERVAL) {
+		err := srv.authRepo.SetSessionLastSeen(ctx, sessionID, time.Now())
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
 	if session.IsRefreshable(SESSION_REFRESH_BUFFER) {
 		newExpiry := time.Now().Add(SESSION_LIFETIME)
 		err := srv.authRepo.SetSessionExpiry(ctx, sessionID, newExpiry)
@@ -219,38 +709,54 @@ func (srv AuthService) validateMember(ctx context.Context, email string, passwor
 		return member.Member{}, err
 	}
 
-	if !m.Active {
-		return member.Member{}, ErrMemberInactive
-	}
-
 	hash, err := srv.memberRepo.PasswordHash(ctx, m.ID)
 	if err != nil {
 		return member.Member{}, err
 	}
 
 	if err := hash.Matches(password); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return member.Member{}, auth.ErrPasswordsNoMatch
+		}
+
 		return member.Member{}, err
 	}
 
+	// The state of the account is only revealed to the owner of it.
+	if srv.emailVerification && !m.IsEmailVerified() {
+		return member.Member{}, ErrEmailNotVerified
+	}
+
+	if !m.Active {
+		return member.Member{}, ErrMemberInactive
+	}
+
 	return m, err
 }
 
-func (srv AuthService) clearMemberSession(ctx context.Context, memberID int64) error {
-	err := srv.authRepo.DeleteMemberSession(ctx, memberID)
+func (srv AuthService) createSession(ctx context.Context, memberID int64, client auth.Client) (auth.SessionToken, time.Time, error) {
+	token, err := auth.NewSessionToken()
 	if err != nil {
-		return err
+		return "", time.Time{}, err
 	}
 
-	return nil
+	session := auth.NewSession(token, memberID, SESSION_LIFETIME, client)
+
+	err = srv.authRepo.InsertSession(ctx, session)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, session.ExpiresAt, nil
 }
 
-func (srv AuthService) createSession(ctx context.Context, memberID int64) (auth.SessionToken, time.Time, error) {
+func (srv AuthService) createPendingSession(ctx context.Context, memberID int64, client auth.Client, step auth.PendingStep) (auth.SessionToken, time.Time, error) {
 	token, err := auth.NewSessionToken()
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
-	session := auth.NewSession(token, memberID, SESSION_LIFETIME)
+	session := auth.NewPendingSession(token, memberID, PENDING_SESSION_LIFETIME, client, step)
 
 	err = srv.authRepo.InsertSession(ctx, session)
 	if err != nil {
@@ -2,25 +2,20 @@ package service
 
 import (
 	"context"
-	"image"
 	"io"
-	"path"
 
 	"github.com/mattismoel/konnekt/internal/domain/content"
-	"github.com/mattismoel/konnekt/internal/object"
-	"github.com/nfnt/resize"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 )
 
-const LANDING_IMAGE_WIDTH_PX = 2048
-
 type ContentService struct {
-	store       object.Store
+	images      *ImageService
 	contentRepo content.Repository
 }
 
-func NewContentService(store object.Store, contentRepo content.Repository) *ContentService {
+func NewContentService(images *ImageService, contentRepo content.Repository) *ContentService {
 	return &ContentService{
-		store:       store,
+		images:      images,
 		contentRepo: contentRepo,
 	}
 }
@@ -34,29 +29,52 @@ func (s ContentService) LandingImages(ctx context.Context) ([]content.LandingIma
 	return images, nil
 }
 
-func (s ContentService) UploadLandingImage(ctx context.Context, r io.Reader) (int64, error) {
-	img, _, err := image.Decode(r)
+// Uploads a landing image, which must be described by the given attribution,
+// as landing images are shown to the public.
+func (s ContentService) UploadLandingImage(ctx context.Context, r io.Reader, attribution media.Attribution) (int64, error) {
+	if err := attribution.ValidatePublic(); err != nil {
+		return 0, err
+	}
+
+	img, err := s.images.Upload(ctx, media.PurposeLanding, r, media.Framing{})
 	if err != nil {
 		return 0, err
 	}
 
-	if img.Bounds().Max.X > LANDING_IMAGE_WIDTH_PX {
-		img = resize.Resize(LANDING_IMAGE_WIDTH_PX, 0, img, resize.Lanczos2)
+	img.Attribution = attribution
+
+	id, err := s.contentRepo.InsertLandingImage(ctx, img)
+	if err != nil {
+		return 0, err
 	}
 
-	formatedImg, err := formatJPEG(img)
+	return id, nil
+}
+
+// Adds the image of the given media asset as a landing image, sharing the
+// variants of the asset.
+func (s ContentService) AddLandingImageFromMedia(ctx context.Context, assetID int64) (int64, error) {
+	img, err := s.images.Resolve(ctx, media.Image{MediaID: assetID})
 	if err != nil {
 		return 0, err
 	}
 
-	fileName := createRandomImageFileName("jpeg")
+	if err := img.ValidatePublic(); err != nil {
+		return 0, err
+	}
 
-	url, err := s.store.Upload(ctx, path.Join("/landing_images", fileName), formatedImg)
+	images, err := s.contentRepo.LandingImages(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	id, err := s.contentRepo.InsertLandingImage(ctx, url)
+	for _, existing := range images {
+		if existing.URL == img.URL {
+			return 0, content.ErrLandingImageExists
+		}
+	}
+
+	id, err := s.contentRepo.InsertLandingImage(ctx, img)
 	if err != nil {
 		return 0, err
 	}
@@ -79,7 +97,7 @@ func (s ContentService) DeleteLandingImage(ctx context.Context, id int64) error
 		return err
 	}
 
-	if err := s.store.Delete(ctx, img.URL); err != nil {
+	if err := s.images.Delete(ctx, img.Image); err != nil {
 		return err
 	}
 
@@ -2,42 +2,43 @@ package service
 
 import (
 	"context"
-	"image"
+	"errors"
+	"fmt"
 	"io"
-	"net/url"
-	"path"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/mattismoel/konnekt/internal/domain/artist"
 	"github.com/mattismoel/konnekt/internal/domain/concert"
 	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/series"
 	"github.com/mattismoel/konnekt/internal/domain/venue"
-	"github.com/mattismoel/konnekt/internal/object"
 	"github.com/mattismoel/konnekt/internal/query"
-	"github.com/nfnt/resize"
 )
 
-const EVENT_COVER_IMAGE_WIDTH_PX = 2048
-
 type EventService struct {
-	eventRepo   event.Repository
-	artistRepo  artist.Repository
-	venueRepo   venue.Repository
-	objectStore object.Store
+	eventRepo  event.Repository
+	artistRepo artist.Repository
+	venueRepo  venue.Repository
+	seriesRepo series.Repository
+	images     *ImageService
 }
 
 func NewEventService(
 	eventRepo event.Repository,
 	artistRepo artist.Repository,
 	venueRepo venue.Repository,
-	objectStore object.Store,
+	seriesRepo series.Repository,
+	images *ImageService,
 ) (*EventService, error) {
 	return &EventService{
-		eventRepo:   eventRepo,
-		artistRepo:  artistRepo,
-		venueRepo:   venueRepo,
-		objectStore: objectStore,
+		eventRepo:  eventRepo,
+		artistRepo: artistRepo,
+		venueRepo:  venueRepo,
+		seriesRepo: seriesRepo,
+		images:     images,
 	}, nil
 }
 
@@ -45,10 +46,17 @@ type CreateEvent struct {
 	Title       string
 	Description string
 	TicketURL   string
-	ImageURL    string
+	Image       media.Image
 	VenueID     int64
 	Concerts    []CreateConcert
-	IsPublic    bool
+	Status      event.Status
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+	SeriesID    int64
+
+	// Whether or not to create the event, even though it conflicts with the
+	// bookings of other events.
+	Force bool
 }
 
 type CreateConcert struct {
@@ -67,16 +75,55 @@ func (s EventService) ByID(ctx context.Context, eventID int64) (event.Event, err
 }
 
 func (s EventService) Create(ctx context.Context, load CreateEvent) (event.Event, error) {
-	venue, err := s.venueRepo.ByID(ctx, load.VenueID)
+	e, err := s.newEvent(ctx, load)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	if !load.Force {
+		if err := s.checkConflicts(ctx, *e); err != nil {
+			return event.Event{}, err
+		}
+	}
+
+	eventID, err := s.eventRepo.Insert(ctx, *e)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	createdEvent, err := s.eventRepo.ByID(ctx, eventID)
 	if err != nil {
 		return event.Event{}, err
 	}
 
+	return createdEvent, nil
+}
+
+// Returns a new, validated event from the given load.
+func (s EventService) newEvent(ctx context.Context, load CreateEvent) (*event.Event, error) {
+	if load.Status == "" {
+		load.Status = event.StatusDraft
+	}
+
+	if !load.Status.IsInitial() {
+		return nil, event.ErrInvalidInitialStatus
+	}
+
+	venue, err := s.venueRepo.ByID(ctx, load.VenueID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventSeries, err := s.seriesByID(ctx, load.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
 	concerts := make([]concert.Concert, 0)
 	for _, c := range load.Concerts {
 		artist, err := s.artistRepo.ByID(ctx, c.ArtistID)
 		if err != nil {
-			return event.Event{}, err
+			return nil, err
 		}
 
 		c, err := concert.NewConcert(
@@ -86,37 +133,38 @@ func (s EventService) Create(ctx context.Context, load CreateEvent) (event.Event
 		)
 
 		if err != nil {
-			return event.Event{}, err
+			return nil, err
 		}
 
 		concerts = append(concerts, c)
 	}
 
+	img, err := s.images.Frame(ctx, media.PurposeEvent, load.Image)
+	if err != nil {
+		return nil, err
+	}
+
 	e, err := event.NewEvent(
 		event.WithTitle(load.Title),
 		event.WithDescription(load.Description),
 		event.WithTicketURL(load.TicketURL),
 		event.WithVenue(venue),
-		event.WithImageURL(load.ImageURL),
+		event.WithImage(img),
 		event.WithConcerts(concerts...),
-		event.WithIsPublic(load.IsPublic),
+		event.WithStatus(load.Status),
+		event.WithPublishSchedule(load.PublishAt, load.UnpublishAt),
+		event.WithSeries(eventSeries),
 	)
 
 	if err != nil {
-		return event.Event{}, err
-	}
-
-	eventID, err := s.eventRepo.Insert(ctx, *e)
-	if err != nil {
-		return event.Event{}, err
+		return nil, err
 	}
 
-	createdEvent, err := s.eventRepo.ByID(ctx, eventID)
-	if err != nil {
-		return event.Event{}, err
+	if err := e.ValidatePublic(); err != nil {
+		return nil, err
 	}
 
-	return createdEvent, nil
+	return e, nil
 }
 
 type UpdateConcert struct {
@@ -129,10 +177,16 @@ type UpdateEvent struct {
 	Title       string
 	Description string
 	TicketURL   string
-	ImageURL    string
+	Image       media.Image
 	VenueID     int64
 	Concerts    []UpdateConcert
-	IsPublic    bool
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+	SeriesID    int64
+
+	// Whether or not to update the event, even though it conflicts with the
+	// bookings of other events.
+	Force bool
 }
 
 func (s EventService) Update(ctx context.Context, eventID int64, load UpdateEvent) (event.Event, error) {
@@ -142,16 +196,125 @@ func (s EventService) Update(ctx context.Context, eventID int64, load UpdateEven
 		return event.Event{}, err
 	}
 
-	venue, err := s.venueRepo.ByID(ctx, load.VenueID)
+	e, err := s.updatedEvent(ctx, eventID, load)
 	if err != nil {
 		return event.Event{}, err
 	}
 
+	if err := validatePublicUpdate(prevEvent, *e); err != nil {
+		return event.Event{}, err
+	}
+
+	// Cancelled events do not occupy their artists or venue.
+	if !load.Force && prevEvent.Status != event.StatusCancelled {
+		if err := s.checkConflicts(ctx, *e); err != nil {
+			return event.Event{}, err
+		}
+	}
+
+	err = s.eventRepo.Update(ctx, eventID, *e)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	// The previous cover image is shared by all occurrences of a recurring
+	// event, so its objects are only deleted for standalone events.
+	if e.Image.URL != "" && prevEvent.Recurrence == nil {
+		if err := s.images.DeleteReplaced(ctx, prevEvent.Image, e.Image); err != nil {
+			return event.Event{}, err
+		}
+	}
+
+	updatedEvent, err := s.eventRepo.ByID(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	return updatedEvent, nil
+}
+
+type CloneEvent struct {
+	// The amount of days to shift the concerts of the clone by.
+	OffsetDays int
+
+	// The new start of the earliest concert of the clone. Used instead of
+	// OffsetDays.
+	BaseDate *time.Time
+
+	// Whether or not to create the clone, even though it conflicts with the
+	// bookings of other events.
+	Force bool
+}
+
+// Creates a draft copy of the event with its concerts shifted as per the load.
+// The cover image is duplicated, so that deleting either event does not delete
+// the image of the other.
+func (s EventService) Clone(ctx context.Context, eventID int64, load CloneEvent) (event.Event, error) {
+	e, err := s.eventRepo.ByID(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	clone, err := e.Clone(load.OffsetDays, load.BaseDate)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	if !load.Force {
+		if err := s.checkConflicts(ctx, clone); err != nil {
+			return event.Event{}, err
+		}
+	}
+
+	if e.Image.URL != "" {
+		clone.Image, err = s.images.Copy(ctx, media.PurposeEvent, e.Image)
+		if err != nil {
+			return event.Event{}, err
+		}
+	}
+
+	cloneID, err := s.eventRepo.Insert(ctx, clone)
+	if err != nil {
+		if clone.Image.URL != "" {
+			s.deleteImage(ctx, clone.Image)
+		}
+
+		return event.Event{}, err
+	}
+
+	createdEvent, err := s.eventRepo.ByID(ctx, cloneID)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	return createdEvent, nil
+}
+
+// Deletes the objects of the given image, logging any failure, as the objects
+// are then merely orphaned.
+func (s EventService) deleteImage(ctx context.Context, img media.Image) {
+	if err := s.images.Delete(ctx, img); err != nil {
+		slog.Error("Could not delete image", "url", img.URL, "error", err)
+	}
+}
+
+// Returns the validated event with the given ID, as updated by the given load.
+func (s EventService) updatedEvent(ctx context.Context, eventID int64, load UpdateEvent) (*event.Event, error) {
+	venue, err := s.venueRepo.ByID(ctx, load.VenueID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventSeries, err := s.seriesByID(ctx, load.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
 	concerts := make([]concert.Concert, 0)
 	for _, c := range load.Concerts {
 		artist, err := s.artistRepo.ByID(ctx, c.ArtistID)
 		if err != nil {
-			return event.Event{}, err
+			return nil, err
 		}
 
 		concert, err := concert.NewConcert(
@@ -162,7 +325,7 @@ func (s EventService) Update(ctx context.Context, eventID int64, load UpdateEven
 		)
 
 		if err != nil {
-			return event.Event{}, err
+			return nil, err
 		}
 
 		concerts = append(concerts, concert)
@@ -175,30 +338,136 @@ func (s EventService) Update(ctx context.Context, eventID int64, load UpdateEven
 		event.WithTicketURL(load.TicketURL),
 		event.WithConcerts(concerts...),
 		event.WithVenue(venue),
-		event.WithIsPublic(load.IsPublic),
+		event.WithPublishSchedule(load.PublishAt, load.UnpublishAt),
+		event.WithSeries(eventSeries),
 	)
 
+	if err != nil {
+		return nil, err
+	}
+
 	// If there is a cover image URL update, set it.
-	if strings.TrimSpace(load.ImageURL) != "" {
-		url, err := url.Parse(prevEvent.ImageURL)
+	if strings.TrimSpace(load.Image.URL) != "" || load.Image.MediaID != 0 {
+		img, err := s.images.Frame(ctx, media.PurposeEvent, load.Image)
 		if err != nil {
-			return event.Event{}, err
+			return nil, err
 		}
 
-		if err := s.objectStore.Delete(ctx, url.Path); err != nil {
-			return event.Event{}, err
+		if err := e.WithCfgs(event.WithImage(img)); err != nil {
+			return nil, err
 		}
+	}
 
-		if err := e.WithCfgs(event.WithImageURL(load.ImageURL)); err != nil {
-			return event.Event{}, err
+	return e, nil
+}
+
+// Checks that the updated event may be shown to the public, if the previous
+// event is, or is to be. Updates leave the status as is, as well as the image,
+// unless it is replaced.
+func validatePublicUpdate(prev event.Event, updated event.Event) error {
+	updated.Status = prev.Status
+
+	if updated.Image.URL == "" {
+		updated.Image = prev.Image
+	}
+
+	return updated.ValidatePublic()
+}
+
+// Returns the series with the given ID, or nil if the ID is zero, as events
+// need not be part of a series.
+func (s EventService) seriesByID(ctx context.Context, seriesID int64) (*series.Series, error) {
+	if seriesID == 0 {
+		return nil, nil
+	}
+
+	eventSeries, err := s.seriesRepo.ByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventSeries, nil
+}
+
+// Returns all conflicts between the bookings of events overlapping the given
+// time range.
+func (s EventService) Conflicts(ctx context.Context, from, to time.Time) ([]event.Conflict, error) {
+	bookings, err := s.eventRepo.Bookings(ctx, event.BookingQuery{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	return event.AuditConflicts(bookings), nil
+}
+
+// Returns an event.ConflictError, if any concert of the event overlaps with a
+// concert of another event, either by artist or by venue.
+func (s EventService) checkConflicts(ctx context.Context, e event.Event) error {
+	if len(e.Concerts) <= 0 {
+		return nil
+	}
+
+	from, to := e.Concerts[0].From, e.Concerts[0].To
+	artistIDs := make([]int64, 0)
+	candidates := make([]event.Booking, 0)
+
+	for _, c := range e.Concerts {
+		if c.From.Before(from) {
+			from = c.From
+		}
+
+		if c.To.After(to) {
+			to = c.To
 		}
+
+		artistIDs = append(artistIDs, c.Artist.ID)
+		candidates = append(candidates, event.Booking{
+			EventID:    e.ID,
+			EventTitle: e.Title,
+			ArtistID:   c.Artist.ID,
+			VenueID:    e.Venue.ID,
+			From:       c.From,
+			To:         c.To,
+		})
+	}
+
+	existing, err := s.eventRepo.Bookings(ctx, event.BookingQuery{
+		From:           from,
+		To:             to,
+		ArtistIDs:      artistIDs,
+		VenueID:        e.Venue.ID,
+		ExcludeEventID: e.ID,
+	})
+
+	if err != nil {
+		return err
 	}
 
+	conflicts := event.FindConflicts(candidates, existing)
+	if len(conflicts) > 0 {
+		return event.ConflictError{Conflicts: conflicts}
+	}
+
+	return nil
+}
+
+// Transitions an event to the given status, enforcing the allowed status
+// transitions of the event domain.
+func (s EventService) SetStatus(ctx context.Context, eventID int64, status event.Status, notice string) (event.Event, error) {
+	e, err := s.eventRepo.ByID(ctx, eventID)
 	if err != nil {
 		return event.Event{}, err
 	}
 
-	err = s.eventRepo.Update(ctx, eventID, *e)
+	if err := e.TransitionTo(status, notice); err != nil {
+		return event.Event{}, err
+	}
+
+	if err := e.ValidatePublic(); err != nil {
+		return event.Event{}, err
+	}
+
+	err = s.eventRepo.SetStatus(ctx, eventID, e.Status, e.StatusNotice)
 	if err != nil {
 		return event.Event{}, err
 	}
@@ -211,27 +480,99 @@ func (s EventService) Update(ctx context.Context, eventID int64, load UpdateEven
 	return updatedEvent, nil
 }
 
-func (s EventService) UploadImage(ctx context.Context, r io.Reader) (string, error) {
-	img, _, err := image.Decode(r)
-
-	fileName := createRandomImageFileName("jpeg")
+// Applies the publish schedules, which are due at the given time. Draft events
+// due for publishing are scheduled, and public events due for unpublishing are
+// archived. Applied schedule times are cleared, so that each is only applied
+// once. Draft events, which are not ready to be shown publicly, stay drafts.
+// A failure to apply the schedule of one event does not prevent the schedules
+// of the other events from being applied.
+func (s EventService) ApplySchedules(ctx context.Context, now time.Time) error {
+	eventIDs, err := s.eventRepo.ScheduleDue(ctx, now)
+	if err != nil {
+		return err
+	}
 
-	if img.Bounds().Max.X > EVENT_COVER_IMAGE_WIDTH_PX {
-		img = resize.Resize(EVENT_COVER_IMAGE_WIDTH_PX, 0, img, resize.Lanczos2)
+	errs := make([]error, 0)
 
+	for _, eventID := range eventIDs {
+		if err := s.applySchedule(ctx, eventID, now); err != nil {
+			errs = append(errs, fmt.Errorf("event %d: %w", eventID, err))
+		}
 	}
 
-	formattedImg, err := formatJPEG(img)
+	return errors.Join(errs...)
+}
+
+func (s EventService) applySchedule(ctx context.Context, eventID int64, now time.Time) error {
+	e, err := s.eventRepo.ByID(ctx, eventID)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	url, err := s.objectStore.Upload(ctx, path.Join("/events", fileName), formattedImg)
+	publishAt, unpublishAt := e.PublishAt, e.UnpublishAt
+
+	if e.PublishDue(now) {
+		if e.Status == event.StatusDraft {
+			published := e
+			if err := published.TransitionTo(event.StatusScheduled, ""); err != nil {
+				return err
+			}
+
+			// The schedule is cleared regardless, so that the event is not
+			// attempted again, until it is fixed and rescheduled.
+			if err := published.ValidatePublic(); err != nil {
+				slog.Error("Could not publish scheduled event", "eventID", eventID, "error", err)
+			} else {
+				e = published
+			}
+		}
+
+		publishAt = nil
+	}
+
+	if e.UnpublishDue(now) {
+		if e.Status.IsPublic() {
+			if err := e.TransitionTo(event.StatusArchived, ""); err != nil {
+				return err
+			}
+		}
+
+		unpublishAt = nil
+	}
+
+	return s.eventRepo.ApplySchedule(ctx, eventID, e.Status, e.StatusNotice, publishAt, unpublishAt)
+}
+
+// Applies due publish schedules at the given interval, until the context is
+// cancelled. Schedules are stored with their events, so any schedules due
+// while the scheduler was not running are applied on the first run.
+func (s EventService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ApplySchedules(ctx, time.Now()); err != nil {
+			slog.Error("Could not apply event schedules", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Uploads the given cover image, cropped by the given framing.
+func (s EventService) UploadImage(ctx context.Context, r io.Reader, framing media.Framing, attribution media.Attribution) (media.Image, error) {
+	img, err := s.images.Upload(ctx, media.PurposeEvent, r, framing)
 	if err != nil {
-		return "", err
+		return media.Image{}, err
 	}
 
-	return url, nil
+	img.Attribution = attribution
+
+	return img, nil
 }
 
 func (s EventService) List(ctx context.Context, q query.ListQuery) (query.ListResult[event.Event], error) {
@@ -249,14 +590,12 @@ func (s EventService) Delete(ctx context.Context, eventID int64) error {
 		return err
 	}
 
-	url, err := url.Parse(e.ImageURL)
-	if err != nil {
-		return err
-	}
-
-	err = s.objectStore.Delete(ctx, url.Path)
-	if err != nil {
-		return err
+	// The cover image is shared by all occurrences of a recurring event.
+	if e.Recurrence == nil {
+		err = s.images.Delete(ctx, e.Image)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = s.eventRepo.Delete(ctx, eventID)
@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/service"
+)
+
+// Holds events by their ID, and applies schedules to them. Any other method
+// panics.
+type scheduleRepo struct {
+	event.Repository
+	events map[int64]event.Event
+	due    []int64
+}
+
+func (r scheduleRepo) ScheduleDue(context.Context, time.Time) ([]int64, error) {
+	return r.due, nil
+}
+
+func (r scheduleRepo) ByID(_ context.Context, eventID int64) (event.Event, error) {
+	e, ok := r.events[eventID]
+	if !ok {
+		return event.Event{}, event.ErrNoExist
+	}
+
+	return e, nil
+}
+
+func (r scheduleRepo) ApplySchedule(_ context.Context, eventID int64, status event.Status, notice string, publishAt, unpublishAt *time.Time) error {
+	e := r.events[eventID]
+	e.Status, e.StatusNotice = status, notice
+	e.PublishAt, e.UnpublishAt = publishAt, unpublishAt
+
+	r.events[eventID] = e
+	return nil
+}
+
+func TestApplySchedules(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	due := now.Add(-time.Minute)
+
+	img := media.Image{URL: "http://localhost:8080/objects/events/a-1080w.jpeg"}
+
+	describedImg := img
+	describedImg.AltText = "Band on stage"
+
+	repo := scheduleRepo{
+		events: map[int64]event.Event{
+			2: {ID: 2, Status: event.StatusDraft, PublishAt: &due, Image: img},
+			3: {ID: 3, Status: event.StatusDraft, PublishAt: &due, Image: describedImg},
+		},
+		// Event 1 does not exist, and must not prevent the others from
+		// being applied.
+		due: []int64{1, 2, 3},
+	}
+
+	eventService, err := service.NewEventService(repo, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = eventService.ApplySchedules(ctx, now)
+	if !errors.Is(err, event.ErrNoExist) {
+		t.Fatalf("got %v, want %v", err, event.ErrNoExist)
+	}
+
+	wants := map[int64]event.Status{
+		// Events, which are not ready to be shown publicly, stay drafts, and
+		// are not attempted again.
+		2: event.StatusDraft,
+		3: event.StatusScheduled,
+	}
+
+	for eventID, want := range wants {
+		got := repo.events[eventID]
+
+		if got.Status != want {
+			t.Errorf("event %d: got status %q, want %q", eventID, got.Status, want)
+		}
+
+		if got.PublishAt != nil {
+			t.Errorf("event %d: got publish time %v, want it cleared", eventID, *got.PublishAt)
+		}
+	}
+}
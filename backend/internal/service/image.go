@@ -1,31 +1,592 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"image"
+	"image/draw"
 	"image/jpeg"
 	_ "image/png"
 	"io"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/exif"
+	"github.com/mattismoel/konnekt/internal/object"
+	"github.com/mattismoel/konnekt/internal/placeholder"
+	"github.com/nfnt/resize"
 )
 
-func formatJPEG(img image.Image) (io.Reader, error) {
-	pr, pw := io.Pipe()
-	go func() {
-		defer pw.Close()
-		err := jpeg.Encode(pw, img, &jpeg.Options{Quality: 35})
+// The width of the downscaled image, from which placeholders are computed.
+// Placeholders are blurred regardless, so larger images only add work.
+const PLACEHOLDER_WIDTH_PX = 32
+
+// The directories of the image objects of each purpose.
+var imageDirs = map[media.Purpose]string{
+	media.PurposeArtist:  "/artists",
+	media.PurposeEvent:   "/events",
+	media.PurposeSeries:  "/series",
+	media.PurposeMember:  "/members",
+	media.PurposeLanding: "/landing_images",
+	media.PurposeMedia:   "/media",
+}
+
+// Processes uploaded images into responsive variants, as described by the
+// profile of their purpose.
+type ImageService struct {
+	store     object.Store
+	profiles  map[media.Purpose]media.Profile
+	mediaRepo media.Repository
+}
+
+type ImageCfgFunc func(s *ImageService) error
+
+func NewImageService(store object.Store, cfgs ...ImageCfgFunc) (*ImageService, error) {
+	s := &ImageService{
+		store:    store,
+		profiles: maps.Clone(media.DefaultProfiles),
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Processes images of the given purpose as described by the given profile,
+// instead of the default profile.
+func WithImageProfile(purpose media.Purpose, profile media.Profile) ImageCfgFunc {
+	return func(s *ImageService) error {
+		if _, ok := imageDirs[purpose]; !ok {
+			return media.ErrInvalidPurpose
+		}
+
+		p, err := media.NewProfile(profile.Quality, profile.Widths...)
+		if err != nil {
+			return err
+		}
+
+		for _, aspect := range profile.Aspects {
+			if _, _, err := aspect.Ratio(); err != nil {
+				return err
+			}
+		}
+
+		p.Aspects = slices.Clone(profile.Aspects)
+
+		s.profiles[purpose] = p
+		return nil
+	}
+}
+
+// Resolves images referring to media assets through the given repository.
+// Without it, such images are rejected.
+func WithMediaRepository(mediaRepo media.Repository) ImageCfgFunc {
+	return func(s *ImageService) error {
+		s.mediaRepo = mediaRepo
+		return nil
+	}
+}
+
+// Stores the variants of the given image under the keys
+// /<directory>/<uuid>-<width>w.jpeg, returning the stored image. Images are
+// turned upright as described by their EXIF orientation. Images of purposes,
+// whose profile has aspect ratios, are also cropped by the given framing.
+func (s ImageService) Upload(ctx context.Context, purpose media.Purpose, r io.Reader, framing media.Framing) (media.Image, error) {
+	profile, ok := s.profiles[purpose]
+	if !ok {
+		return media.Image{}, media.ErrInvalidPurpose
+	}
+
+	if err := framing.Validate(); err != nil {
+		return media.Image{}, err
+	}
+
+	// Stored variants are encoded from the decoded pixels alone, and so hold
+	// none of the metadata of the uploaded image, such as its GPS location.
+	// The orientation is therefore applied beforehand.
+	img, _, err := exif.Decode(r)
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	id := uuid.NewString()
+
+	variants, err := s.storeVariants(ctx, img, profile, func(width int) string {
+		return variantKey(purpose, id, width)
+	})
+
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	stored, err := media.NewImage(variants[len(variants)-1].URL, variants...)
+	if err != nil {
+		s.deleteVariants(ctx, variants)
+		return media.Image{}, err
+	}
+
+	if err := withPlaceholder(&stored, img); err != nil {
+		s.deleteVariants(ctx, variants)
+		return media.Image{}, err
+	}
+
+	stored.Framing = framing
+
+	stored.Crops, err = s.storeCrops(ctx, purpose, id, img, framing, profile)
+	if err != nil {
+		s.deleteVariants(ctx, variants)
+		return media.Image{}, err
+	}
+
+	return stored, nil
+}
+
+// Crops the given image by its framing, unless it is already cropped so,
+// returning the image with its new crops. The previous crops are left in
+// place, as they may still be in use by other entities.
+func (s ImageService) Frame(ctx context.Context, purpose media.Purpose, img media.Image) (media.Image, error) {
+	profile, ok := s.profiles[purpose]
+	if !ok {
+		return media.Image{}, media.ErrInvalidPurpose
+	}
+
+	img, err := s.Resolve(ctx, img)
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	if err := s.Verify(purpose, img); err != nil {
+		return media.Image{}, err
+	}
+
+	if img.URL == "" || img.Cropped(profile.Aspects) {
+		return img, nil
+	}
+
+	if err := img.Framing.Validate(); err != nil {
+		return media.Image{}, err
+	}
+
+	// The URL refers to the widest variant, which the crops are taken from.
+	decoded, err := s.decodeObject(ctx, img.URL)
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	img.Crops, err = s.storeCrops(ctx, purpose, uuid.NewString(), decoded, img.Framing, profile)
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	return img, nil
+}
+
+// Verifies that every object owned by the given image is stored under the
+// directory of the purpose. Images are sent by clients, and their owned
+// objects are deleted along with them, so they must not refer to the objects
+// of other purposes, such as those of media assets.
+func (s ImageService) Verify(purpose media.Purpose, img media.Image) error {
+	dir, ok := imageDirs[purpose]
+	if !ok {
+		return media.ErrInvalidPurpose
+	}
+
+	for _, u := range img.OwnedURLs() {
+		key, err := s.store.Key(u)
+		if err != nil || !strings.HasPrefix(key, dir+"/") {
+			return media.ErrForeignImage
+		}
+	}
+
+	return nil
+}
+
+// Returns the given image with the variants and placeholder of its media
+// asset, if it refers to one, as the asset is the authority on them. The
+// framing is kept, as is the crops, unless the image was of another source.
+// The attribution of the asset is used, unless the image has its own.
+func (s ImageService) Resolve(ctx context.Context, img media.Image) (media.Image, error) {
+	if img.MediaID == 0 {
+		return img, nil
+	}
+
+	if img.MediaID < 0 || s.mediaRepo == nil {
+		return media.Image{}, media.ErrAssetNoExist
+	}
+
+	a, err := s.mediaRepo.ByID(ctx, img.MediaID)
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	resolved := a.SharedImage()
+	resolved.Framing = img.Framing
+
+	if img.AltText != "" || img.Credit != "" || img.License != "" {
+		resolved.Attribution = img.Attribution
+	}
+
+	if img.URL == resolved.URL {
+		resolved.Crops = img.Crops
+	}
+
+	return resolved, nil
+}
+
+// Computes the placeholder of an already stored image from its smallest
+// variant, returning the image with its placeholder set.
+func (s ImageService) Placeholder(ctx context.Context, img media.Image) (media.Image, error) {
+	srcURL := img.URL
+	if len(img.Variants) > 0 {
+		srcURL = img.Variants[0].URL
+	}
+
+	decoded, err := s.decodeObject(ctx, srcURL)
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	if err := withPlaceholder(&img, decoded); err != nil {
+		return media.Image{}, err
+	}
+
+	return img, nil
+}
+
+// Copies the objects of the given image to new objects, returning the copy.
+// Used when an image must outlive the entity it was copied from.
+func (s ImageService) Copy(ctx context.Context, purpose media.Purpose, img media.Image) (media.Image, error) {
+	if _, ok := imageDirs[purpose]; !ok {
+		return media.Image{}, media.ErrInvalidPurpose
+	}
+
+	id := uuid.NewString()
+
+	// Images of media assets keep sharing the variants of the asset, so only
+	// their crops are copied.
+	if img.MediaID > 0 {
+		crops, err := s.copyCrops(ctx, purpose, id, img.Crops)
+		if err != nil {
+			return media.Image{}, err
+		}
+
+		copied := img
+		copied.Variants = slices.Clone(img.Variants)
+		copied.Crops = crops
+
+		return copied, nil
+	}
+
+	// Images without variants keep their file extension.
+	if len(img.Variants) <= 0 {
+		extension := strings.TrimPrefix(path.Ext(img.URL), ".")
+		if extension == "" {
+			extension = "jpeg"
+		}
+
+		u, err := s.copyObject(ctx, img.URL, path.Join(imageDirs[purpose], id+"."+extension))
+		if err != nil {
+			return media.Image{}, err
+		}
+
+		copied, err := media.NewImage(u)
+		if err != nil {
+			return media.Image{}, err
+		}
+
+		copied.BlurHash, copied.Color = img.BlurHash, img.Color
+		copied.Attribution = img.Attribution
+		return copied, nil
+	}
+
+	variants, err := s.copyVariants(ctx, img.Variants, func(width int) string {
+		return variantKey(purpose, id, width)
+	})
+
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	copyURL := variants[len(variants)-1].URL
+	if i := slices.IndexFunc(img.Variants, func(v media.Variant) bool { return v.URL == img.URL }); i >= 0 {
+		copyURL = variants[i].URL
+	}
+
+	copied, err := media.NewImage(copyURL, variants...)
+	if err != nil {
+		s.deleteVariants(ctx, variants)
+		return media.Image{}, err
+	}
+
+	copied.BlurHash, copied.Color = img.BlurHash, img.Color
+	copied.Attribution = img.Attribution
+	copied.Framing = img.Framing
+
+	copied.Crops, err = s.copyCrops(ctx, purpose, id, img.Crops)
+	if err != nil {
+		s.deleteVariants(ctx, variants)
+		return media.Image{}, err
+	}
+
+	return copied, nil
+}
+
+// Copies the given crops to new objects, under the crop keys of the given
+// id.
+func (s ImageService) copyCrops(ctx context.Context, purpose media.Purpose, id string, crops []media.AspectCrop) ([]media.AspectCrop, error) {
+	copies := make([]media.AspectCrop, 0)
+
+	for _, c := range crops {
+		variants, err := s.copyVariants(ctx, c.Variants, func(width int) string {
+			return cropKey(purpose, id, c.Aspect, width)
+		})
+
 		if err != nil {
-			pw.CloseWithError(err)
+			s.deleteCrops(ctx, copies)
+			return nil, err
 		}
-	}()
 
-	return pr, nil
+		copies = append(copies, media.AspectCrop{
+			Aspect:   c.Aspect,
+			Framing:  c.Framing,
+			Variants: variants,
+		})
+	}
+
+	return copies, nil
+}
+
+// Deletes the objects of the given image and all its variants. The variants
+// of images of media assets belong to the asset, and are left in place.
+func (s ImageService) Delete(ctx context.Context, img media.Image) error {
+	return s.deleteURLs(ctx, img.OwnedURLs())
+}
+
+// Deletes the objects of the previous image, which are not used by the next
+// image, such as when an image is replaced or cropped anew.
+func (s ImageService) DeleteReplaced(ctx context.Context, prev, next media.Image) error {
+	nextURLs := next.URLs()
+
+	unused := slices.DeleteFunc(prev.OwnedURLs(), func(u string) bool {
+		return slices.Contains(nextURLs, u)
+	})
+
+	return s.deleteURLs(ctx, unused)
+}
+
+func (s ImageService) deleteURLs(ctx context.Context, urls []string) error {
+	errs := make([]error, 0)
+
+	for _, imageURL := range urls {
+		u, err := url.Parse(imageURL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := s.store.Delete(ctx, u.Path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Stores the variants of the given image of the widths of the profile,
+// under the keys returned by the given function.
+func (s ImageService) storeVariants(ctx context.Context, img image.Image, profile media.Profile, key func(width int) string) ([]media.Variant, error) {
+	variants := make([]media.Variant, 0)
+
+	for _, width := range profile.VariantWidths(img.Bounds().Dx()) {
+		variant := img
+		if width < img.Bounds().Dx() {
+			variant = resize.Resize(uint(width), 0, img, resize.Lanczos2)
+		}
+
+		body, err := encodeJPEG(variant, profile.Quality)
+		if err != nil {
+			s.deleteVariants(ctx, variants)
+			return nil, err
+		}
+
+		u, err := s.store.Upload(ctx, key(width), body)
+		if err != nil {
+			s.deleteVariants(ctx, variants)
+			return nil, err
+		}
+
+		variants = append(variants, media.Variant{Width: width, URL: u})
+	}
+
+	return variants, nil
+}
+
+// Stores the crops of the given image of each aspect ratio of the profile,
+// under the keys /<directory>/<uuid>-<width>x<height>-<width>w.jpeg.
+func (s ImageService) storeCrops(
+	ctx context.Context,
+	purpose media.Purpose,
+	id string,
+	img image.Image,
+	framing media.Framing,
+	profile media.Profile,
+) ([]media.AspectCrop, error) {
+	crops := make([]media.AspectCrop, 0)
+
+	for _, aspect := range profile.Aspects {
+		rect, err := framing.Rect(img.Bounds().Dx(), img.Bounds().Dy(), aspect)
+		if err != nil {
+			s.deleteCrops(ctx, crops)
+			return nil, err
+		}
+
+		cropped := subImage(img, rect.Add(img.Bounds().Min))
+
+		variants, err := s.storeVariants(ctx, cropped, profile, func(width int) string {
+			return cropKey(purpose, id, aspect, width)
+		})
+
+		if err != nil {
+			s.deleteCrops(ctx, crops)
+			return nil, err
+		}
+
+		crops = append(crops, media.AspectCrop{
+			Aspect:   aspect,
+			Framing:  framing,
+			Variants: variants,
+		})
+	}
+
+	return crops, nil
+}
+
+// Copies the given variants to new objects, under the keys returned by the
+// given function.
+func (s ImageService) copyVariants(ctx context.Context, variants []media.Variant, key func(width int) string) ([]media.Variant, error) {
+	copies := make([]media.Variant, 0)
+
+	for _, v := range variants {
+		u, err := s.copyObject(ctx, v.URL, key(v.Width))
+		if err != nil {
+			s.deleteVariants(ctx, copies)
+			return nil, err
+		}
+
+		copies = append(copies, media.Variant{Width: v.Width, URL: u})
+	}
+
+	return copies, nil
+}
+
+func (s ImageService) copyObject(ctx context.Context, srcURL string, key string) (string, error) {
+	u, err := url.Parse(srcURL)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := s.store.Get(ctx, u.Path)
+	if err != nil {
+		return "", err
+	}
+
+	defer body.Close()
+
+	return s.store.Upload(ctx, key, body)
+}
+
+// Decodes the stored image object of the given URL.
+func (s ImageService) decodeObject(ctx context.Context, objectURL string) (image.Image, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.store.Get(ctx, u.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	img, _, err := image.Decode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// Deletes the given variants, after a failed upload or copy. Failures are
+// ignored, as the objects are then merely orphaned.
+func (s ImageService) deleteVariants(ctx context.Context, variants []media.Variant) {
+	_ = s.Delete(ctx, media.Image{Variants: variants})
+}
+
+// Deletes the given crops, after a failed upload. Failures are ignored, as
+// the objects are then merely orphaned.
+func (s ImageService) deleteCrops(ctx context.Context, crops []media.AspectCrop) {
+	_ = s.Delete(ctx, media.Image{Crops: crops})
+}
+
+// Sets the BlurHash and dominant colour of the given image, computed from its
+// decoded pixels.
+func withPlaceholder(dst *media.Image, img image.Image) error {
+	small := img
+	if img.Bounds().Dx() > PLACEHOLDER_WIDTH_PX {
+		small = resize.Resize(PLACEHOLDER_WIDTH_PX, 0, img, resize.Bilinear)
+	}
+
+	hash, err := placeholder.BlurHash(small, placeholder.DEFAULT_X_COMPONENTS, placeholder.DEFAULT_Y_COMPONENTS)
+	if err != nil {
+		return err
+	}
+
+	dst.BlurHash = hash
+	dst.Color = placeholder.DominantColor(small)
+
+	return nil
+}
+
+func variantKey(purpose media.Purpose, id string, width int) string {
+	return path.Join(imageDirs[purpose], fmt.Sprintf("%s-%dw.jpeg", id, width))
+}
+
+func cropKey(purpose media.Purpose, id string, aspect media.Aspect, width int) string {
+	ratio := strings.ReplaceAll(string(aspect), ":", "x")
+	return path.Join(imageDirs[purpose], fmt.Sprintf("%s-%s-%dw.jpeg", id, ratio, width))
+}
+
+// Returns the given rectangle of the image, sharing its pixels if possible.
+func subImage(img image.Image, rect image.Rectangle) image.Image {
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+
+	return dst
 }
 
-func createRandomImageFileName(extension string) string {
-	extension = strings.TrimPrefix(extension, ".")
+func encodeJPEG(img image.Image, quality int) (io.Reader, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
 
-	return fmt.Sprintf("%s.%s", uuid.NewString(), extension)
+	return &buf, nil
 }
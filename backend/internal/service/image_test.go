@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/object/local"
+	"github.com/mattismoel/konnekt/internal/service"
+)
+
+func TestVerifyImage(t *testing.T) {
+	const baseURL = "http://localhost:8080/objects"
+
+	type test struct {
+		img     media.Image
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Image of purpose": {
+			img: media.Image{
+				URL:      baseURL + "/events/a-1080w.jpeg",
+				Variants: []media.Variant{{Width: 1080, URL: baseURL + "/events/a-1080w.jpeg"}},
+			},
+		},
+		"Image of other purpose": {
+			img:     media.Image{URL: baseURL + "/artists/a-1080w.jpeg"},
+			wantErr: media.ErrForeignImage,
+		},
+		"Variant of media asset": {
+			img: media.Image{
+				URL:      baseURL + "/events/a-1080w.jpeg",
+				Variants: []media.Variant{{Width: 1080, URL: baseURL + "/media/a-1080w.jpeg"}},
+			},
+			wantErr: media.ErrForeignImage,
+		},
+		"Crop of other purpose": {
+			img: media.Image{
+				MediaID: 1,
+				URL:     baseURL + "/media/a-1080w.jpeg",
+				Crops: []media.AspectCrop{{
+					Aspect:   "1:1",
+					Variants: []media.Variant{{Width: 1080, URL: baseURL + "/artists/a-1x1-1080w.jpeg"}},
+				}},
+			},
+			wantErr: media.ErrForeignImage,
+		},
+		"Traversing path": {
+			img:     media.Image{URL: baseURL + "/events/../media/a-1080w.jpeg"},
+			wantErr: media.ErrForeignImage,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, err := local.NewLocalObjectStore(t.TempDir(), baseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			images, err := service.NewImageService(store)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = images.Verify(media.PurposeEvent, tt.img)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
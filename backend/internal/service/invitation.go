@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+	"github.com/mattismoel/konnekt/internal/domain/invitation"
+	"github.com/mattismoel/konnekt/internal/domain/member"
+	"github.com/mattismoel/konnekt/internal/domain/team"
+	"github.com/mattismoel/konnekt/internal/mailer"
+	"github.com/mattismoel/konnekt/internal/query"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const INVITATION_LIFETIME = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidInvitationURL = errors.New("Invitation URL must be an absolute URL")
+	ErrInvitationNoMailer   = errors.New("Invitations are unavailable, as no mailer is configured")
+)
+
+type InvitationService struct {
+	invitationRepo invitation.Repository
+	memberRepo     member.Repository
+	teamRepo       team.Repository
+
+	// Delivers invitation links.
+	mailer mailer.Mailer
+
+	// The URL of the page, on which invitees accept an invitation. The
+	// invitation token is appended as the token query parameter.
+	acceptURL string
+}
+
+func NewInvitationService(
+	invitationRepo invitation.Repository,
+	memberRepo member.Repository,
+	teamRepo team.Repository,
+	mailer mailer.Mailer,
+	acceptURL string,
+) (*InvitationService, error) {
+	if mailer == nil {
+		return nil, ErrInvitationNoMailer
+	}
+
+	u, err := url.Parse(acceptURL)
+	if err != nil || !u.IsAbs() {
+		return nil, ErrInvalidInvitationURL
+	}
+
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		memberRepo:     memberRepo,
+		teamRepo:       teamRepo,
+		mailer:         mailer,
+		acceptURL:      acceptURL,
+	}, nil
+}
+
+func (srv InvitationService) List(ctx context.Context, q query.ListQuery) (query.ListResult[invitation.Invitation], error) {
+	result, err := srv.invitationRepo.List(ctx, q)
+	if err != nil {
+		return query.ListResult[invitation.Invitation]{}, err
+	}
+
+	return result, nil
+}
+
+// Invites the email to join the given teams on behalf of the inviting member,
+// emailing the invitee a link with which the invitation may be accepted. Any
+// earlier invitation of the email is replaced, so that only the latest link
+// may be used.
+func (srv InvitationService) Invite(ctx context.Context, invitedBy int64, email string, teamIDs ...int64) (invitation.Invitation, error) {
+	inviter, err := srv.memberRepo.ByID(ctx, invitedBy)
+	if err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	teams := make(team.TeamCollection, 0)
+	for _, teamID := range teamIDs {
+		t, err := srv.teamRepo.ByID(ctx, teamID)
+		if err != nil {
+			return invitation.Invitation{}, err
+		}
+
+		teams = append(teams, t)
+	}
+
+	token, err := invitation.NewToken()
+	if err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	inv, err := invitation.NewInvitation(token, email, teams, invitedBy, INVITATION_LIFETIME)
+	if err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	_, err = srv.memberRepo.ByEmail(ctx, inv.Email)
+	if err == nil {
+		return invitation.Invitation{}, member.ErrAlreadyExists
+	}
+
+	if !errors.Is(err, member.ErrNotFound) {
+		return invitation.Invitation{}, err
+	}
+
+	if err := srv.invitationRepo.DeleteByEmail(ctx, inv.Email); err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	invitationID, err := srv.invitationRepo.Insert(ctx, inv)
+	if err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	link, err := tokenURL(srv.acceptURL, string(token))
+	if err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	teamNames := make([]string, 0, len(teams))
+	for _, t := range teams {
+		teamNames = append(teamNames, t.DisplayName)
+	}
+
+	err = srv.mailer.Send(ctx, mailer.Message{
+		To:      inv.Email,
+		Subject: "Du er inviteret til Konnekt",
+		Body: fmt.Sprintf(
+			"Hej,\n\n"+
+				"%s %s har inviteret dig til at blive en del af Konnekt (%s). "+
+				"Følg linket herunder for at oprette din konto. Linket udløber om syv dage og kan kun bruges én gang.\n\n"+
+				"%s\n\n"+
+				"Forventede du ikke denne invitation, kan du se bort fra denne mail.\n",
+			inviter.FirstName, inviter.LastName, strings.Join(teamNames, ", "), link,
+		),
+	})
+
+	// An invitation, which never reached the invitee, cannot be accepted.
+	if err != nil {
+		if err := srv.invitationRepo.Delete(ctx, invitationID); err != nil {
+			return invitation.Invitation{}, err
+		}
+
+		return invitation.Invitation{}, err
+	}
+
+	return srv.invitationRepo.ByID(ctx, invitationID)
+}
+
+// Revokes the invitation, so that its link can no longer be used.
+func (srv InvitationService) Revoke(ctx context.Context, invitationID int64) error {
+	err := srv.invitationRepo.Delete(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type AcceptInvitationLoad struct {
+	FirstName       string
+	LastName        string
+	Password        auth.Password
+	PasswordConfirm auth.Password
+}
+
+// Accepts the invitation of the token, creating an active member of the
+// invited email, who is assigned the teams of the invitation. The email is
+// considered verified, as the invitation was received by it. The invitation
+// is used up.
+func (srv InvitationService) Accept(ctx context.Context, token invitation.Token, load AcceptInvitationLoad) (member.Member, error) {
+	// The password is validated before the invitation is used, so that the
+	// link may be retried with a valid password.
+	if err := load.Password.Validate(); err != nil {
+		return member.Member{}, err
+	}
+
+	if err := load.Password.Matches(load.PasswordConfirm); err != nil {
+		return member.Member{}, err
+	}
+
+	inv, err := srv.invitationRepo.ByTokenHash(ctx, token.Hash())
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	if inv.IsExpired() {
+		return member.Member{}, invitation.ErrInvalidToken
+	}
+
+	_, err = srv.memberRepo.ByEmail(ctx, inv.Email)
+	if err == nil {
+		return member.Member{}, member.ErrAlreadyExists
+	}
+
+	if !errors.Is(err, member.ErrNotFound) {
+		return member.Member{}, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword(load.Password, bcrypt.DefaultCost)
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	m, err := member.NewMember(
+		member.WithEmail(inv.Email),
+		member.WithFirstName(load.FirstName),
+		member.WithLastName(load.LastName),
+		member.WithPasswordHash(hash),
+	)
+
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	memberID, err := srv.invitationRepo.Accept(ctx, inv.ID, m, time.Now())
+	if err != nil {
+		return member.Member{}, err
+	}
+
+	return srv.memberRepo.ByID(ctx, memberID)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/exif"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+// Manages the media library, whose assets may be used by artists, events and
+// landing images.
+type MediaService struct {
+	mediaRepo media.Repository
+	images    *ImageService
+}
+
+func NewMediaService(mediaRepo media.Repository, images *ImageService) (*MediaService, error) {
+	return &MediaService{
+		mediaRepo: mediaRepo,
+		images:    images,
+	}, nil
+}
+
+type AssetLoad struct {
+	Title   string
+	AltText string
+	Credit  string
+	Tags    []string
+}
+
+func (s MediaService) List(ctx context.Context, q query.ListQuery) (query.ListResult[media.Asset], error) {
+	result, err := s.mediaRepo.List(ctx, q)
+	if err != nil {
+		return query.ListResult[media.Asset]{}, err
+	}
+
+	return result, nil
+}
+
+func (s MediaService) ByID(ctx context.Context, assetID int64) (media.Asset, error) {
+	a, err := s.mediaRepo.ByID(ctx, assetID)
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	return a, nil
+}
+
+// Uploads the given image to the media library.
+func (s MediaService) Upload(ctx context.Context, r io.Reader, load AssetLoad) (media.Asset, error) {
+	a, err := media.NewAsset(
+		media.WithTitle(load.Title),
+		media.WithAltText(load.AltText),
+		media.WithCredit(load.Credit),
+		media.WithTags(load.Tags...),
+		media.WithCreatedAt(time.Now()),
+	)
+
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	cfg, _, err := exif.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	img, err := s.images.Upload(ctx, media.PurposeMedia, bytes.NewReader(data), media.Framing{})
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	err = a.WithCfgs(
+		media.WithDimensions(cfg.Width, cfg.Height),
+		media.WithAssetImage(img),
+	)
+
+	if err != nil {
+		_ = s.images.Delete(ctx, img)
+		return media.Asset{}, err
+	}
+
+	assetID, err := s.mediaRepo.Insert(ctx, *a)
+	if err != nil {
+		_ = s.images.Delete(ctx, img)
+		return media.Asset{}, err
+	}
+
+	created, err := s.mediaRepo.ByID(ctx, assetID)
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	return created, nil
+}
+
+// Updates the title, alt text, credit and tags of the given asset.
+func (s MediaService) Update(ctx context.Context, assetID int64, load AssetLoad) (media.Asset, error) {
+	if _, err := s.mediaRepo.ByID(ctx, assetID); err != nil {
+		return media.Asset{}, err
+	}
+
+	a, err := media.NewAsset(
+		media.WithAssetID(assetID),
+		media.WithTitle(load.Title),
+		media.WithAltText(load.AltText),
+		media.WithCredit(load.Credit),
+		media.WithTags(load.Tags...),
+	)
+
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	if err := s.mediaRepo.Update(ctx, assetID, *a); err != nil {
+		return media.Asset{}, err
+	}
+
+	updated, err := s.mediaRepo.ByID(ctx, assetID)
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	return updated, nil
+}
+
+// Returns the entities using the given asset.
+func (s MediaService) References(ctx context.Context, assetID int64) ([]media.Reference, error) {
+	if _, err := s.mediaRepo.ByID(ctx, assetID); err != nil {
+		return nil, err
+	}
+
+	refs, err := s.mediaRepo.References(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// Deletes the given asset along with its image. Assets in use are not
+// deleted, and media.ErrAssetInUse is returned.
+func (s MediaService) Delete(ctx context.Context, assetID int64) error {
+	a, err := s.mediaRepo.ByID(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mediaRepo.Delete(ctx, assetID); err != nil {
+		return err
+	}
+
+	if err := s.images.Delete(ctx, a.Image); err != nil {
+		return err
+	}
+
+	return nil
+}
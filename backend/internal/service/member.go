@@ -2,31 +2,25 @@ package service
 
 import (
 	"context"
-	"image"
 	"io"
-	"net/url"
-	"path"
 
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/domain/member"
 	"github.com/mattismoel/konnekt/internal/domain/team"
-	"github.com/mattismoel/konnekt/internal/object"
 	"github.com/mattismoel/konnekt/internal/query"
-	"github.com/nfnt/resize"
 )
 
-const PROFILE_PICTURE_WIDTH_PX = 512
-
 type MemberService struct {
-	memberRepo  member.Repository
-	teamRepo    team.Repository
-	objectStore object.Store
+	memberRepo member.Repository
+	teamRepo   team.Repository
+	images     *ImageService
 }
 
-func NewMemberService(memberRepo member.Repository, teamRepo team.Repository, objectStore object.Store) (*MemberService, error) {
+func NewMemberService(memberRepo member.Repository, teamRepo team.Repository, images *ImageService) (*MemberService, error) {
 	return &MemberService{
-		memberRepo:  memberRepo,
-		teamRepo:    teamRepo,
-		objectStore: objectStore,
+		memberRepo: memberRepo,
+		teamRepo:   teamRepo,
+		images:     images,
 	}, nil
 }
 
@@ -48,47 +42,29 @@ func (srv MemberService) List(ctx context.Context, q query.ListQuery) (query.Lis
 	return result, nil
 }
 
-func (srv MemberService) UploadProfilePicture(ctx context.Context, r io.Reader) (string, error) {
-	img, _, err := image.Decode(r)
+func (srv MemberService) UploadProfilePicture(ctx context.Context, r io.Reader) (media.Image, error) {
+	img, err := srv.images.Upload(ctx, media.PurposeMember, r, media.Framing{})
 	if err != nil {
-		return "", err
-	}
-
-	if img.Bounds().Max.X > PROFILE_PICTURE_WIDTH_PX {
-		img = resize.Resize(PROFILE_PICTURE_WIDTH_PX, 0, img, resize.Lanczos2)
+		return media.Image{}, err
 	}
 
-	formatedImg, err := formatJPEG(img)
-	if err != nil {
-		return "", err
-	}
-
-	fileName := createRandomImageFileName("jpeg")
-
-	url, err := srv.objectStore.Upload(ctx, path.Join("/members", fileName), formatedImg)
-	if err != nil {
-		return "", err
-	}
-
-	return url, nil
+	return img, nil
 }
 
-func (srv MemberService) DeleteProfilePicture(ctx context.Context, url string) error {
-	err := srv.objectStore.Delete(ctx, url)
+// Approves the member, returning the approved member. Whether or not the
+// member has verified the email is shown by the returned member.
+func (srv MemberService) Approve(ctx context.Context, memberID int64) (member.Member, error) {
+	err := srv.memberRepo.Approve(ctx, memberID)
 	if err != nil {
-		return err
+		return member.Member{}, err
 	}
 
-	return nil
-}
-
-func (srv MemberService) Approve(ctx context.Context, memberID int64) error {
-	err := srv.memberRepo.Approve(ctx, memberID)
+	m, err := srv.memberRepo.ByID(ctx, memberID)
 	if err != nil {
-		return err
+		return member.Member{}, err
 	}
 
-	return nil
+	return m, nil
 }
 
 func (srv MemberService) Delete(ctx context.Context, memberID int64) error {
@@ -97,12 +73,7 @@ func (srv MemberService) Delete(ctx context.Context, memberID int64) error {
 		return err
 	}
 
-	url, err := url.Parse(m.ProfilePictureURL)
-	if err != nil {
-		return err
-	}
-
-	err = srv.objectStore.Delete(ctx, url.Path)
+	err = srv.images.Delete(ctx, m.ProfilePicture)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/mattismoel/konnekt/internal/domain/artist"
+	"github.com/mattismoel/konnekt/internal/domain/content"
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/member"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+// Computes the placeholders of images stored before placeholders were
+// introduced.
+type PlaceholderService struct {
+	images      *ImageService
+	artistRepo  artist.Repository
+	eventRepo   event.Repository
+	seriesRepo  series.Repository
+	memberRepo  member.Repository
+	contentRepo content.Repository
+}
+
+func NewPlaceholderService(
+	images *ImageService,
+	artistRepo artist.Repository,
+	eventRepo event.Repository,
+	seriesRepo series.Repository,
+	memberRepo member.Repository,
+	contentRepo content.Repository,
+) (*PlaceholderService, error) {
+	return &PlaceholderService{
+		images:      images,
+		artistRepo:  artistRepo,
+		eventRepo:   eventRepo,
+		seriesRepo:  seriesRepo,
+		memberRepo:  memberRepo,
+		contentRepo: contentRepo,
+	}, nil
+}
+
+// Computes and stores the placeholder of every stored image without one,
+// returning the amount of updated images. Images, which cannot be read from
+// the object store, are logged and skipped.
+func (s PlaceholderService) Backfill(ctx context.Context) (int, error) {
+	// Occurrences of recurring events and cloned events may share images, which
+	// are then only read once.
+	computed := make(map[string]media.Image)
+	updated := 0
+
+	backfill := func(kind string, id int64, img media.Image, set func(media.Image) error) error {
+		if img.URL == "" || img.BlurHash != "" {
+			return nil
+		}
+
+		withPlaceholder, ok := computed[img.URL]
+		if !ok {
+			var err error
+			withPlaceholder, err = s.images.Placeholder(ctx, img)
+			if err != nil {
+				slog.Warn("Could not compute image placeholder", "kind", kind, "id", id, "url", img.URL, "error", err)
+				return nil
+			}
+
+			computed[img.URL] = withPlaceholder
+		}
+
+		img.BlurHash, img.Color = withPlaceholder.BlurHash, withPlaceholder.Color
+		if err := set(img); err != nil {
+			return err
+		}
+
+		updated++
+		return nil
+	}
+
+	// Lists are unlimited by default, so every record is on the first page.
+	q, err := query.NewListQuery()
+	if err != nil {
+		return updated, err
+	}
+
+	artists, err := s.artistRepo.List(ctx, q)
+	if err != nil {
+		return updated, err
+	}
+
+	for _, a := range artists.Records {
+		err := backfill("artist", a.ID, a.Image, func(img media.Image) error {
+			return s.artistRepo.SetImage(ctx, a.ID, img)
+		})
+
+		if err != nil {
+			return updated, err
+		}
+	}
+
+	// Event listings leave out events without concerts, so the images are
+	// read directly.
+	eventImages, err := s.eventRepo.Images(ctx)
+	if err != nil {
+		return updated, err
+	}
+
+	for _, eventID := range slices.Sorted(maps.Keys(eventImages)) {
+		err := backfill("event", eventID, eventImages[eventID], func(img media.Image) error {
+			return s.eventRepo.SetImage(ctx, eventID, img)
+		})
+
+		if err != nil {
+			return updated, err
+		}
+	}
+
+	seriesResult, err := s.seriesRepo.List(ctx, q)
+	if err != nil {
+		return updated, err
+	}
+
+	for _, se := range seriesResult.Records {
+		err := backfill("series", se.ID, se.Image, func(img media.Image) error {
+			return s.seriesRepo.SetImage(ctx, se.ID, img)
+		})
+
+		if err != nil {
+			return updated, err
+		}
+	}
+
+	members, err := s.memberRepo.List(ctx, q)
+	if err != nil {
+		return updated, err
+	}
+
+	for _, m := range members.Records {
+		err := backfill("member", m.ID, m.ProfilePicture, func(img media.Image) error {
+			return s.memberRepo.SetProfilePicture(ctx, m.ID, img)
+		})
+
+		if err != nil {
+			return updated, err
+		}
+	}
+
+	landingImages, err := s.contentRepo.LandingImages(ctx)
+	if err != nil {
+		return updated, err
+	}
+
+	for _, l := range landingImages {
+		err := backfill("landing image", l.ID, l.Image, func(img media.Image) error {
+			return s.contentRepo.SetLandingImage(ctx, l.ID, img)
+		})
+
+		if err != nil {
+			return updated, err
+		}
+	}
+
+	return updated, nil
+}
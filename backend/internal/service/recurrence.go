@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+type CreateRecurringEvent struct {
+	CreateEvent
+
+	// The RFC 5545 recurrence rule, such as "FREQ=WEEKLY;COUNT=10".
+	Rule string
+
+	// The IANA time zone, in which occurrences keep their local time.
+	// Defaults to UTC.
+	TimeZone string
+
+	// Dates of format YYYY-MM-DD, on which no occurrence takes place.
+	Exceptions []string
+}
+
+// Creates an event for every occurrence of the recurrence. The concerts of the
+// load describe the first occurrence, and are shifted to every following
+// occurrence. The created events are returned in order of occurrence, and may
+// each be edited individually afterwards.
+func (s EventService) CreateRecurring(ctx context.Context, load CreateRecurringEvent) ([]event.Event, error) {
+	e, err := s.newEvent(ctx, load.CreateEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(e.Concerts) <= 0 {
+		return nil, event.ErrRecurrenceNoConcerts
+	}
+
+	r, err := event.NewRecurrence(
+		event.WithRule(load.Rule),
+		event.WithTimeZone(load.TimeZone),
+		event.WithExceptions(load.Exceptions...),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	base := e.Concerts[0].From
+	for _, c := range e.Concerts {
+		if c.From.Before(base) {
+			base = c.From
+		}
+	}
+
+	occurrences, err := r.Occurrences(base)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]event.Event, 0)
+	for _, occurrence := range occurrences {
+		events = append(events, e.ForOccurrence(base, occurrence))
+	}
+
+	if !load.Force {
+		if err := s.checkAllConflicts(ctx, events); err != nil {
+			return nil, err
+		}
+	}
+
+	eventIDs, err := s.eventRepo.InsertRecurring(ctx, *r, events)
+	if err != nil {
+		return nil, err
+	}
+
+	createdEvents := make([]event.Event, 0)
+	for _, eventID := range eventIDs {
+		createdEvent, err := s.eventRepo.ByID(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+
+		createdEvents = append(createdEvents, createdEvent)
+	}
+
+	return createdEvents, nil
+}
+
+// Updates the given occurrence of a recurring event along with all following
+// occurrences. The concerts of the load describe the given occurrence, and
+// keep their offset from the start of every following occurrence. Earlier
+// occurrences are left untouched.
+func (s EventService) UpdateFollowing(ctx context.Context, eventID int64, load UpdateEvent) (event.Event, error) {
+	prevEvent, err := s.eventRepo.ByID(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	if prevEvent.Recurrence == nil || prevEvent.OccurrenceStart == nil {
+		return event.Event{}, event.ErrNotRecurring
+	}
+
+	following, err := s.followingOccurrences(ctx, prevEvent)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	e, err := s.updatedEvent(ctx, eventID, load)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	// All occurrences are validated before any is saved, so that a conflict
+	// does not leave the recurrence partially updated.
+	updates := make([]event.Event, 0)
+	conflictCandidates := make([]event.Event, 0)
+
+	for _, f := range following {
+		update := e.ForOccurrence(*prevEvent.OccurrenceStart, *f.OccurrenceStart)
+		update.ID = f.ID
+
+		if err := validatePublicUpdate(f, update); err != nil {
+			return event.Event{}, err
+		}
+
+		updates = append(updates, update)
+
+		// Cancelled events do not occupy their artists or venue.
+		if f.Status != event.StatusCancelled {
+			conflictCandidates = append(conflictCandidates, update)
+		}
+	}
+
+	if !load.Force {
+		if err := s.checkAllConflicts(ctx, conflictCandidates); err != nil {
+			return event.Event{}, err
+		}
+	}
+
+	if err := s.eventRepo.UpdateOccurrences(ctx, updates); err != nil {
+		return event.Event{}, err
+	}
+
+	updatedEvent, err := s.eventRepo.ByID(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	return updatedEvent, nil
+}
+
+// Returns the given occurrence and all following occurrences of its
+// recurrence, in order of occurrence.
+func (s EventService) followingOccurrences(ctx context.Context, e event.Event) ([]event.Event, error) {
+	q, err := query.NewListQuery(query.WithFilters(query.FilterCollection{
+		"recurrence_id": []query.Filter{{Cmp: query.Equal, Value: strconv.FormatInt(e.Recurrence.ID, 10)}},
+	}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.eventRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	following := make([]event.Event, 0)
+	for _, occurrence := range result.Records {
+		if occurrence.OccurrenceStart == nil || occurrence.OccurrenceStart.Before(*e.OccurrenceStart) {
+			continue
+		}
+
+		following = append(following, occurrence)
+	}
+
+	slices.SortFunc(following, func(a, b event.Event) int {
+		return a.OccurrenceStart.Compare(*b.OccurrenceStart)
+	})
+
+	return following, nil
+}
+
+// Checks every event for conflicts, returning a single event.ConflictError
+// with the conflicts of all events.
+func (s EventService) checkAllConflicts(ctx context.Context, events []event.Event) error {
+	conflicts := make([]event.Conflict, 0)
+
+	for _, e := range events {
+		err := s.checkConflicts(ctx, e)
+
+		var conflictErr event.ConflictError
+		if errors.As(err, &conflictErr) {
+			conflicts = append(conflicts, conflictErr.Conflicts...)
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return event.ConflictError{Conflicts: conflicts}
+	}
+
+	return nil
+}
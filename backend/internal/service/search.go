@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mattismoel/konnekt/internal/domain/search"
+)
+
+type SearchService struct {
+	searchRepo search.Repository
+}
+
+func NewSearchService(searchRepo search.Repository) (*SearchService, error) {
+	return &SearchService{
+		searchRepo: searchRepo,
+	}, nil
+}
+
+// Returns the events, artists and venues matching the query, grouped by kind
+// and ordered by relevance within each kind.
+func (s SearchService) Search(ctx context.Context, q search.Query) ([]search.Result, error) {
+	results, err := s.searchRepo.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Rebuilds the search index, so that it includes rows written outside of the
+// application, such as by hand.
+func (s SearchService) Reindex(ctx context.Context) error {
+	if err := s.searchRepo.Reindex(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
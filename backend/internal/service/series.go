@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mattismoel/konnekt/internal/domain/artist"
+	"github.com/mattismoel/konnekt/internal/domain/concert"
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+type SeriesService struct {
+	seriesRepo series.Repository
+	eventRepo  event.Repository
+	images     *ImageService
+}
+
+func NewSeriesService(
+	seriesRepo series.Repository,
+	eventRepo event.Repository,
+	images *ImageService,
+) (*SeriesService, error) {
+	return &SeriesService{
+		seriesRepo: seriesRepo,
+		eventRepo:  eventRepo,
+		images:     images,
+	}, nil
+}
+
+type CreateSeries struct {
+	Name        string
+	Slug        string
+	Description string
+	Image       media.Image
+}
+
+type UpdateSeries struct {
+	Name        string
+	Slug        string
+	Description string
+	Image       media.Image
+}
+
+func (s SeriesService) List(ctx context.Context, q query.ListQuery) (query.ListResult[series.Series], error) {
+	result, err := s.seriesRepo.List(ctx, q)
+	if err != nil {
+		return query.ListResult[series.Series]{}, err
+	}
+
+	return result, nil
+}
+
+func (s SeriesService) ByID(ctx context.Context, seriesID int64) (series.Series, error) {
+	ser, err := s.seriesRepo.ByID(ctx, seriesID)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	return ser, nil
+}
+
+func (s SeriesService) BySlug(ctx context.Context, slug string) (series.Series, error) {
+	ser, err := s.seriesRepo.BySlug(ctx, slug)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	return ser, nil
+}
+
+func (s SeriesService) Create(ctx context.Context, load CreateSeries) (series.Series, error) {
+	ser, err := series.NewSeries(
+		series.WithName(load.Name),
+		series.WithSlug(load.Slug),
+		series.WithDescription(load.Description),
+	)
+
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	if strings.TrimSpace(load.Image.URL) != "" {
+		if err := s.images.Verify(media.PurposeSeries, load.Image); err != nil {
+			return series.Series{}, err
+		}
+
+		if err := ser.WithCfgs(series.WithImage(load.Image)); err != nil {
+			return series.Series{}, err
+		}
+	}
+
+	// Return if the slug is already used by another series.
+	_, err = s.seriesRepo.BySlug(ctx, ser.Slug)
+	if err == nil {
+		return series.Series{}, series.ErrSlugTaken
+	}
+
+	if !errors.Is(err, series.ErrNoExist) {
+		return series.Series{}, err
+	}
+
+	seriesID, err := s.seriesRepo.Insert(ctx, *ser)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	createdSeries, err := s.seriesRepo.ByID(ctx, seriesID)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	return createdSeries, nil
+}
+
+func (s SeriesService) Update(ctx context.Context, seriesID int64, load UpdateSeries) (series.Series, error) {
+	prevSeries, err := s.seriesRepo.ByID(ctx, seriesID)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	ser, err := series.NewSeries(
+		series.WithID(seriesID),
+		series.WithName(load.Name),
+		series.WithSlug(load.Slug),
+		series.WithDescription(load.Description),
+	)
+
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	// Return if the slug is already used by another series.
+	other, err := s.seriesRepo.BySlug(ctx, ser.Slug)
+	if err == nil && other.ID != seriesID {
+		return series.Series{}, series.ErrSlugTaken
+	}
+
+	if err != nil && !errors.Is(err, series.ErrNoExist) {
+		return series.Series{}, err
+	}
+
+	// If there is an image update, delete the previous image.
+	if strings.TrimSpace(load.Image.URL) != "" {
+		if err := s.images.Verify(media.PurposeSeries, load.Image); err != nil {
+			return series.Series{}, err
+		}
+
+		if err := ser.WithCfgs(series.WithImage(load.Image)); err != nil {
+			return series.Series{}, err
+		}
+
+		if prevSeries.Image.URL != "" && prevSeries.Image.URL != ser.Image.URL {
+			if err := s.images.Delete(ctx, prevSeries.Image); err != nil {
+				return series.Series{}, err
+			}
+		}
+	}
+
+	err = s.seriesRepo.Update(ctx, seriesID, *ser)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	updatedSeries, err := s.seriesRepo.ByID(ctx, seriesID)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	return updatedSeries, nil
+}
+
+// Deletes the series. Events of the series are kept, but are no longer part
+// of any series.
+func (s SeriesService) Delete(ctx context.Context, seriesID int64) error {
+	ser, err := s.seriesRepo.ByID(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	if ser.Image.URL != "" {
+		if err := s.images.Delete(ctx, ser.Image); err != nil {
+			return err
+		}
+	}
+
+	err = s.seriesRepo.Delete(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns the public events of the series.
+func (s SeriesService) Events(ctx context.Context, seriesID int64) (query.ListResult[event.Event], error) {
+	q, err := query.NewListQuery(query.WithFilters(query.FilterCollection{
+		"series_id": []query.Filter{{Cmp: query.Equal, Value: strconv.FormatInt(seriesID, 10)}},
+		"is_public": []query.Filter{{Cmp: query.Equal, Value: "true"}},
+	}))
+
+	if err != nil {
+		return query.ListResult[event.Event]{}, err
+	}
+
+	result, err := s.eventRepo.List(ctx, q)
+	if err != nil {
+		return query.ListResult[event.Event]{}, err
+	}
+
+	return result, nil
+}
+
+// Returns the line-up of the series, being all artists performing at the
+// public events of the series. Each artist is only included once, in the
+// order of their first concert.
+func (s SeriesService) Lineup(ctx context.Context, seriesID int64) ([]artist.Artist, error) {
+	if _, err := s.seriesRepo.ByID(ctx, seriesID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.Events(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lineup(result.Records), nil
+}
+
+func (s SeriesService) UploadImage(ctx context.Context, r io.Reader) (media.Image, error) {
+	img, err := s.images.Upload(ctx, media.PurposeSeries, r, media.Framing{})
+	if err != nil {
+		return media.Image{}, err
+	}
+
+	return img, nil
+}
+
+// Returns the distinct artists of the concerts of the given events, ordered by
+// their first concert.
+func lineup(events []event.Event) []artist.Artist {
+	concerts := make([]concert.Concert, 0)
+	for _, e := range events {
+		concerts = append(concerts, e.Concerts...)
+	}
+
+	slices.SortStableFunc(concerts, func(a, b concert.Concert) int {
+		return a.From.Compare(b.From)
+	})
+
+	seen := make(map[int64]bool)
+	artists := make([]artist.Artist, 0)
+
+	for _, c := range concerts {
+		if seen[c.Artist.ID] {
+			continue
+		}
+
+		seen[c.Artist.ID] = true
+		artists = append(artists, c.Artist)
+	}
+
+	return artists
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/artist"
+	"github.com/mattismoel/konnekt/internal/domain/content"
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/member"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+	"github.com/mattismoel/konnekt/internal/object"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+// Images are uploaded before the entity referencing them is saved, so objects
+// must be left alone for some time before they are considered orphaned.
+const DEFAULT_ORPHAN_GRACE_PERIOD = 24 * time.Hour
+
+var ErrInvalidGracePeriod = errors.New("Orphan grace period must not be negative")
+
+// Collects stored image objects, which are not referenced by any entity, such
+// as images of abandoned forms and failed creates.
+type StorageService struct {
+	store       object.Store
+	artistRepo  artist.Repository
+	eventRepo   event.Repository
+	seriesRepo  series.Repository
+	memberRepo  member.Repository
+	contentRepo content.Repository
+	mediaRepo   media.Repository
+
+	gracePeriod time.Duration
+}
+
+type StorageCfgFunc func(s *StorageService) error
+
+func NewStorageService(
+	store object.Store,
+	artistRepo artist.Repository,
+	eventRepo event.Repository,
+	seriesRepo series.Repository,
+	memberRepo member.Repository,
+	contentRepo content.Repository,
+	mediaRepo media.Repository,
+	cfgs ...StorageCfgFunc,
+) (*StorageService, error) {
+	s := &StorageService{
+		store:       store,
+		artistRepo:  artistRepo,
+		eventRepo:   eventRepo,
+		seriesRepo:  seriesRepo,
+		memberRepo:  memberRepo,
+		contentRepo: contentRepo,
+		mediaRepo:   mediaRepo,
+		gracePeriod: DEFAULT_ORPHAN_GRACE_PERIOD,
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Only considers objects orphaned, once they have been stored for the given
+// duration.
+func WithOrphanGracePeriod(d time.Duration) StorageCfgFunc {
+	return func(s *StorageService) error {
+		if d < 0 {
+			return ErrInvalidGracePeriod
+		}
+
+		s.gracePeriod = d
+		return nil
+	}
+}
+
+// Returns the image objects, which are not referenced by any entity, and were
+// stored before the grace period preceding now.
+func (s StorageService) Orphans(ctx context.Context, now time.Time) ([]object.Info, error) {
+	referenced, err := s.referencedKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(imageDirs))
+	for _, dir := range imageDirs {
+		dirs = append(dirs, dir)
+	}
+
+	slices.Sort(dirs)
+
+	cutoff := now.Add(-s.gracePeriod)
+	orphans := make([]object.Info, 0)
+
+	for _, dir := range dirs {
+		infos, err := s.store.List(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, info := range infos {
+			if _, ok := referenced[info.Key]; ok {
+				continue
+			}
+
+			if info.ModTime.After(cutoff) {
+				continue
+			}
+
+			orphans = append(orphans, info)
+		}
+	}
+
+	return orphans, nil
+}
+
+// Deletes the orphaned image objects, returning those deleted. Objects, which
+// could not be deleted, are skipped, and their errors are returned joined.
+func (s StorageService) DeleteOrphans(ctx context.Context, now time.Time) ([]object.Info, error) {
+	orphans, err := s.Orphans(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]object.Info, 0, len(orphans))
+	var errs []error
+
+	for _, orphan := range orphans {
+		if err := s.store.Delete(ctx, orphan.Key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		deleted = append(deleted, orphan)
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// Deletes orphaned image objects at the given interval, until the context is
+// done.
+func (s StorageService) RunCollector(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.DeleteOrphans(ctx, time.Now())
+		if err != nil {
+			slog.Error("Could not delete orphaned objects", "error", err)
+		}
+
+		if len(deleted) > 0 {
+			slog.Info("Deleted orphaned objects", "count", len(deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns the keys of every object referenced by an entity.
+func (s StorageService) referencedKeys(ctx context.Context) (map[string]struct{}, error) {
+	keys := make(map[string]struct{})
+
+	reference := func(img media.Image) error {
+		for _, u := range img.URLs() {
+			key, err := s.store.Key(u)
+			if err != nil {
+				return err
+			}
+
+			keys[key] = struct{}{}
+		}
+
+		return nil
+	}
+
+	// Lists are unlimited by default, so every record is on the first page.
+	q, err := query.NewListQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	artists, err := s.artistRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range artists.Records {
+		if err := reference(a.Image); err != nil {
+			return nil, err
+		}
+	}
+
+	// Event listings leave out events without concerts, so the images are
+	// read directly.
+	eventImages, err := s.eventRepo.Images(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, img := range eventImages {
+		if err := reference(img); err != nil {
+			return nil, err
+		}
+	}
+
+	seriesResult, err := s.seriesRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, se := range seriesResult.Records {
+		if err := reference(se.Image); err != nil {
+			return nil, err
+		}
+	}
+
+	members, err := s.memberRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range members.Records {
+		if err := reference(m.ProfilePicture); err != nil {
+			return nil, err
+		}
+	}
+
+	landingImages, err := s.contentRepo.LandingImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range landingImages {
+		if err := reference(l.Image); err != nil {
+			return nil, err
+		}
+	}
+
+	// The media library is always paginated, so every page is visited.
+	for page := 1; ; page++ {
+		mq, err := query.NewListQuery(query.WithPage(page), query.WithPerPage(query.MAX_PER_PAGE))
+		if err != nil {
+			return nil, err
+		}
+
+		assets, err := s.mediaRepo.List(ctx, mq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range assets.Records {
+			if err := reference(a.Image); err != nil {
+				return nil, err
+			}
+		}
+
+		if page >= assets.PageCount {
+			break
+		}
+	}
+
+	return keys, nil
+}
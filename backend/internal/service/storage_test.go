@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/artist"
+	"github.com/mattismoel/konnekt/internal/domain/content"
+	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/member"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+	"github.com/mattismoel/konnekt/internal/object/local"
+	"github.com/mattismoel/konnekt/internal/query"
+	"github.com/mattismoel/konnekt/internal/service"
+)
+
+// The repositories only implement the methods used to find referenced images.
+// Any other method panics.
+
+type artistRepo struct{ artist.Repository }
+
+func (artistRepo) List(context.Context, query.ListQuery) (query.ListResult[artist.Artist], error) {
+	return query.ListResult[artist.Artist]{}, nil
+}
+
+// Holds the images of all events, as stored, regardless of their concerts.
+type eventRepo struct {
+	event.Repository
+	images map[int64]media.Image
+}
+
+func (r eventRepo) Images(context.Context) (map[int64]media.Image, error) {
+	return r.images, nil
+}
+
+type seriesRepo struct{ series.Repository }
+
+func (seriesRepo) List(context.Context, query.ListQuery) (query.ListResult[series.Series], error) {
+	return query.ListResult[series.Series]{}, nil
+}
+
+type memberRepo struct{ member.Repository }
+
+func (memberRepo) List(context.Context, query.ListQuery) (query.ListResult[member.Member], error) {
+	return query.ListResult[member.Member]{}, nil
+}
+
+type contentRepo struct{ content.Repository }
+
+func (contentRepo) LandingImages(context.Context) ([]content.LandingImage, error) {
+	return nil, nil
+}
+
+type mediaRepo struct{ media.Repository }
+
+func (mediaRepo) List(context.Context, query.ListQuery) (query.ListResult[media.Asset], error) {
+	return query.ListResult[media.Asset]{}, nil
+}
+
+func TestDeleteOrphans(t *testing.T) {
+	ctx := context.Background()
+
+	type test struct {
+		// The keys of the stored objects, and the keys of those referenced by
+		// events.
+		stored     []string
+		referenced []string
+		want       []string
+	}
+
+	tests := map[string]test{
+		"Unreferenced object": {
+			stored: []string{"/events/a.jpeg"},
+			want:   []string{},
+		},
+		"Image of event without concerts": {
+			stored:     []string{"/events/a.jpeg", "/events/a-480.jpeg", "/events/b.jpeg"},
+			referenced: []string{"/events/a.jpeg", "/events/a-480.jpeg"},
+			want:       []string{"/events/a-480.jpeg", "/events/a.jpeg"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, err := local.NewLocalObjectStore(t.TempDir(), "http://localhost:8080/objects")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			urls := make(map[string]string)
+			for _, key := range tt.stored {
+				u, err := store.Upload(ctx, key, strings.NewReader("image"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				urls[key] = u
+			}
+
+			var img media.Image
+			for i, key := range tt.referenced {
+				if i == 0 {
+					img.URL = urls[key]
+					continue
+				}
+
+				img.Variants = append(img.Variants, media.Variant{Width: 480, URL: urls[key]})
+			}
+
+			storageService, err := service.NewStorageService(
+				store,
+				artistRepo{},
+				eventRepo{images: map[int64]media.Image{1: img}},
+				seriesRepo{},
+				memberRepo{},
+				contentRepo{},
+				mediaRepo{},
+				service.WithOrphanGracePeriod(0),
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := storageService.DeleteOrphans(ctx, time.Now().Add(time.Minute)); err != nil {
+				t.Fatal(err)
+			}
+
+			infos, err := store.List(ctx, "/events")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0)
+			for _, info := range infos {
+				got = append(got, info.Key)
+			}
+
+			slices.Sort(got)
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
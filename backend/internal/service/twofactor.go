@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+)
+
+const (
+	// The lifetime of a session awaiting a two-factor code, within which the
+	// code must be entered.
+	PENDING_SESSION_LIFETIME = 10 * time.Minute
+
+	// The amount of invalid two-factor codes, after which a pending session is
+	// revoked, and the member must log in again.
+	MAX_TWO_FACTOR_ATTEMPTS = 5
+
+	// The issuer shown for the account in authenticator apps.
+	TWO_FACTOR_ISSUER = "Konnekt"
+)
+
+// The secret of a two-factor enrolment, which the member adds to an
+// authenticator app by scanning the URI as a QR code.
+type TwoFactorEnrolment struct {
+	Secret auth.TOTPSecret
+	URI    string
+}
+
+// The result of confirming two-factor authentication. If the confirming
+// session awaited enrolment, it is replaced by a valid session of the token.
+type TwoFactorConfirmation struct {
+	RecoveryCodes []auth.RecoveryCode
+	Token         auth.SessionToken
+	ExpiresAt     time.Time
+}
+
+type TwoFactorStatus struct {
+	Enabled bool
+
+	// Whether or not two-factor authentication is required for the teams of
+	// the member, in which case it cannot be disabled.
+	Required bool
+
+	// The amount of unused recovery codes of the member.
+	RecoveryCodesLeft int
+}
+
+func (srv AuthService) TwoFactorStatus(ctx context.Context, memberID int64) (TwoFactorStatus, error) {
+	required, err := srv.requiresTwoFactor(ctx, memberID)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	tf, err := srv.authRepo.TwoFactor(ctx, memberID)
+	if err != nil {
+		if errors.Is(err, auth.ErrTwoFactorNotEnrolled) {
+			return TwoFactorStatus{Required: required}, nil
+		}
+
+		return TwoFactorStatus{}, err
+	}
+
+	count, err := srv.authRepo.RecoveryCodeCount(ctx, memberID)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	return TwoFactorStatus{
+		Enabled:           tf.IsEnabled(),
+		Required:          required,
+		RecoveryCodesLeft: count,
+	}, nil
+}
+
+// Begins setting up two-factor authentication for the member of the session,
+// which is either valid or awaits enrolment. Any earlier unconfirmed secret is
+// replaced. The secret is not used until confirmed with a code.
+func (srv AuthService) EnrolTwoFactor(ctx context.Context, token auth.SessionToken) (TwoFactorEnrolment, error) {
+	session, err := srv.twoFactorSession(ctx, token, "", auth.PendingTwoFactorEnrolment)
+	if err != nil {
+		return TwoFactorEnrolment{}, err
+	}
+
+	tf, err := srv.authRepo.TwoFactor(ctx, session.MemberID)
+	if err != nil && !errors.Is(err, auth.ErrTwoFactorNotEnrolled) {
+		return TwoFactorEnrolment{}, err
+	}
+
+	if tf.IsEnabled() {
+		return TwoFactorEnrolment{}, auth.ErrTwoFactorAlreadyEnabled
+	}
+
+	m, err := srv.memberRepo.ByID(ctx, session.MemberID)
+	if err != nil {
+		return TwoFactorEnrolment{}, err
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return TwoFactorEnrolment{}, err
+	}
+
+	if err := srv.authRepo.SetTwoFactor(ctx, auth.NewTwoFactor(m.ID, secret)); err != nil {
+		return TwoFactorEnrolment{}, err
+	}
+
+	return TwoFactorEnrolment{
+		Secret: secret,
+		URI:    secret.URI(TWO_FACTOR_ISSUER, m.Email),
+	}, nil
+}
+
+// Enables two-factor authentication for the member of the session, once the
+// member has entered a code of the enrolled secret, returning the recovery
+// codes of the member. They are only shown once.
+func (srv AuthService) ConfirmTwoFactor(ctx context.Context, token auth.SessionToken, code string) (TwoFactorConfirmation, error) {
+	session, err := srv.twoFactorSession(ctx, token, "", auth.PendingTwoFactorEnrolment)
+	if err != nil {
+		return TwoFactorConfirmation{}, err
+	}
+
+	tf, err := srv.authRepo.TwoFactor(ctx, session.MemberID)
+	if err != nil {
+		return TwoFactorConfirmation{}, err
+	}
+
+	if tf.IsEnabled() {
+		return TwoFactorConfirmation{}, auth.ErrTwoFactorAlreadyEnabled
+	}
+
+	period, err := srv.useTOTPCode(ctx, tf, code)
+	if err != nil {
+		return TwoFactorConfirmation{}, srv.failTwoFactorAttempt(ctx, session, err)
+	}
+
+	now := time.Now()
+	tf.ConfirmedAt = &now
+	tf.LastPeriod = period
+
+	if err := srv.authRepo.SetTwoFactor(ctx, tf); err != nil {
+		return TwoFactorConfirmation{}, err
+	}
+
+	codes, err := srv.newRecoveryCodes(ctx, session.MemberID)
+	if err != nil {
+		return TwoFactorConfirmation{}, err
+	}
+
+	confirmation := TwoFactorConfirmation{RecoveryCodes: codes}
+
+	if session.IsPending() {
+		confirmation.Token, confirmation.ExpiresAt, err = srv.completeLogin(ctx, session)
+		if err != nil {
+			return TwoFactorConfirmation{}, err
+		}
+	}
+
+	return confirmation, nil
+}
+
+// Completes the login of the pending session with a two-factor code or a
+// recovery code, replacing the session with a valid session of the returned
+// token. The session is revoked after MAX_TWO_FACTOR_ATTEMPTS invalid codes.
+func (srv AuthService) VerifyTwoFactor(ctx context.Context, token auth.SessionToken, code string) (auth.SessionToken, time.Time, error) {
+	session, err := srv.twoFactorSession(ctx, token, auth.PendingTwoFactor)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	tf, err := srv.authRepo.TwoFactor(ctx, session.MemberID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if !tf.IsEnabled() {
+		return "", time.Time{}, auth.ErrTwoFactorNotEnrolled
+	}
+
+	if err := srv.useCode(ctx, tf, code); err != nil {
+		return "", time.Time{}, srv.failTwoFactorAttempt(ctx, session, err)
+	}
+
+	return srv.completeLogin(ctx, session)
+}
+
+// Replaces the recovery codes of the member, once the member has entered a
+// two-factor code, returning the new codes.
+func (srv AuthService) RegenerateRecoveryCodes(ctx context.Context, memberID int64, code string) ([]auth.RecoveryCode, error) {
+	tf, err := srv.authRepo.TwoFactor(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tf.IsEnabled() {
+		return nil, auth.ErrTwoFactorNotEnrolled
+	}
+
+	if _, err := srv.useTOTPCode(ctx, tf, code); err != nil {
+		return nil, err
+	}
+
+	return srv.newRecoveryCodes(ctx, memberID)
+}
+
+// Disables two-factor authentication of the member, once the member has
+// entered a two-factor code or a recovery code. An unconfirmed enrolment is
+// cancelled without a code. Returns auth.ErrTwoFactorRequired, if two-factor
+// authentication is required for the teams of the member.
+func (srv AuthService) DisableTwoFactor(ctx context.Context, memberID int64, code string) error {
+	tf, err := srv.authRepo.TwoFactor(ctx, memberID)
+	if err != nil {
+		return err
+	}
+
+	if tf.IsEnabled() {
+		required, err := srv.requiresTwoFactor(ctx, memberID)
+		if err != nil {
+			return err
+		}
+
+		if required {
+			return auth.ErrTwoFactorRequired
+		}
+
+		if err := srv.useCode(ctx, tf, code); err != nil {
+			return err
+		}
+	}
+
+	return srv.authRepo.DeleteTwoFactor(ctx, memberID)
+}
+
+// Returns the step of logging in, which the member must complete after
+// entering the password, if any.
+func (srv AuthService) pendingStep(ctx context.Context, memberID int64) (auth.PendingStep, error) {
+	tf, err := srv.authRepo.TwoFactor(ctx, memberID)
+	if err != nil && !errors.Is(err, auth.ErrTwoFactorNotEnrolled) {
+		return "", err
+	}
+
+	if tf.IsEnabled() {
+		return auth.PendingTwoFactor, nil
+	}
+
+	required, err := srv.requiresTwoFactor(ctx, memberID)
+	if err != nil {
+		return "", err
+	}
+
+	if required {
+		return auth.PendingTwoFactorEnrolment, nil
+	}
+
+	return "", nil
+}
+
+// Returns whether or not the member is on a team, for which two-factor
+// authentication is required.
+func (srv AuthService) requiresTwoFactor(ctx context.Context, memberID int64) (bool, error) {
+	if len(srv.twoFactorTeams) == 0 {
+		return false, nil
+	}
+
+	teams, err := srv.teamRepo.MemberTeams(ctx, memberID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, t := range teams {
+		if slices.Contains(srv.twoFactorTeams, t.Name) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Returns the unexpired session of the token, if it awaits one of the given
+// steps. The empty step allows valid sessions.
+func (srv AuthService) twoFactorSession(ctx context.Context, token auth.SessionToken, steps ...auth.PendingStep) (auth.Session, error) {
+	session, err := srv.authRepo.Session(ctx, token.SessionID())
+	if err != nil {
+		return auth.Session{}, err
+	}
+
+	if session.IsExpired() {
+		return auth.Session{}, auth.ErrInvalidSession
+	}
+
+	if !slices.Contains(steps, session.Pending) {
+		if session.IsPending() {
+			return auth.Session{}, auth.ErrTwoFactorPending
+		}
+
+		return auth.Session{}, auth.ErrInvalidSession
+	}
+
+	return session, nil
+}
+
+// Replaces the pending session with a valid session, so that the token of
+// the pending session cannot be used once the login is complete.
+func (srv AuthService) completeLogin(ctx context.Context, pending auth.Session) (auth.SessionToken, time.Time, error) {
+	if err := srv.authRepo.DeleteSession(ctx, pending.ID); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return srv.createSession(ctx, pending.MemberID, pending.Client)
+}
+
+// Records an invalid code entered for the session, revoking pending sessions
+// after MAX_TWO_FACTOR_ATTEMPTS invalid codes. Returns the error to report.
+func (srv AuthService) failTwoFactorAttempt(ctx context.Context, session auth.Session, codeErr error) error {
+	if !session.IsPending() || !errors.Is(codeErr, auth.ErrInvalidTwoFactorCode) {
+		return codeErr
+	}
+
+	attempts, err := srv.authRepo.AddSessionFailedAttempt(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+
+	if attempts < MAX_TWO_FACTOR_ATTEMPTS {
+		return codeErr
+	}
+
+	if err := srv.authRepo.DeleteSession(ctx, session.ID); err != nil {
+		return err
+	}
+
+	return auth.ErrTooManyTwoFactorAttempts
+}
+
+// Uses the two-factor code or recovery code. Codes of as many characters as
+// two-factor codes are taken as two-factor codes.
+func (srv AuthService) useCode(ctx context.Context, tf auth.TwoFactor, code string) error {
+	if len(strings.ReplaceAll(code, " ", "")) == auth.TOTP_DIGITS {
+		_, err := srv.useTOTPCode(ctx, tf, code)
+		return err
+	}
+
+	return srv.authRepo.UseRecoveryCode(ctx, tf.MemberID, auth.RecoveryCode(code).Hash())
+}
+
+// Verifies the two-factor code and records its period as used, so that the
+// code cannot be used again. Returns the period of the code.
+func (srv AuthService) useTOTPCode(ctx context.Context, tf auth.TwoFactor, code string) (int64, error) {
+	period, err := tf.Secret.Verify(code, time.Now(), tf.LastPeriod)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := srv.authRepo.UseTwoFactorPeriod(ctx, tf.MemberID, period); err != nil {
+		return 0, err
+	}
+
+	return period, nil
+}
+
+func (srv AuthService) newRecoveryCodes(ctx context.Context, memberID int64) ([]auth.RecoveryCode, error) {
+	codes, err := auth.NewRecoveryCodes(auth.RECOVERY_CODE_COUNT)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, code.Hash())
+	}
+
+	if err := srv.authRepo.SetRecoveryCodes(ctx, memberID, hashes...); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,182 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	// The maximum amount of URLs of a single sitemap, as per the sitemap
+	// protocol.
+	MAX_URLS = 50_000
+
+	namespace      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	imageNamespace = "http://www.google.com/schemas/sitemap-image/1.1"
+)
+
+var ErrInvalidPageSize = errors.New("Sitemap page size must be between 1 and 50000")
+
+// A sitemap, listing the pages of a site.
+type URLSet struct {
+	URLs []URL
+}
+
+type URL struct {
+	Loc string
+
+	// The time of the latest change to the page. Omitted if zero.
+	LastMod time.Time
+
+	// The images shown on the page, as per the image sitemap extension.
+	Images []Image
+}
+
+type Image struct {
+	Loc     string
+	Caption string
+
+	// The URL of the licence of the image, if any.
+	License string
+}
+
+// A sitemap index, listing the sitemaps of a site too large for a single
+// sitemap.
+type Index struct {
+	Sitemaps []Sitemap
+}
+
+type Sitemap struct {
+	Loc string
+
+	// The time of the latest change to any page of the sitemap. Omitted if
+	// zero.
+	LastMod time.Time
+}
+
+type xmlURLSet struct {
+	XMLName    xml.Name `xml:"urlset"`
+	XMLNs      string   `xml:"xmlns,attr"`
+	XMLNsImage string   `xml:"xmlns:image,attr"`
+	URLs       []xmlURL `xml:"url"`
+}
+
+type xmlURL struct {
+	Loc     string     `xml:"loc"`
+	LastMod string     `xml:"lastmod,omitempty"`
+	Images  []xmlImage `xml:"image:image"`
+}
+
+type xmlImage struct {
+	Loc     string `xml:"image:loc"`
+	Caption string `xml:"image:caption,omitempty"`
+	License string `xml:"image:license,omitempty"`
+}
+
+type xmlIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNs    string       `xml:"xmlns,attr"`
+	Sitemaps []xmlSitemap `xml:"sitemap"`
+}
+
+type xmlSitemap struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Splits the URLs into pages of at most the given size, each of which may be
+// encoded as a sitemap of a sitemap index. Returns a single empty page, if
+// there are no URLs.
+func Paginate(urls []URL, size int) ([]URLSet, error) {
+	if size <= 0 || size > MAX_URLS {
+		return nil, ErrInvalidPageSize
+	}
+
+	pages := make([]URLSet, 0)
+
+	for start := 0; start < len(urls); start += size {
+		end := min(start+size, len(urls))
+		pages = append(pages, URLSet{URLs: urls[start:end]})
+	}
+
+	if len(pages) == 0 {
+		pages = append(pages, URLSet{URLs: make([]URL, 0)})
+	}
+
+	return pages, nil
+}
+
+// Returns the latest modification time of the URLs of the sitemap, or the zero
+// time, if none have one.
+func (s URLSet) LastMod() time.Time {
+	var latest time.Time
+
+	for _, u := range s.URLs {
+		if u.LastMod.After(latest) {
+			latest = u.LastMod
+		}
+	}
+
+	return latest
+}
+
+// Encodes the sitemap as XML to the given writer.
+func (s URLSet) Encode(w io.Writer) error {
+	set := xmlURLSet{
+		XMLNs:      namespace,
+		XMLNsImage: imageNamespace,
+		URLs:       make([]xmlURL, 0),
+	}
+
+	for _, u := range s.URLs {
+		xu := xmlURL{Loc: u.Loc, LastMod: formatLastMod(u.LastMod)}
+
+		for _, img := range u.Images {
+			xu.Images = append(xu.Images, xmlImage(img))
+		}
+
+		set.URLs = append(set.URLs, xu)
+	}
+
+	return encode(w, set)
+}
+
+// Encodes the sitemap index as XML to the given writer.
+func (idx Index) Encode(w io.Writer) error {
+	index := xmlIndex{
+		XMLNs:    namespace,
+		Sitemaps: make([]xmlSitemap, 0),
+	}
+
+	for _, s := range idx.Sitemaps {
+		index.Sitemaps = append(index.Sitemaps, xmlSitemap{Loc: s.Loc, LastMod: formatLastMod(s.LastMod)})
+	}
+
+	return encode(w, index)
+}
+
+func encode(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// Formats the time in the W3C datetime format of the sitemap protocol,
+// returning an empty string for the zero time.
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,172 @@
+package sitemap_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/sitemap"
+)
+
+func TestPaginate(t *testing.T) {
+	type test struct {
+		count     int
+		size      int
+		wantSizes []int
+		wantErr   error
+	}
+
+	tests := map[string]test{
+		"No URLs":         {count: 0, size: 2, wantSizes: []int{0}},
+		"Single page":     {count: 2, size: 2, wantSizes: []int{2}},
+		"Partial page":    {count: 5, size: 2, wantSizes: []int{2, 2, 1}},
+		"Default size":    {count: 3, size: sitemap.MAX_URLS, wantSizes: []int{3}},
+		"Zero size":       {count: 3, size: 0, wantErr: sitemap.ErrInvalidPageSize},
+		"Above max URLs":  {count: 3, size: sitemap.MAX_URLS + 1, wantErr: sitemap.ErrInvalidPageSize},
+		"Exactly divided": {count: 4, size: 2, wantSizes: []int{2, 2}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			urls := make([]sitemap.URL, 0)
+			for i := range tt.count {
+				urls = append(urls, sitemap.URL{Loc: fmt.Sprintf("https://example.com/%d", i)})
+			}
+
+			pages, err := sitemap.Paginate(urls, tt.size)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if len(pages) != len(tt.wantSizes) {
+				t.Fatalf("got %d pages, want %d", len(pages), len(tt.wantSizes))
+			}
+
+			next := 0
+			for i, page := range pages {
+				if len(page.URLs) != tt.wantSizes[i] {
+					t.Fatalf("got %d URLs on page %d, want %d", len(page.URLs), i, tt.wantSizes[i])
+				}
+
+				// Pages must hold the URLs in their original order.
+				for _, u := range page.URLs {
+					if want := fmt.Sprintf("https://example.com/%d", next); u.Loc != want {
+						t.Fatalf("got %q, want %q", u.Loc, want)
+					}
+
+					next++
+				}
+			}
+		})
+	}
+}
+
+func TestLastMod(t *testing.T) {
+	earlier := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	type test struct {
+		urls []sitemap.URL
+		want time.Time
+	}
+
+	tests := map[string]test{
+		"No URLs":          {},
+		"Without lastmod":  {urls: []sitemap.URL{{Loc: "https://example.com/"}}},
+		"Latest of many":   {urls: []sitemap.URL{{LastMod: earlier}, {LastMod: later}, {}}, want: later},
+		"Single with time": {urls: []sitemap.URL{{LastMod: earlier}}, want: earlier},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := (sitemap.URLSet{URLs: tt.urls}).LastMod(); !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	lastMod := time.Date(2025, 6, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	type test struct {
+		encode  func(w io.Writer) error
+		want    []string
+		notWant []string
+	}
+
+	tests := map[string]test{
+		"URL set": {
+			encode: sitemap.URLSet{URLs: []sitemap.URL{
+				{
+					Loc:     "https://example.com/events/1",
+					LastMod: lastMod,
+					Images: []sitemap.Image{{
+						Loc:     "https://example.com/a.jpeg",
+						Caption: "Band & crowd",
+						License: "https://creativecommons.org/licenses/by/4.0/",
+					}},
+				},
+				{Loc: "https://example.com/about"},
+			}}.Encode,
+			want: []string{
+				`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">`,
+				"<loc>https://example.com/events/1</loc>",
+				"<lastmod>2025-06-01T12:00:00Z</lastmod>",
+				"<image:loc>https://example.com/a.jpeg</image:loc>",
+				"<image:caption>Band &amp; crowd</image:caption>",
+				"<image:license>https://creativecommons.org/licenses/by/4.0/</image:license>",
+				"<loc>https://example.com/about</loc>",
+			},
+		},
+		"URL without lastmod or images": {
+			encode:  sitemap.URLSet{URLs: []sitemap.URL{{Loc: "https://example.com/"}}}.Encode,
+			want:    []string{"<loc>https://example.com/</loc>"},
+			notWant: []string{"<lastmod>", "<image:image>"},
+		},
+		"Index": {
+			encode: sitemap.Index{Sitemaps: []sitemap.Sitemap{
+				{Loc: "https://example.com/sitemap/1", LastMod: lastMod},
+				{Loc: "https://example.com/sitemap/2"},
+			}}.Encode,
+			want: []string{
+				`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+				"<loc>https://example.com/sitemap/1</loc>",
+				"<lastmod>2025-06-01T12:00:00Z</lastmod>",
+				"<loc>https://example.com/sitemap/2</loc>",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.encode(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			out := buf.String()
+
+			if !strings.HasPrefix(out, xml.Header) {
+				t.Fatalf("got %q, want XML header", out)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Fatalf("got %s, want it to contain %q", out, want)
+				}
+			}
+
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out, notWant) {
+					t.Fatalf("got %s, want it not to contain %q", out, notWant)
+				}
+			}
+		})
+	}
+}
@@ -3,18 +3,31 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattismoel/konnekt/internal/domain/artist"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/search"
 	"github.com/mattismoel/konnekt/internal/query"
 )
 
 type Artist struct {
-	ID          int64
-	Name        string
-	Description string
-	PreviewURL  string
-	ImageURL    string
+	ID            int64
+	Name          string
+	Description   string
+	PreviewURL    string
+	ImageURL      string
+	ImageVariants ImageVariants
+	ImageBlurHash string
+	ImageColor    string
+	ImageFraming  ImageFraming
+	ImageCrops    ImageCrops
+	ImageMediaID  MediaID
+	ImageAltText  string
+	ImageCredit   string
+	ImageLicense  string
+	UpdatedAt     time.Time
 }
 
 func (a Artist) ToInternal(genres []artist.Genre, socials []artist.Social) artist.Artist {
@@ -23,9 +36,23 @@ func (a Artist) ToInternal(genres []artist.Genre, socials []artist.Social) artis
 		Name:        a.Name,
 		Description: a.Description,
 		PreviewURL:  a.PreviewURL,
-		ImageURL:    a.ImageURL,
-		Genres:      genres,
-		Socials:     socials,
+		Image: media.Image{
+			URL:      a.ImageURL,
+			Variants: a.ImageVariants,
+			BlurHash: a.ImageBlurHash,
+			Color:    a.ImageColor,
+			Framing:  media.Framing(a.ImageFraming),
+			Crops:    a.ImageCrops,
+			Attribution: media.Attribution{
+				AltText: a.ImageAltText,
+				Credit:  a.ImageCredit,
+				License: a.ImageLicense,
+			},
+			MediaID: int64(a.ImageMediaID),
+		},
+		Genres:    genres,
+		Socials:   socials,
+		UpdatedAt: a.UpdatedAt,
 	}
 }
 
@@ -56,6 +83,7 @@ func (repo ArtistRepository) List(ctx context.Context, q query.ListQuery) (query
 		Limit:   q.Limit,
 		OrderBy: q.OrderBy,
 		Filters: q.Filters,
+		Search:  q.Search,
 	})
 	if err != nil {
 		return query.ListResult[artist.Artist]{}, err
@@ -113,10 +141,19 @@ func (repo ArtistRepository) Insert(ctx context.Context, a artist.Artist) (int64
 	}
 
 	artistID, err := insertArtist(ctx, tx, Artist{
-		Name:        a.Name,
-		Description: a.Description,
-		PreviewURL:  a.PreviewURL,
-		ImageURL:    a.ImageURL,
+		Name:          a.Name,
+		Description:   a.Description,
+		PreviewURL:    a.PreviewURL,
+		ImageURL:      a.Image.URL,
+		ImageVariants: a.Image.Variants,
+		ImageBlurHash: a.Image.BlurHash,
+		ImageColor:    a.Image.Color,
+		ImageFraming:  ImageFraming(a.Image.Framing),
+		ImageCrops:    a.Image.Crops,
+		ImageMediaID:  MediaID(a.Image.MediaID),
+		ImageAltText:  a.Image.AltText,
+		ImageCredit:   a.Image.Credit,
+		ImageLicense:  a.Image.License,
 	})
 
 	if err != nil {
@@ -147,6 +184,10 @@ func (repo ArtistRepository) Insert(ctx context.Context, a artist.Artist) (int64
 		}
 	}
 
+	if err := indexSearch(ctx, tx, search.KindArtist, artistID); err != nil {
+		return 0, err
+	}
+
 	defer tx.Rollback()
 
 	if err := tx.Commit(); err != nil {
@@ -165,10 +206,19 @@ func (repo ArtistRepository) Update(ctx context.Context, artistID int64, a artis
 	defer tx.Rollback()
 
 	err = updateArtist(ctx, tx, artistID, Artist{
-		Name:        a.Name,
-		Description: a.Description,
-		PreviewURL:  a.PreviewURL,
-		ImageURL:    a.ImageURL,
+		Name:          a.Name,
+		Description:   a.Description,
+		PreviewURL:    a.PreviewURL,
+		ImageURL:      a.Image.URL,
+		ImageVariants: a.Image.Variants,
+		ImageBlurHash: a.Image.BlurHash,
+		ImageColor:    a.Image.Color,
+		ImageFraming:  ImageFraming(a.Image.Framing),
+		ImageCrops:    a.Image.Crops,
+		ImageMediaID:  MediaID(a.Image.MediaID),
+		ImageAltText:  a.Image.AltText,
+		ImageCredit:   a.Image.Credit,
+		ImageLicense:  a.Image.License,
 	})
 
 	if err != nil {
@@ -195,6 +245,10 @@ func (repo ArtistRepository) Update(ctx context.Context, artistID int64, a artis
 		return err
 	}
 
+	if err := indexSearch(ctx, tx, search.KindArtist, artistID); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -202,7 +256,7 @@ func (repo ArtistRepository) Update(ctx context.Context, artistID int64, a artis
 	return nil
 }
 
-func (repo ArtistRepository) SetImageURL(ctx context.Context, artistID int64, url string) error {
+func (repo ArtistRepository) SetImage(ctx context.Context, artistID int64, img media.Image) error {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -210,7 +264,7 @@ func (repo ArtistRepository) SetImageURL(ctx context.Context, artistID int64, ur
 
 	defer tx.Rollback()
 
-	err = setArtistImageURL(ctx, tx, artistID, url)
+	err = setArtistImage(ctx, tx, artistID, img)
 	if err != nil {
 		return err
 	}
@@ -295,6 +349,10 @@ func (repo ArtistRepository) Delete(ctx context.Context, artistID int64) error {
 		return err
 	}
 
+	if err := unindexSearch(ctx, tx, search.KindArtist, artist.ID); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -309,6 +367,16 @@ func scanArtist(scanner Scanner, dst *Artist) error {
 		&dst.Description,
 		&dst.PreviewURL,
 		&dst.ImageURL,
+		&dst.ImageVariants,
+		&dst.ImageBlurHash,
+		&dst.ImageColor,
+		&dst.ImageFraming,
+		&dst.ImageCrops,
+		&dst.ImageMediaID,
+		&dst.ImageAltText,
+		&dst.ImageCredit,
+		&dst.ImageLicense,
+		&dst.UpdatedAt,
 	)
 
 	if err != nil {
@@ -325,12 +393,23 @@ var artistBuilder = sq.
 		"artist.description",
 		"artist.preview_url",
 		"artist.image_url",
+		"artist.image_variants",
+		"artist.image_blur_hash",
+		"artist.image_color",
+		"artist.image_framing",
+		"artist.image_crops",
+		"artist.image_media_id",
+		"artist.image_alt_text",
+		"artist.image_credit",
+		"artist.image_license",
+		"artist.updated_at",
 	).
 	From("artist")
 
 func listArtists(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Artist, error) {
 	builder := artistBuilder
 
+	builder = withSearch(builder, search.KindArtist, params.Search)
 	builder = withPagination(builder, params)
 	builder = withOrdering(builder, params.OrderBy, "name", "artist")
 
@@ -364,8 +443,38 @@ func listArtists(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Artist,
 func insertArtist(ctx context.Context, tx *sql.Tx, a Artist) (int64, error) {
 	query, args, err := sq.
 		Insert("artist").
-		Columns("name", "description", "preview_url", "image_url").
-		Values(a.Name, a.Description, a.PreviewURL, a.ImageURL).
+		Columns(
+			"name",
+			"description",
+			"preview_url",
+			"image_url",
+			"image_variants",
+			"image_blur_hash",
+			"image_color",
+			"image_framing",
+			"image_crops",
+			"image_media_id",
+			"image_alt_text",
+			"image_credit",
+			"image_license",
+			"updated_at",
+		).
+		Values(
+			a.Name,
+			a.Description,
+			a.PreviewURL,
+			a.ImageURL,
+			a.ImageVariants,
+			a.ImageBlurHash,
+			a.ImageColor,
+			a.ImageFraming,
+			a.ImageCrops,
+			a.ImageMediaID,
+			a.ImageAltText,
+			a.ImageCredit,
+			a.ImageLicense,
+			formatTime(time.Now()),
+		).
 		ToSql()
 
 	res, err := tx.ExecContext(ctx, query, args...)
@@ -433,11 +542,21 @@ func deleteArtist(ctx context.Context, tx *sql.Tx, artistID int64) error {
 	return nil
 }
 
-func setArtistImageURL(ctx context.Context, tx *sql.Tx, artistID int64, url string) error {
+func setArtistImage(ctx context.Context, tx *sql.Tx, artistID int64, img media.Image) error {
 	query, args, err := sq.
 		Update("artist").
 		Where(sq.Eq{"id": artistID}).
-		Set("image_url", url).
+		Set("image_url", img.URL).
+		Set("image_variants", ImageVariants(img.Variants)).
+		Set("image_blur_hash", img.BlurHash).
+		Set("image_color", img.Color).
+		Set("image_framing", ImageFraming(img.Framing)).
+		Set("image_crops", ImageCrops(img.Crops)).
+		Set("image_media_id", MediaID(img.MediaID)).
+		Set("image_alt_text", img.AltText).
+		Set("image_credit", img.Credit).
+		Set("image_license", img.License).
+		Set("updated_at", formatTime(time.Now())).
 		ToSql()
 
 	if err != nil {
@@ -453,7 +572,10 @@ func setArtistImageURL(ctx context.Context, tx *sql.Tx, artistID int64, url stri
 }
 
 func updateArtist(ctx context.Context, tx *sql.Tx, artistID int64, a Artist) error {
-	builder := sq.Update("artist").Where(sq.Eq{"id": artistID})
+	builder := sq.
+		Update("artist").
+		Where(sq.Eq{"id": artistID}).
+		Set("updated_at", formatTime(time.Now()))
 
 	if a.Name != "" {
 		builder = builder.Set("name", a.Name)
@@ -469,6 +591,15 @@ func updateArtist(ctx context.Context, tx *sql.Tx, artistID int64, a Artist) err
 
 	if a.ImageURL != "" {
 		builder = builder.Set("image_url", a.ImageURL)
+		builder = builder.Set("image_variants", a.ImageVariants)
+		builder = builder.Set("image_blur_hash", a.ImageBlurHash)
+		builder = builder.Set("image_color", a.ImageColor)
+		builder = builder.Set("image_framing", a.ImageFraming)
+		builder = builder.Set("image_crops", a.ImageCrops)
+		builder = builder.Set("image_media_id", a.ImageMediaID)
+		builder = builder.Set("image_alt_text", a.ImageAltText)
+		builder = builder.Set("image_credit", a.ImageCredit)
+		builder = builder.Set("image_license", a.ImageLicense)
 	}
 
 	query, args, err := builder.ToSql()
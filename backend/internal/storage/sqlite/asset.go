@@ -0,0 +1,580 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+type Asset struct {
+	ID            int64
+	Title         string
+	AltText       string
+	Credit        string
+	Width         int
+	Height        int
+	ImageURL      string
+	ImageVariants ImageVariants
+	ImageBlurHash string
+	ImageColor    string
+	CreatedAt     time.Time
+}
+
+func AssetFromInternal(a media.Asset) Asset {
+	return Asset{
+		ID:            a.ID,
+		Title:         a.Title,
+		AltText:       a.AltText,
+		Credit:        a.Credit,
+		Width:         a.Width,
+		Height:        a.Height,
+		ImageURL:      a.Image.URL,
+		ImageVariants: a.Image.Variants,
+		ImageBlurHash: a.Image.BlurHash,
+		ImageColor:    a.Image.Color,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+func (a Asset) ToInternal(tags []string) media.Asset {
+	return media.Asset{
+		ID:      a.ID,
+		Title:   a.Title,
+		AltText: a.AltText,
+		Credit:  a.Credit,
+		Tags:    tags,
+		Width:   a.Width,
+		Height:  a.Height,
+		Image: media.Image{
+			URL:      a.ImageURL,
+			Variants: a.ImageVariants,
+			BlurHash: a.ImageBlurHash,
+			Color:    a.ImageColor,
+		},
+		CreatedAt: a.CreatedAt.UTC(),
+	}
+}
+
+var _ media.Repository = (*MediaRepository)(nil)
+
+type MediaRepository struct {
+	db *sql.DB
+}
+
+func NewMediaRepository(db *sql.DB) (*MediaRepository, error) {
+	return &MediaRepository{
+		db: db,
+	}, nil
+}
+
+func (repo MediaRepository) List(ctx context.Context, q query.ListQuery) (query.ListResult[media.Asset], error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return query.ListResult[media.Asset]{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbAssets, totalCount, err := listAssets(ctx, tx, q)
+	if err != nil {
+		return query.ListResult[media.Asset]{}, err
+	}
+
+	records := make([]media.Asset, 0)
+	for _, a := range dbAssets {
+		tags, err := assetTags(ctx, tx, a.ID)
+		if err != nil {
+			return query.ListResult[media.Asset]{}, err
+		}
+
+		records = append(records, a.ToInternal(tags))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return query.ListResult[media.Asset]{}, err
+	}
+
+	return query.ListResult[media.Asset]{
+		Page:       q.Page,
+		PerPage:    q.PerPage,
+		TotalCount: totalCount,
+		PageCount:  q.PageCount(totalCount),
+		Records:    records,
+	}, nil
+}
+
+func (repo MediaRepository) ByID(ctx context.Context, assetID int64) (media.Asset, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbAsset, err := assetByID(ctx, tx, assetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return media.Asset{}, media.ErrAssetNoExist
+		}
+
+		return media.Asset{}, err
+	}
+
+	tags, err := assetTags(ctx, tx, assetID)
+	if err != nil {
+		return media.Asset{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return media.Asset{}, err
+	}
+
+	return dbAsset.ToInternal(tags), nil
+}
+
+func (repo MediaRepository) Insert(ctx context.Context, a media.Asset) (int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	assetID, err := insertAsset(ctx, tx, AssetFromInternal(a))
+	if err != nil {
+		return 0, err
+	}
+
+	if err := setAssetTags(ctx, tx, assetID, a.Tags); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return assetID, nil
+}
+
+// Updates the title, alt text, credit and tags of the asset. The image of an
+// asset never changes, as it is shared by the entities using it.
+func (repo MediaRepository) Update(ctx context.Context, assetID int64, a media.Asset) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = updateAsset(ctx, tx, assetID, AssetFromInternal(a))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return media.ErrAssetNoExist
+		}
+
+		return err
+	}
+
+	if err := setAssetTags(ctx, tx, assetID, a.Tags); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo MediaRepository) Delete(ctx context.Context, assetID int64) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// The reference check is repeated within the transaction, so that an
+	// asset cannot be put to use while being deleted.
+	refs, err := assetReferences(ctx, tx, assetID)
+	if err != nil {
+		return err
+	}
+
+	if len(refs) > 0 {
+		return media.ErrAssetInUse
+	}
+
+	if err := setAssetTags(ctx, tx, assetID, nil); err != nil {
+		return err
+	}
+
+	if err := deleteAsset(ctx, tx, assetID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo MediaRepository) References(ctx context.Context, assetID int64) ([]media.Reference, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	refs, err := assetReferences(ctx, tx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+var assetBuilder = sq.
+	Select(
+		"media.id",
+		"media.title",
+		"media.alt_text",
+		"media.credit",
+		"media.width",
+		"media.height",
+		"media.image_url",
+		"media.image_variants",
+		"media.image_blur_hash",
+		"media.image_color",
+		"media.created_at",
+	).
+	From("media")
+
+func scanAsset(s Scanner, dst *Asset) error {
+	err := s.Scan(
+		&dst.ID,
+		&dst.Title,
+		&dst.AltText,
+		&dst.Credit,
+		&dst.Width,
+		&dst.Height,
+		&dst.ImageURL,
+		&dst.ImageVariants,
+		&dst.ImageBlurHash,
+		&dst.ImageColor,
+		&dst.CreatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns the given page of assets, along with the total count of assets
+// matching the query. Assets are listed newest first, unless ordered
+// otherwise.
+func listAssets(ctx context.Context, tx *sql.Tx, q query.ListQuery) ([]Asset, int, error) {
+	where := sq.And{}
+
+	for key, filters := range q.Filters {
+		for _, f := range filters {
+			switch key {
+			case "tag":
+				where = append(where, sq.Expr(
+					"EXISTS (SELECT 1 FROM media_tag WHERE media_tag.media_id = media.id AND media_tag.tag = ?)",
+					strings.ToLower(f.Value),
+				))
+			case "title":
+				where = append(where, contains("media.title", f.Value))
+			}
+		}
+	}
+
+	for _, word := range strings.Fields(q.Search) {
+		where = append(where, sq.Or{
+			contains("media.title", word),
+			contains("media.alt_text", word),
+			contains("media.credit", word),
+		})
+	}
+
+	countQuery, countArgs, err := sq.Select("COUNT(*)").From("media").Where(where).ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int
+	if err := tx.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	builder := assetBuilder.Where(where)
+	builder = withOrdering(builder, q.OrderBy, "title", "media")
+	builder = withOrdering(builder, q.OrderBy, "created_at", "media")
+	builder = builder.OrderBy("media.created_at DESC", "media.id DESC")
+
+	if q.PerPage > 0 {
+		builder = builder.Limit(uint64(q.PerPage)).Offset(uint64(max(q.Offset(), 0)))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	assets := make([]Asset, 0)
+	for rows.Next() {
+		var a Asset
+		if err := scanAsset(rows, &a); err != nil {
+			return nil, 0, err
+		}
+
+		assets = append(assets, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return assets, totalCount, nil
+}
+
+func assetByID(ctx context.Context, tx *sql.Tx, assetID int64) (Asset, error) {
+	query, args, err := assetBuilder.
+		Where(sq.Eq{"media.id": assetID}).
+		ToSql()
+
+	if err != nil {
+		return Asset{}, err
+	}
+
+	var a Asset
+	if err := scanAsset(tx.QueryRowContext(ctx, query, args...), &a); err != nil {
+		return Asset{}, err
+	}
+
+	return a, nil
+}
+
+func insertAsset(ctx context.Context, tx *sql.Tx, a Asset) (int64, error) {
+	query, args, err := sq.
+		Insert("media").
+		Columns(
+			"title",
+			"alt_text",
+			"credit",
+			"width",
+			"height",
+			"image_url",
+			"image_variants",
+			"image_blur_hash",
+			"image_color",
+			"created_at",
+		).
+		Values(
+			a.Title,
+			a.AltText,
+			a.Credit,
+			a.Width,
+			a.Height,
+			a.ImageURL,
+			a.ImageVariants,
+			a.ImageBlurHash,
+			a.ImageColor,
+			formatTime(a.CreatedAt),
+		).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	assetID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return assetID, nil
+}
+
+func updateAsset(ctx context.Context, tx *sql.Tx, assetID int64, a Asset) error {
+	query, args, err := sq.
+		Update("media").
+		Set("title", a.Title).
+		Set("alt_text", a.AltText).
+		Set("credit", a.Credit).
+		Where(sq.Eq{"id": assetID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected <= 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func deleteAsset(ctx context.Context, tx *sql.Tx, assetID int64) error {
+	query, args, err := sq.
+		Delete("media").
+		Where(sq.Eq{"id": assetID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func assetTags(ctx context.Context, tx *sql.Tx, assetID int64) ([]string, error) {
+	query, args, err := sq.
+		Select("tag").
+		From("media_tag").
+		Where(sq.Eq{"media_id": assetID}).
+		OrderBy("tag").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tags := make([]string, 0)
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Replaces the tags of the given asset.
+func setAssetTags(ctx context.Context, tx *sql.Tx, assetID int64, tags []string) error {
+	query, args, err := sq.
+		Delete("media_tag").
+		Where(sq.Eq{"media_id": assetID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	if len(tags) <= 0 {
+		return nil
+	}
+
+	builder := sq.Insert("media_tag").Columns("media_id", "tag")
+	for _, tag := range tags {
+		builder = builder.Values(assetID, tag)
+	}
+
+	query, args, err = builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns the artists, events and landing images using the given asset.
+func assetReferences(ctx context.Context, tx *sql.Tx, assetID int64) ([]media.Reference, error) {
+	query, args, err := sq.
+		Select("'artist'", "id", "name").From("artist").Where(sq.Eq{"image_media_id": assetID}).
+		Suffix("UNION ALL SELECT 'event', id, title FROM event WHERE image_media_id = ?", assetID).
+		Suffix("UNION ALL SELECT 'landing image', id, '' FROM landing_image WHERE media_id = ?", assetID).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	refs := make([]media.Reference, 0)
+	for rows.Next() {
+		var ref media.Reference
+		if err := rows.Scan(&ref.Kind, &ref.ID, &ref.Name); err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, ref)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
@@ -12,6 +12,19 @@ import (
 )
 
 type Session struct {
+	ID         string
+	MemberID   int64
+	ExpiresAt  time.Time
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+
+	Pending        string
+	FailedAttempts int
+}
+
+type PasswordReset struct {
 	ID        string
 	MemberID  int64
 	ExpiresAt time.Time
@@ -46,13 +59,7 @@ func (repo AuthRepository) InsertSession(ctx context.Context, session auth.Sessi
 
 	defer tx.Rollback()
 
-	dbSession := Session{
-		ID:        string(session.ID),
-		MemberID:  session.MemberID,
-		ExpiresAt: session.ExpiresAt,
-	}
-
-	if err := insertSession(ctx, tx, dbSession); err != nil {
+	if err := insertSession(ctx, tx, SessionFromInternal(session)); err != nil {
 		return err
 	}
 
@@ -65,6 +72,10 @@ func (repo AuthRepository) InsertSession(ctx context.Context, session auth.Sessi
 
 func (repo AuthRepository) Session(ctx context.Context, sessionID auth.SessionID) (auth.Session, error) {
 	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return auth.Session{}, err
+	}
+
 	defer tx.Rollback()
 
 	dbSession, err := sessionByID(ctx, tx, string(sessionID))
@@ -72,6 +83,8 @@ func (repo AuthRepository) Session(ctx context.Context, sessionID auth.SessionID
 		if errors.Is(err, sql.ErrNoRows) {
 			return auth.Session{}, auth.ErrNoSession
 		}
+
+		return auth.Session{}, err
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -101,7 +114,80 @@ func (repo AuthRepository) SetSessionExpiry(ctx context.Context, sessionID auth.
 	return nil
 }
 
-func (repo AuthRepository) DeleteMemberSession(ctx context.Context, memberID int64) error {
+func (repo AuthRepository) MemberSessions(ctx context.Context, memberID int64) ([]auth.Session, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	dbSessions, err := listSessions(ctx, tx, sq.Eq{"member_id": memberID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]auth.Session, 0)
+	for _, dbSession := range dbSessions {
+		sessions = append(sessions, dbSession.ToInternal())
+	}
+
+	return sessions, nil
+}
+
+func (repo AuthRepository) SetSessionLastSeen(ctx context.Context, sessionID auth.SessionID, lastSeen time.Time) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = setSessionLastSeen(ctx, tx, string(sessionID), lastSeen)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo AuthRepository) AddSessionFailedAttempt(ctx context.Context, sessionID auth.SessionID) (int, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	if err := addSessionFailedAttempt(ctx, tx, string(sessionID)); err != nil {
+		return 0, err
+	}
+
+	dbSession, err := sessionByID(ctx, tx, string(sessionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, auth.ErrNoSession
+		}
+
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return dbSession.FailedAttempts, nil
+}
+
+func (repo AuthRepository) DeleteSession(ctx context.Context, sessionID auth.SessionID) error {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -109,7 +195,7 @@ func (repo AuthRepository) DeleteMemberSession(ctx context.Context, memberID int
 
 	defer tx.Rollback()
 
-	if err := deleteMemberSession(ctx, tx, memberID); err != nil {
+	if err := deleteSessions(ctx, tx, sq.Eq{"id": string(sessionID)}); err != nil {
 		return err
 	}
 
@@ -118,7 +204,138 @@ func (repo AuthRepository) DeleteMemberSession(ctx context.Context, memberID int
 	}
 
 	return nil
+}
+
+func (repo AuthRepository) DeleteMemberSessions(ctx context.Context, memberID int64, except ...auth.SessionID) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	exceptIDs := make([]string, 0)
+	for _, id := range except {
+		exceptIDs = append(exceptIDs, string(id))
+	}
+
+	err = deleteSessions(ctx, tx, sq.And{
+		sq.Eq{"member_id": memberID},
+		sq.NotEq{"id": exceptIDs},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Expiry times have not always been stored in a comparable format, so expired
+// sessions are found by their scanned expiry rather than by the query.
+func (repo AuthRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	dbSessions, err := listSessions(ctx, tx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	expiredIDs := make([]string, 0)
+	for _, dbSession := range dbSessions {
+		if !dbSession.ExpiresAt.After(now) {
+			expiredIDs = append(expiredIDs, dbSession.ID)
+		}
+	}
+
+	if len(expiredIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := deleteSessions(ctx, tx, sq.Eq{"id": expiredIDs}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int64(len(expiredIDs)), nil
+}
+
+func (repo AuthRepository) InsertPasswordReset(ctx context.Context, r auth.PasswordReset) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := insertPasswordReset(ctx, tx, PasswordResetFromInternal(r)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo AuthRepository) ConsumePasswordReset(ctx context.Context, resetID auth.ResetID) (auth.PasswordReset, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return auth.PasswordReset{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbReset, err := passwordResetByID(ctx, tx, string(resetID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.PasswordReset{}, auth.ErrInvalidResetToken
+		}
+
+		return auth.PasswordReset{}, err
+	}
+
+	if err := deletePasswordResets(ctx, tx, sq.Eq{"id": dbReset.ID}); err != nil {
+		return auth.PasswordReset{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return auth.PasswordReset{}, err
+	}
+
+	return dbReset.ToInternal(), nil
+}
+
+func (repo AuthRepository) DeleteMemberPasswordResets(ctx context.Context, memberID int64) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := deletePasswordResets(ctx, tx, sq.Eq{"member_id": memberID}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
 
+	return nil
 }
 
 func (repo AuthRepository) ListPermissions(ctx context.Context, q query.ListQuery) (query.ListResult[auth.Permission], error) {
@@ -195,10 +412,44 @@ var sessionBuilder = sq.
 		"session.id",
 		"session.member_id",
 		"session.expires_at",
+		"session.user_agent",
+		"session.ip_address",
+		"session.created_at",
+		"session.last_seen_at",
+		"session.pending",
+		"session.failed_attempts",
 	).
 	From("session")
 
 func scanSession(s Scanner, dst *Session) error {
+	err := s.Scan(
+		&dst.ID,
+		&dst.MemberID,
+		&dst.ExpiresAt,
+		&dst.UserAgent,
+		&dst.IPAddress,
+		&dst.CreatedAt,
+		&dst.LastSeenAt,
+		&dst.Pending,
+		&dst.FailedAttempts,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var passwordResetBuilder = sq.
+	Select(
+		"password_reset.id",
+		"password_reset.member_id",
+		"password_reset.expires_at",
+	).
+	From("password_reset")
+
+func scanPasswordReset(s Scanner, dst *PasswordReset) error {
 	err := s.Scan(&dst.ID, &dst.MemberID, &dst.ExpiresAt)
 	if err != nil {
 		return err
@@ -225,10 +476,47 @@ func scanPermission(s Scanner, dst *Permission) error {
 	return nil
 }
 
-func deleteMemberSession(ctx context.Context, tx *sql.Tx, memberID int64) error {
+func listSessions(ctx context.Context, tx *sql.Tx, where sq.Sqlizer) ([]Session, error) {
+	builder := sessionBuilder.OrderBy("session.last_seen_at DESC")
+
+	if where != nil {
+		builder = builder.Where(where)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	sessions := make([]Session, 0)
+
+	for rows.Next() {
+		var s Session
+		if err := scanSession(rows, &s); err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func deleteSessions(ctx context.Context, tx *sql.Tx, where sq.Sqlizer) error {
 	query, args, err := sq.
 		Delete("session").
-		Where(sq.Eq{"member_id": memberID}).
+		Where(where).
 		ToSql()
 
 	if err != nil {
@@ -246,8 +534,26 @@ func deleteMemberSession(ctx context.Context, tx *sql.Tx, memberID int64) error
 func insertSession(ctx context.Context, tx *sql.Tx, session Session) error {
 	query, args, err := sq.
 		Insert("session").
-		Columns("id", "member_id", "expires_at").
-		Values(session.ID, session.MemberID, session.ExpiresAt).
+		Columns(
+			"id",
+			"member_id",
+			"expires_at",
+			"user_agent",
+			"ip_address",
+			"created_at",
+			"last_seen_at",
+			"pending",
+		).
+		Values(
+			session.ID,
+			session.MemberID,
+			formatTime(session.ExpiresAt),
+			session.UserAgent,
+			session.IPAddress,
+			formatTime(session.CreatedAt),
+			formatTime(session.LastSeenAt),
+			session.Pending,
+		).
 		ToSql()
 
 	if err != nil {
@@ -284,7 +590,7 @@ func setSessionExpiry(ctx context.Context, tx *sql.Tx, sessionID string, newExpi
 	query, args, err := sq.
 		Update("session").
 		Where(sq.Eq{"id": sessionID}).
-		Set("expires_at", newExpiry).
+		Set("expires_at", formatTime(newExpiry)).
 		ToSql()
 
 	if err != nil {
@@ -304,11 +610,144 @@ func setSessionExpiry(ctx context.Context, tx *sql.Tx, sessionID string, newExpi
 	return nil
 }
 
+func addSessionFailedAttempt(ctx context.Context, tx *sql.Tx, sessionID string) error {
+	query, args, err := sq.
+		Update("session").
+		Where(sq.Eq{"id": sessionID}).
+		Set("failed_attempts", sq.Expr("failed_attempts + 1")).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func setSessionLastSeen(ctx context.Context, tx *sql.Tx, sessionID string, lastSeen time.Time) error {
+	query, args, err := sq.
+		Update("session").
+		Where(sq.Eq{"id": sessionID}).
+		Set("last_seen_at", formatTime(lastSeen)).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func insertPasswordReset(ctx context.Context, tx *sql.Tx, r PasswordReset) error {
+	query, args, err := sq.
+		Insert("password_reset").
+		Columns("id", "member_id", "expires_at").
+		Values(r.ID, r.MemberID, formatTime(r.ExpiresAt)).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func passwordResetByID(ctx context.Context, tx *sql.Tx, resetID string) (PasswordReset, error) {
+	query, args, err := passwordResetBuilder.
+		Where(sq.Eq{"id": resetID}).
+		ToSql()
+
+	if err != nil {
+		return PasswordReset{}, err
+	}
+
+	var r PasswordReset
+	row := tx.QueryRowContext(ctx, query, args...)
+	if err := scanPasswordReset(row, &r); err != nil {
+		return PasswordReset{}, err
+	}
+
+	return r, nil
+}
+
+func deletePasswordResets(ctx context.Context, tx *sql.Tx, where sq.Sqlizer) error {
+	query, args, err := sq.
+		Delete("password_reset").
+		Where(where).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func PasswordResetFromInternal(r auth.PasswordReset) PasswordReset {
+	return PasswordReset{
+		ID:        string(r.ID),
+		MemberID:  r.MemberID,
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+func (r PasswordReset) ToInternal() auth.PasswordReset {
+	return auth.PasswordReset{
+		ID:        auth.ResetID(r.ID),
+		MemberID:  r.MemberID,
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+func SessionFromInternal(s auth.Session) Session {
+	return Session{
+		ID:         string(s.ID),
+		MemberID:   s.MemberID,
+		ExpiresAt:  s.ExpiresAt,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+
+		Pending:        string(s.Pending),
+		FailedAttempts: s.FailedAttempts,
+	}
+}
+
 func (s Session) ToInternal() auth.Session {
 	return auth.Session{
 		ID:        auth.SessionID(s.ID),
 		MemberID:  s.MemberID,
 		ExpiresAt: s.ExpiresAt,
+		Client: auth.Client{
+			UserAgent: s.UserAgent,
+			IPAddress: s.IPAddress,
+		},
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+
+		Pending:        auth.PendingStep(s.Pending),
+		FailedAttempts: s.FailedAttempts,
 	}
 }
 
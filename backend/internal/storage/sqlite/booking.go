@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/event"
+)
+
+func (repo EventRepository) Bookings(ctx context.Context, q event.BookingQuery) ([]event.Booking, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	bookings, err := listBookings(ctx, tx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return bookings, nil
+}
+
+var bookingBuilder = sq.
+	Select(
+		"concert.id",
+		"concert.event_id",
+		"event.title",
+		"event.venue_id",
+		"concert.artist_id",
+		"concert.from_date",
+		"concert.to_date",
+	).
+	From("concert").
+	Join("event ON event.id = concert.event_id")
+
+func listBookings(ctx context.Context, tx *sql.Tx, q event.BookingQuery) ([]event.Booking, error) {
+	// Times are stored as RFC 3339 strings, so the range check is a plain
+	// string comparison, which is able to use the concert time index.
+	bookingStatuses := make([]string, 0)
+	for _, status := range event.BookingStatuses() {
+		bookingStatuses = append(bookingStatuses, string(status))
+	}
+
+	builder := bookingBuilder.
+		Where(sq.Eq{"event.status": bookingStatuses}).
+		Where(sq.Lt{"concert.from_date": formatTime(q.To)}).
+		Where(sq.Gt{"concert.to_date": formatTime(q.From)}).
+		OrderBy("concert.from_date ASC")
+
+	scope := sq.Or{}
+
+	if len(q.ArtistIDs) > 0 {
+		scope = append(scope, sq.Eq{"concert.artist_id": q.ArtistIDs})
+	}
+
+	if q.VenueID > 0 {
+		scope = append(scope, sq.Eq{"event.venue_id": q.VenueID})
+	}
+
+	if len(scope) > 0 {
+		builder = builder.Where(scope)
+	}
+
+	if q.ExcludeEventID > 0 {
+		builder = builder.Where(sq.NotEq{"concert.event_id": q.ExcludeEventID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	bookings := make([]event.Booking, 0)
+	for rows.Next() {
+		var b event.Booking
+
+		err := rows.Scan(
+			&b.ConcertID,
+			&b.EventID,
+			&b.EventTitle,
+			&b.VenueID,
+			&b.ArtistID,
+			&b.From,
+			&b.To,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		bookings = append(bookings, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return bookings, nil
+}
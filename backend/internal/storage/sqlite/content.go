@@ -6,13 +6,21 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattismoel/konnekt/internal/domain/content"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 )
 
 var _ content.Repository = (*ContentRepository)(nil)
 
 type Image struct {
-	ID  int64
-	URL string
+	ID       int64
+	URL      string
+	Variants ImageVariants
+	BlurHash string
+	Color    string
+	MediaID  MediaID
+	AltText  string
+	Credit   string
+	License  string
 }
 
 type ImageCollection = []Image
@@ -28,7 +36,7 @@ func NewContentRepository(db *sql.DB) (*ContentRepository, error) {
 }
 
 // InsertLandingImage implements content.Repository.
-func (r *ContentRepository) InsertLandingImage(ctx context.Context, url string) (int64, error) {
+func (r *ContentRepository) InsertLandingImage(ctx context.Context, img media.Image) (int64, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
@@ -36,7 +44,7 @@ func (r *ContentRepository) InsertLandingImage(ctx context.Context, url string)
 
 	defer tx.Rollback()
 
-	id, err := insertLandingImage(ctx, tx, url)
+	id, err := insertLandingImage(ctx, tx, ImageFromInternal(img))
 	if err != nil {
 		return 0, err
 	}
@@ -48,6 +56,26 @@ func (r *ContentRepository) InsertLandingImage(ctx context.Context, url string)
 	return id, nil
 }
 
+// SetLandingImage implements content.Repository.
+func (r ContentRepository) SetLandingImage(ctx context.Context, id int64, img media.Image) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := setLandingImage(ctx, tx, id, ImageFromInternal(img)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // LandingImageByID implements content.Repository.
 func (r ContentRepository) LandingImageByID(ctx context.Context, id int64) (content.LandingImage, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -116,11 +144,11 @@ func (r ContentRepository) DeleteLandingImage(ctx context.Context, id int64) err
 	return nil
 }
 
-func insertLandingImage(ctx context.Context, tx *sql.Tx, url string) (int64, error) {
+func insertLandingImage(ctx context.Context, tx *sql.Tx, img Image) (int64, error) {
 	query, args, err := sq.
 		Insert("landing_image").
-		Columns("url").
-		Values(url).
+		Columns("url", "variants", "blur_hash", "color", "media_id", "alt_text", "credit", "license").
+		Values(img.URL, img.Variants, img.BlurHash, img.Color, img.MediaID, img.AltText, img.Credit, img.License).
 		ToSql()
 
 	if err != nil {
@@ -140,9 +168,35 @@ func insertLandingImage(ctx context.Context, tx *sql.Tx, url string) (int64, err
 	return id, nil
 }
 
+func setLandingImage(ctx context.Context, tx *sql.Tx, id int64, img Image) error {
+	query, args, err := sq.
+		Update("landing_image").
+		Set("url", img.URL).
+		Set("variants", img.Variants).
+		Set("blur_hash", img.BlurHash).
+		Set("color", img.Color).
+		Set("media_id", img.MediaID).
+		Set("alt_text", img.AltText).
+		Set("credit", img.Credit).
+		Set("license", img.License).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func landingImages(ctx context.Context, tx *sql.Tx) (ImageCollection, error) {
 	query, args, err := sq.
-		Select("id", "url").
+		Select("id", "url", "variants", "blur_hash", "color", "media_id", "alt_text", "credit", "license").
 		From("landing_image").
 		ToSql()
 
@@ -160,17 +214,13 @@ func landingImages(ctx context.Context, tx *sql.Tx) (ImageCollection, error) {
 	images := make(ImageCollection, 0)
 
 	for rows.Next() {
-		var id int64
-		var url string
+		var img Image
 
-		if err := rows.Scan(&id, &url); err != nil {
+		if err := rows.Scan(&img.ID, &img.URL, &img.Variants, &img.BlurHash, &img.Color, &img.MediaID, &img.AltText, &img.Credit, &img.License); err != nil {
 			return nil, err
 		}
 
-		images = append(images, Image{
-			ID:  id,
-			URL: url,
-		})
+		images = append(images, img)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -182,7 +232,7 @@ func landingImages(ctx context.Context, tx *sql.Tx) (ImageCollection, error) {
 
 func landingImageByID(ctx context.Context, tx *sql.Tx, id int64) (Image, error) {
 	query, args, err := sq.
-		Select("url").
+		Select("url", "variants", "blur_hash", "color", "media_id", "alt_text", "credit", "license").
 		From("landing_image").
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -191,20 +241,17 @@ func landingImageByID(ctx context.Context, tx *sql.Tx, id int64) (Image, error)
 		return Image{}, err
 	}
 
-	var url string
+	img := Image{ID: id}
 
 	err = tx.
 		QueryRowContext(ctx, query, args...).
-		Scan(&url)
+		Scan(&img.URL, &img.Variants, &img.BlurHash, &img.Color, &img.MediaID, &img.AltText, &img.Credit, &img.License)
 
 	if err != nil {
 		return Image{}, err
 	}
 
-	return Image{
-		ID:  id,
-		URL: url,
-	}, nil
+	return img, nil
 }
 
 func deleteLandingImage(ctx context.Context, tx *sql.Tx, id int64) error {
@@ -225,9 +272,33 @@ func deleteLandingImage(ctx context.Context, tx *sql.Tx, id int64) error {
 	return nil
 }
 
+func ImageFromInternal(img media.Image) Image {
+	return Image{
+		URL:      img.URL,
+		Variants: img.Variants,
+		BlurHash: img.BlurHash,
+		Color:    img.Color,
+		MediaID:  MediaID(img.MediaID),
+		AltText:  img.AltText,
+		Credit:   img.Credit,
+		License:  img.License,
+	}
+}
+
 func (img Image) ToInternal() content.LandingImage {
 	return content.LandingImage{
-		ID:  img.ID,
-		URL: img.URL,
+		ID: img.ID,
+		Image: media.Image{
+			URL:      img.URL,
+			Variants: img.Variants,
+			BlurHash: img.BlurHash,
+			Color:    img.Color,
+			Attribution: media.Attribution{
+				AltText: img.AltText,
+				Credit:  img.Credit,
+				License: img.License,
+			},
+			MediaID: int64(img.MediaID),
+		},
 	}
 }
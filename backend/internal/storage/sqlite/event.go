@@ -6,10 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattismoel/konnekt/internal/domain/concert"
 	"github.com/mattismoel/konnekt/internal/domain/event"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/search"
+	"github.com/mattismoel/konnekt/internal/domain/series"
 	"github.com/mattismoel/konnekt/internal/domain/venue"
 	"github.com/mattismoel/konnekt/internal/query"
 )
@@ -21,37 +25,117 @@ type EventRepository struct {
 var _ event.Repository = (*EventRepository)(nil)
 
 type Event struct {
-	ID          int64
-	Title       string
-	Description string
-	TicketURL   string
-	ImageURL    string
-	VenueID     int64
-	IsPublic    bool
+	ID            int64
+	Title         string
+	Description   string
+	TicketURL     string
+	ImageURL      string
+	ImageVariants ImageVariants
+	ImageBlurHash string
+	ImageColor    string
+	ImageFraming  ImageFraming
+	ImageCrops    ImageCrops
+	ImageMediaID  MediaID
+	ImageAltText  string
+	ImageCredit   string
+	ImageLicense  string
+	VenueID       int64
+
+	Status       string
+	StatusNotice string
+
+	PublishAt   sql.NullTime
+	UnpublishAt sql.NullTime
+
+	SeriesID sql.NullInt64
+
+	RecurrenceID    sql.NullInt64
+	OccurrenceStart sql.NullTime
+
+	UpdatedAt time.Time
 }
 
 func EventFromInternal(e event.Event) Event {
-	return Event{
-		ID:          e.ID,
-		Title:       e.Title,
-		Description: e.Description,
-		TicketURL:   e.TicketURL,
-		ImageURL:    e.ImageURL,
-		VenueID:     e.Venue.ID,
-		IsPublic:    e.IsPublic,
+	dbEvent := Event{
+		ID:            e.ID,
+		Title:         e.Title,
+		Description:   e.Description,
+		TicketURL:     e.TicketURL,
+		ImageURL:      e.Image.URL,
+		ImageVariants: e.Image.Variants,
+		ImageBlurHash: e.Image.BlurHash,
+		ImageColor:    e.Image.Color,
+		ImageFraming:  ImageFraming(e.Image.Framing),
+		ImageCrops:    e.Image.Crops,
+		ImageMediaID:  MediaID(e.Image.MediaID),
+		ImageAltText:  e.Image.AltText,
+		ImageCredit:   e.Image.Credit,
+		ImageLicense:  e.Image.License,
+		VenueID:       e.Venue.ID,
+
+		Status:       string(e.Status),
+		StatusNotice: e.StatusNotice,
+
+		PublishAt:   nullTimeFromPtr(e.PublishAt),
+		UnpublishAt: nullTimeFromPtr(e.UnpublishAt),
+	}
+
+	if e.Series != nil {
+		dbEvent.SeriesID = sql.NullInt64{Int64: e.Series.ID, Valid: true}
+	}
+
+	if e.Recurrence != nil {
+		dbEvent.RecurrenceID = sql.NullInt64{Int64: e.Recurrence.ID, Valid: true}
+		dbEvent.OccurrenceStart = nullTimeFromPtr(e.OccurrenceStart)
+	}
+
+	return dbEvent
+}
+
+func (e Event) image() media.Image {
+	return media.Image{
+		URL:      e.ImageURL,
+		Variants: e.ImageVariants,
+		BlurHash: e.ImageBlurHash,
+		Color:    e.ImageColor,
+		Framing:  media.Framing(e.ImageFraming),
+		Crops:    e.ImageCrops,
+		Attribution: media.Attribution{
+			AltText: e.ImageAltText,
+			Credit:  e.ImageCredit,
+			License: e.ImageLicense,
+		},
+		MediaID: int64(e.ImageMediaID),
 	}
 }
 
-func (e Event) ToInternal(venue venue.Venue, concerts []concert.Concert) event.Event {
+func (e Event) ToInternal(
+	venue venue.Venue,
+	concerts []concert.Concert,
+	s *series.Series,
+	r *event.Recurrence,
+) event.Event {
 	return event.Event{
 		ID:          e.ID,
 		Title:       e.Title,
 		Description: e.Description,
 		TicketURL:   e.TicketURL,
-		ImageURL:    e.ImageURL,
+		Image:       e.image(),
 		Venue:       venue,
 		Concerts:    concerts,
-		IsPublic:    e.IsPublic,
+
+		Status:       event.Status(e.Status),
+		StatusNotice: e.StatusNotice,
+
+		PublishAt:   ptrFromNullTime(e.PublishAt),
+		UnpublishAt: ptrFromNullTime(e.UnpublishAt),
+
+		Series: s,
+
+		Recurrence:      r,
+		OccurrenceStart: ptrFromNullTime(e.OccurrenceStart),
+
+		UpdatedAt: e.UpdatedAt,
 	}
 }
 
@@ -95,11 +179,21 @@ func (repo EventRepository) ByID(ctx context.Context, eventID int64) (event.Even
 
 	venue := dbVenue.ToInternal()
 
+	s, err := eventSeries(ctx, tx, dbEvent)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	r, err := eventRecurrence(ctx, tx, dbEvent)
+	if err != nil {
+		return event.Event{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return event.Event{}, err
 	}
 
-	e := dbEvent.ToInternal(venue, concerts)
+	e := dbEvent.ToInternal(venue, concerts, s, r)
 
 	return e, nil
 }
@@ -112,26 +206,11 @@ func (repo EventRepository) Insert(ctx context.Context, e event.Event) (int64, e
 
 	defer tx.Rollback()
 
-	eventID, err := insertEvent(ctx, tx, EventFromInternal(e))
+	eventID, err := insertEventWithConcerts(ctx, tx, EventFromInternal(e), e.Concerts)
 	if err != nil {
 		return 0, err
 	}
 
-	for _, c := range e.Concerts {
-		dbArtist, err := artistByID(ctx, tx, c.Artist.ID)
-		if err != nil {
-			return 0, err
-		}
-
-		dbConcert := ConcertFromInternal(c, eventID)
-		dbConcert.ArtistID = dbArtist.ID
-
-		_, err = insertConcert(ctx, tx, dbConcert)
-		if err != nil {
-			return 0, err
-		}
-	}
-
 	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
@@ -147,21 +226,31 @@ func (repo EventRepository) Update(ctx context.Context, eventID int64, e event.E
 
 	defer tx.Rollback()
 
-	err = updateEvent(ctx, tx, eventID, EventFromInternal(e))
-	if err != nil {
+	if err := updateEventWithConcerts(ctx, tx, eventID, e); err != nil {
 		return err
 	}
 
-	concerts := make([]Concert, 0)
-	for _, c := range e.Concerts {
-		concerts = append(concerts, ConcertFromInternal(c, eventID))
+	if err := tx.Commit(); err != nil {
+		return err
 	}
 
-	_, err = setEventConcerts(ctx, tx, eventID, concerts...)
+	return nil
+}
+
+func (repo EventRepository) UpdateOccurrences(ctx context.Context, events []event.Event) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
+	defer tx.Rollback()
+
+	for _, e := range events {
+		if err := updateEventWithConcerts(ctx, tx, e.ID, e); err != nil {
+			return err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -185,6 +274,30 @@ func (repo EventRepository) Delete(ctx context.Context, eventID int64) error {
 		return err
 	}
 
+	if err := unindexSearch(ctx, tx, search.KindEvent, eventID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo EventRepository) SetImage(ctx context.Context, eventID int64, img media.Image) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = setEventImage(ctx, tx, eventID, img)
+	if err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -192,7 +305,7 @@ func (repo EventRepository) Delete(ctx context.Context, eventID int64) error {
 	return nil
 }
 
-func (repo EventRepository) SetImageURL(ctx context.Context, eventID int64, coverImageURL string) error {
+func (repo EventRepository) SetStatus(ctx context.Context, eventID int64, status event.Status, notice string) error {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -200,7 +313,7 @@ func (repo EventRepository) SetImageURL(ctx context.Context, eventID int64, cove
 
 	defer tx.Rollback()
 
-	err = setEventImageURL(ctx, tx, eventID, coverImageURL)
+	err = setEventStatus(ctx, tx, eventID, string(status), notice)
 	if err != nil {
 		return err
 	}
@@ -212,6 +325,71 @@ func (repo EventRepository) SetImageURL(ctx context.Context, eventID int64, cove
 	return nil
 }
 
+func (repo EventRepository) ApplySchedule(ctx context.Context, eventID int64, status event.Status, notice string, publishAt, unpublishAt *time.Time) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = setEventStatus(ctx, tx, eventID, string(status), notice)
+	if err != nil {
+		return err
+	}
+
+	err = setEventPublishSchedule(ctx, tx, eventID, nullTimeFromPtr(publishAt), nullTimeFromPtr(unpublishAt))
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo EventRepository) ScheduleDue(ctx context.Context, now time.Time) ([]int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	eventIDs, err := scheduleDueEventIDs(ctx, tx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return eventIDs, nil
+}
+
+func (repo EventRepository) Images(ctx context.Context) (map[int64]media.Image, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	images, err := eventImages(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
 func (repo EventRepository) List(ctx context.Context, q query.ListQuery) (query.ListResult[event.Event], error) {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -225,6 +403,7 @@ func (repo EventRepository) List(ctx context.Context, q query.ListQuery) (query.
 		Limit:   q.Limit,
 		Filters: q.Filters,
 		OrderBy: q.OrderBy,
+		Search:  q.Search,
 	})
 
 	if err != nil {
@@ -256,7 +435,17 @@ func (repo EventRepository) List(ctx context.Context, q query.ListQuery) (query.
 			return query.ListResult[event.Event]{}, err
 		}
 
-		event := dbEvent.ToInternal(venue, concerts)
+		s, err := eventSeries(ctx, tx, dbEvent)
+		if err != nil {
+			return query.ListResult[event.Event]{}, err
+		}
+
+		r, err := eventRecurrence(ctx, tx, dbEvent)
+		if err != nil {
+			return query.ListResult[event.Event]{}, err
+		}
+
+		event := dbEvent.ToInternal(venue, concerts, s, r)
 		events = append(events, event)
 	}
 
@@ -298,8 +487,24 @@ var eventBuilder = sq.
 		"event.description",
 		"event.ticket_url",
 		"event.image_url",
+		"event.image_variants",
+		"event.image_blur_hash",
+		"event.image_color",
+		"event.image_framing",
+		"event.image_crops",
+		"event.image_media_id",
+		"event.image_alt_text",
+		"event.image_credit",
+		"event.image_license",
 		"event.venue_id",
-		"event.is_public",
+		"event.status",
+		"event.status_notice",
+		"event.publish_at",
+		"event.unpublish_at",
+		"event.series_id",
+		"event.recurrence_id",
+		"event.occurrence_start",
+		"event.updated_at",
 	).
 	From("event")
 
@@ -310,8 +515,24 @@ func scanEvent(s Scanner, dst *Event) error {
 		&dst.Description,
 		&dst.TicketURL,
 		&dst.ImageURL,
+		&dst.ImageVariants,
+		&dst.ImageBlurHash,
+		&dst.ImageColor,
+		&dst.ImageFraming,
+		&dst.ImageCrops,
+		&dst.ImageMediaID,
+		&dst.ImageAltText,
+		&dst.ImageCredit,
+		&dst.ImageLicense,
 		&dst.VenueID,
-		&dst.IsPublic,
+		&dst.Status,
+		&dst.StatusNotice,
+		&dst.PublishAt,
+		&dst.UnpublishAt,
+		&dst.SeriesID,
+		&dst.RecurrenceID,
+		&dst.OccurrenceStart,
+		&dst.UpdatedAt,
 	)
 
 	if err != nil {
@@ -321,6 +542,39 @@ func scanEvent(s Scanner, dst *Event) error {
 	return nil
 }
 
+// Returns the images of all events. Unlike listing events, events without
+// concerts are included.
+func eventImages(ctx context.Context, tx *sql.Tx) (map[int64]media.Image, error) {
+	query, args, err := eventBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	images := make(map[int64]media.Image)
+
+	for rows.Next() {
+		var e Event
+		if err := scanEvent(rows, &e); err != nil {
+			return nil, err
+		}
+
+		images[e.ID] = e.image()
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
 func listEvents(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Event, error) {
 	builder := eventBuilder.
 		Distinct().
@@ -332,9 +586,15 @@ func listEvents(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Event, e
 		},
 		"is_public": func(f query.Filter) sq.Sqlizer {
 			if strings.ToUpper(f.Value) == "TRUE" {
-				return sq.Eq{"is_public": true}
+				return isPublicEvent(time.Now())
+			}
+			return sq.Expr("NOT (?)", isPublicEvent(time.Now()))
+		},
+		"status": func(f query.Filter) sq.Sqlizer {
+			if f.Cmp == query.NotEqual {
+				return sq.NotEq{"event.status": f.Value}
 			}
-			return sq.Eq{"is_public": false}
+			return sq.Eq{"event.status": f.Value}
 		},
 		"from_date": func(f query.Filter) sq.Sqlizer {
 			return sq.Expr(fmt.Sprintf("concert.from_date %s ?", f.Cmp), f.Value)
@@ -345,8 +605,18 @@ func listEvents(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Event, e
 		"artist_id": func(f query.Filter) sq.Sqlizer {
 			return sq.Eq{"concert.artist_id": f.Value}
 		},
+		"venue_id": func(f query.Filter) sq.Sqlizer {
+			return sq.Eq{"event.venue_id": f.Value}
+		},
+		"series_id": func(f query.Filter) sq.Sqlizer {
+			return sq.Eq{"event.series_id": f.Value}
+		},
+		"recurrence_id": func(f query.Filter) sq.Sqlizer {
+			return sq.Eq{"event.recurrence_id": f.Value}
+		},
 	})
 
+	builder = withSearch(builder, search.KindEvent, params.Search)
 	builder = withOrdering(builder, params.OrderBy, "from_date", "concert")
 	builder = withPagination(builder, params)
 
@@ -379,10 +649,147 @@ func listEvents(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Event, e
 	return events, nil
 }
 
+// Returns the series of the event, or nil if the event is not part of a series.
+func eventSeries(ctx context.Context, tx *sql.Tx, e Event) (*series.Series, error) {
+	if !e.SeriesID.Valid {
+		return nil, nil
+	}
+
+	dbSeries, err := seriesByID(ctx, tx, e.SeriesID.Int64)
+	if err != nil {
+		return nil, err
+	}
+
+	s := dbSeries.ToInternal()
+	return &s, nil
+}
+
+// Updates the event and replaces its concerts, keeping the search index up to
+// date.
+func updateEventWithConcerts(ctx context.Context, tx *sql.Tx, eventID int64, e event.Event) error {
+	err := updateEvent(ctx, tx, eventID, EventFromInternal(e))
+	if err != nil {
+		return err
+	}
+
+	if err := indexSearch(ctx, tx, search.KindEvent, eventID); err != nil {
+		return err
+	}
+
+	concerts := make([]Concert, 0)
+	for _, c := range e.Concerts {
+		concerts = append(concerts, ConcertFromInternal(c, eventID))
+	}
+
+	_, err = setEventConcerts(ctx, tx, eventID, concerts...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns a condition matching events, which are visible to the public at the
+// given time. Events not yet published, or already unpublished, are not public
+// regardless of their status, as the scheduler may lag behind.
+func isPublicEvent(now time.Time) sq.Sqlizer {
+	publicStatuses := make([]string, 0)
+	for _, status := range event.PublicStatuses() {
+		publicStatuses = append(publicStatuses, string(status))
+	}
+
+	return sq.And{
+		sq.Eq{"event.status": publicStatuses},
+		sq.Or{
+			sq.Eq{"event.publish_at": nil},
+			sq.LtOrEq{"event.publish_at": formatTime(now)},
+		},
+		sq.Or{
+			sq.Eq{"event.unpublish_at": nil},
+			sq.Gt{"event.unpublish_at": formatTime(now)},
+		},
+	}
+}
+
+// Inserts the event along with its concerts, returning the ID of the event.
+func insertEventWithConcerts(ctx context.Context, tx *sql.Tx, e Event, concerts []concert.Concert) (int64, error) {
+	eventID, err := insertEvent(ctx, tx, e)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := indexSearch(ctx, tx, search.KindEvent, eventID); err != nil {
+		return 0, err
+	}
+
+	for _, c := range concerts {
+		dbArtist, err := artistByID(ctx, tx, c.Artist.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		dbConcert := ConcertFromInternal(c, eventID)
+		dbConcert.ArtistID = dbArtist.ID
+
+		_, err = insertConcert(ctx, tx, dbConcert)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return eventID, nil
+}
+
 func insertEvent(ctx context.Context, tx *sql.Tx, e Event) (int64, error) {
 	query, args, err := sq.Insert("event").
-		Columns("title", "description", "ticket_url", "image_url", "venue_id", "is_public").
-		Values(e.Title, e.Description, e.TicketURL, e.ImageURL, e.VenueID, e.IsPublic).ToSql()
+		Columns(
+			"title",
+			"description",
+			"ticket_url",
+			"image_url",
+			"image_variants",
+			"image_blur_hash",
+			"image_color",
+			"image_framing",
+			"image_crops",
+			"image_media_id",
+			"image_alt_text",
+			"image_credit",
+			"image_license",
+			"venue_id",
+			"status",
+			"status_notice",
+			"publish_at",
+			"unpublish_at",
+			"series_id",
+			"recurrence_id",
+			"occurrence_start",
+			"updated_at",
+		).
+		Values(
+			e.Title,
+			e.Description,
+			e.TicketURL,
+			e.ImageURL,
+			e.ImageVariants,
+			e.ImageBlurHash,
+			e.ImageColor,
+			e.ImageFraming,
+			e.ImageCrops,
+			e.ImageMediaID,
+			e.ImageAltText,
+			e.ImageCredit,
+			e.ImageLicense,
+			e.VenueID,
+			e.Status,
+			e.StatusNotice,
+			formatNullTime(e.PublishAt),
+			formatNullTime(e.UnpublishAt),
+			e.SeriesID,
+			e.RecurrenceID,
+			formatNullTime(e.OccurrenceStart),
+			formatTime(time.Now()),
+		).ToSql()
 
 	if err != nil {
 		return 0, err
@@ -401,10 +808,20 @@ func insertEvent(ctx context.Context, tx *sql.Tx, e Event) (int64, error) {
 	return eventID, nil
 }
 
-func setEventImageURL(ctx context.Context, tx *sql.Tx, eventID int64, url string) error {
+func setEventImage(ctx context.Context, tx *sql.Tx, eventID int64, img media.Image) error {
 	query, args, err := sq.
 		Update("event").
-		Set("image_url", url).
+		Set("image_url", img.URL).
+		Set("image_variants", ImageVariants(img.Variants)).
+		Set("image_blur_hash", img.BlurHash).
+		Set("image_color", img.Color).
+		Set("image_framing", ImageFraming(img.Framing)).
+		Set("image_crops", ImageCrops(img.Crops)).
+		Set("image_media_id", MediaID(img.MediaID)).
+		Set("image_alt_text", img.AltText).
+		Set("image_credit", img.Credit).
+		Set("image_license", img.License).
+		Set("updated_at", formatTime(time.Now())).
 		Where(sq.Eq{"id": eventID}).
 		ToSql()
 
@@ -420,6 +837,104 @@ func setEventImageURL(ctx context.Context, tx *sql.Tx, eventID int64, url string
 	return nil
 }
 
+func setEventStatus(ctx context.Context, tx *sql.Tx, eventID int64, status string, notice string) error {
+	query, args, err := sq.
+		Update("event").
+		Set("status", status).
+		Set("status_notice", notice).
+		Set("updated_at", formatTime(time.Now())).
+		Where(sq.Eq{"id": eventID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected <= 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func setEventPublishSchedule(ctx context.Context, tx *sql.Tx, eventID int64, publishAt, unpublishAt sql.NullTime) error {
+	query, args, err := sq.
+		Update("event").
+		Set("publish_at", formatNullTime(publishAt)).
+		Set("unpublish_at", formatNullTime(unpublishAt)).
+		Set("updated_at", formatTime(time.Now())).
+		Where(sq.Eq{"id": eventID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected <= 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func scheduleDueEventIDs(ctx context.Context, tx *sql.Tx, now time.Time) ([]int64, error) {
+	query, args, err := sq.
+		Select("id").
+		From("event").
+		Where(sq.Or{
+			sq.LtOrEq{"publish_at": formatTime(now)},
+			sq.LtOrEq{"unpublish_at": formatTime(now)},
+		}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	eventIDs := make([]int64, 0)
+	for rows.Next() {
+		var eventID int64
+		if err := rows.Scan(&eventID); err != nil {
+			return nil, err
+		}
+
+		eventIDs = append(eventIDs, eventID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return eventIDs, nil
+}
+
 func updateEvent(ctx context.Context, tx *sql.Tx, eventID int64, e Event) error {
 	builder := sq.Update("event").Where(sq.Eq{"id": eventID})
 
@@ -437,13 +952,28 @@ func updateEvent(ctx context.Context, tx *sql.Tx, eventID int64, e Event) error
 
 	if e.ImageURL != "" {
 		builder = builder.Set("image_url", e.ImageURL)
+		builder = builder.Set("image_variants", e.ImageVariants)
+		builder = builder.Set("image_blur_hash", e.ImageBlurHash)
+		builder = builder.Set("image_color", e.ImageColor)
+		builder = builder.Set("image_framing", e.ImageFraming)
+		builder = builder.Set("image_crops", e.ImageCrops)
+		builder = builder.Set("image_media_id", e.ImageMediaID)
+		builder = builder.Set("image_alt_text", e.ImageAltText)
+		builder = builder.Set("image_credit", e.ImageCredit)
+		builder = builder.Set("image_license", e.ImageLicense)
 	}
 
 	if e.VenueID != 0 {
 		builder = builder.Set("venue_id", e.VenueID)
 	}
 
-	builder = builder.Set("is_public", e.IsPublic)
+	// The publish schedule and series are always replaced, as clearing them is
+	// a valid update.
+	builder = builder.
+		Set("publish_at", formatNullTime(e.PublishAt)).
+		Set("unpublish_at", formatNullTime(e.UnpublishAt)).
+		Set("series_id", e.SeriesID).
+		Set("updated_at", formatTime(time.Now()))
 
 	query, args, err := builder.ToSql()
 	if err != nil {
@@ -0,0 +1,491 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/invitation"
+	"github.com/mattismoel/konnekt/internal/domain/member"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+type Invitation struct {
+	ID        int64
+	Email     string
+	TokenHash string
+	InvitedBy int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func InvitationFromInternal(i invitation.Invitation) Invitation {
+	return Invitation{
+		ID:        i.ID,
+		Email:     i.Email,
+		TokenHash: i.TokenHash,
+		InvitedBy: i.InvitedBy,
+		CreatedAt: i.CreatedAt,
+		ExpiresAt: i.ExpiresAt,
+	}
+}
+
+func (i Invitation) ToInternal(teams TeamCollection) invitation.Invitation {
+	return invitation.Invitation{
+		ID:        i.ID,
+		Email:     i.Email,
+		Teams:     teams.ToInternal(),
+		InvitedBy: i.InvitedBy,
+		CreatedAt: i.CreatedAt.UTC(),
+		ExpiresAt: i.ExpiresAt.UTC(),
+		TokenHash: i.TokenHash,
+	}
+}
+
+var _ invitation.Repository = (*InvitationRepository)(nil)
+
+type InvitationRepository struct {
+	db *sql.DB
+}
+
+func NewInvitationRepository(db *sql.DB) (*InvitationRepository, error) {
+	return &InvitationRepository{
+		db: db,
+	}, nil
+}
+
+func (repo InvitationRepository) List(ctx context.Context, q query.ListQuery) (query.ListResult[invitation.Invitation], error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return query.ListResult[invitation.Invitation]{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbInvitations, totalCount, err := listInvitations(ctx, tx, q)
+	if err != nil {
+		return query.ListResult[invitation.Invitation]{}, err
+	}
+
+	records := make([]invitation.Invitation, 0)
+	for _, i := range dbInvitations {
+		teams, err := invitationTeams(ctx, tx, i.ID)
+		if err != nil {
+			return query.ListResult[invitation.Invitation]{}, err
+		}
+
+		records = append(records, i.ToInternal(teams))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return query.ListResult[invitation.Invitation]{}, err
+	}
+
+	return query.ListResult[invitation.Invitation]{
+		Page:       q.Page,
+		PerPage:    q.PerPage,
+		TotalCount: totalCount,
+		PageCount:  q.PageCount(totalCount),
+		Records:    records,
+	}, nil
+}
+
+func (repo InvitationRepository) ByID(ctx context.Context, invitationID int64) (invitation.Invitation, error) {
+	return repo.invitationWhere(ctx, sq.Eq{"id": invitationID}, invitation.ErrNotFound)
+}
+
+func (repo InvitationRepository) ByTokenHash(ctx context.Context, tokenHash string) (invitation.Invitation, error) {
+	return repo.invitationWhere(ctx, sq.Eq{"token_hash": tokenHash}, invitation.ErrInvalidToken)
+}
+
+// Returns the invitation matching the condition, or errNoRows if there is
+// none.
+func (repo InvitationRepository) invitationWhere(ctx context.Context, where sq.Sqlizer, errNoRows error) (invitation.Invitation, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbInvitation, err := invitationWhere(ctx, tx, where)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return invitation.Invitation{}, errNoRows
+		}
+
+		return invitation.Invitation{}, err
+	}
+
+	teams, err := invitationTeams(ctx, tx, dbInvitation.ID)
+	if err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return invitation.Invitation{}, err
+	}
+
+	return dbInvitation.ToInternal(teams), nil
+}
+
+func (repo InvitationRepository) Insert(ctx context.Context, i invitation.Invitation) (int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	invitationID, err := insertInvitation(ctx, tx, InvitationFromInternal(i))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, t := range i.Teams {
+		if err := addInvitationTeam(ctx, tx, invitationID, t.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return invitationID, nil
+}
+
+func (repo InvitationRepository) Delete(ctx context.Context, invitationID int64) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	count, err := deleteInvitations(ctx, tx, sq.Eq{"id": invitationID})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return invitation.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo InvitationRepository) Accept(ctx context.Context, invitationID int64, m member.Member, verifiedAt time.Time) (int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	teams, err := invitationTeams(ctx, tx, invitationID)
+	if err != nil {
+		return 0, err
+	}
+
+	// The invitation is deleted first, so that it is only accepted once.
+	count, err := deleteInvitations(ctx, tx, sq.Eq{"id": invitationID})
+	if err != nil {
+		return 0, err
+	}
+
+	if count == 0 {
+		return 0, invitation.ErrNotFound
+	}
+
+	memberID, err := insertMember(ctx, tx, MemberFromInternal(m))
+	if err != nil {
+		if errors.Is(err, ErrAlreadyExists) {
+			return 0, member.ErrAlreadyExists
+		}
+
+		return 0, err
+	}
+
+	if err := approveMember(ctx, tx, memberID); err != nil {
+		return 0, err
+	}
+
+	if err := setMemberTime(ctx, tx, memberID, "email_verified_at", verifiedAt); err != nil {
+		return 0, err
+	}
+
+	for _, t := range teams {
+		if err := associateMemberWithTeam(ctx, tx, memberID, t.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return memberID, nil
+}
+
+func (repo InvitationRepository) DeleteByEmail(ctx context.Context, email string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := deleteInvitations(ctx, tx, sq.Eq{"email": email}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var invitationBuilder = sq.
+	Select(
+		"invitation.id",
+		"invitation.email",
+		"invitation.token_hash",
+		"invitation.invited_by",
+		"invitation.created_at",
+		"invitation.expires_at",
+	).
+	From("invitation")
+
+func scanInvitation(s Scanner, dst *Invitation) error {
+	err := s.Scan(
+		&dst.ID,
+		&dst.Email,
+		&dst.TokenHash,
+		&dst.InvitedBy,
+		&dst.CreatedAt,
+		&dst.ExpiresAt,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns the given page of invitations, along with the total count of
+// invitations matching the query. Invitations are listed newest first, unless
+// ordered otherwise.
+func listInvitations(ctx context.Context, tx *sql.Tx, q query.ListQuery) ([]Invitation, int, error) {
+	where := sq.And{}
+
+	for key, filters := range q.Filters {
+		for _, f := range filters {
+			switch key {
+			case "email":
+				where = append(where, contains("invitation.email", f.Value))
+			case "expired":
+				switch f.Value {
+				case "true":
+					where = append(where, sq.LtOrEq{"invitation.expires_at": formatTime(time.Now())})
+				case "false":
+					where = append(where, sq.Gt{"invitation.expires_at": formatTime(time.Now())})
+				}
+			}
+		}
+	}
+
+	if q.Search != "" {
+		where = append(where, contains("invitation.email", q.Search))
+	}
+
+	countQuery, countArgs, err := sq.Select("COUNT(*)").From("invitation").Where(where).ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int
+	if err := tx.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	builder := invitationBuilder.Where(where)
+	builder = withOrdering(builder, q.OrderBy, "email", "invitation")
+	builder = withOrdering(builder, q.OrderBy, "expires_at", "invitation")
+	builder = builder.OrderBy("invitation.created_at DESC", "invitation.id DESC")
+
+	if q.PerPage > 0 {
+		builder = builder.Limit(uint64(q.PerPage)).Offset(uint64(max(q.Offset(), 0)))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	invitations := make([]Invitation, 0)
+	for rows.Next() {
+		var i Invitation
+		if err := scanInvitation(rows, &i); err != nil {
+			return nil, 0, err
+		}
+
+		invitations = append(invitations, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return invitations, totalCount, nil
+}
+
+func invitationWhere(ctx context.Context, tx *sql.Tx, where sq.Sqlizer) (Invitation, error) {
+	query, args, err := invitationBuilder.
+		Where(where).
+		ToSql()
+
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	var i Invitation
+	row := tx.QueryRowContext(ctx, query, args...)
+	if err := scanInvitation(row, &i); err != nil {
+		return Invitation{}, err
+	}
+
+	return i, nil
+}
+
+func insertInvitation(ctx context.Context, tx *sql.Tx, i Invitation) (int64, error) {
+	query, args, err := sq.
+		Insert("invitation").
+		Columns("email", "token_hash", "invited_by", "created_at", "expires_at").
+		Values(i.Email, i.TokenHash, i.InvitedBy, formatTime(i.CreatedAt), formatTime(i.ExpiresAt)).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	invitationID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return invitationID, nil
+}
+
+// Deletes the invitations matching the condition along with their teams,
+// returning the amount of deleted invitations.
+func deleteInvitations(ctx context.Context, tx *sql.Tx, where sq.Sqlizer) (int64, error) {
+	idQuery, idArgs, err := sq.Select("id").From("invitation").Where(where).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	query, args, err := sq.
+		Delete("invitation_team").
+		Where("invitation_id IN ("+idQuery+")", idArgs...).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	query, args, err = sq.
+		Delete("invitation").
+		Where(where).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func invitationTeams(ctx context.Context, tx *sql.Tx, invitationID int64) (TeamCollection, error) {
+	query, args, err := teamBuilder.
+		Join("invitation_team it ON it.team_id = team.id").
+		Where(sq.Eq{"it.invitation_id": invitationID}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	teams := make(TeamCollection, 0)
+
+	for rows.Next() {
+		var t Team
+		if err := scanTeam(rows, &t); err != nil {
+			return nil, err
+		}
+
+		teams = append(teams, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+func addInvitationTeam(ctx context.Context, tx *sql.Tx, invitationID int64, teamID int64) error {
+	query, args, err := sq.
+		Insert("invitation_team").
+		Options("OR IGNORE").
+		Columns("invitation_id", "team_id").
+		Values(invitationID, teamID).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
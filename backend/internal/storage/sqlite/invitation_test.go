@@ -0,0 +1,118 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattismoel/konnekt/internal/domain/invitation"
+	"github.com/mattismoel/konnekt/internal/domain/member"
+	"github.com/mattismoel/konnekt/internal/domain/team"
+	"github.com/mattismoel/konnekt/internal/storage/sqlite"
+)
+
+func TestAcceptInvitation(t *testing.T) {
+	ctx := context.Background()
+
+	const email = "invitee@knnkt.dk"
+
+	type test struct {
+		// Whether or not the email is already registered.
+		registered bool
+		wantErr    error
+	}
+
+	tests := map[string]test{
+		"Invited email":    {},
+		"Registered email": {registered: true, wantErr: member.ErrAlreadyExists},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := openTestDB(t)
+
+			m, err := sqlite.NewMigrator(db)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := m.Up(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			memberRepo, _ := sqlite.NewMemberRepository(db)
+			teamRepo, _ := sqlite.NewTeamRepository(db)
+			invitationRepo, _ := sqlite.NewInvitationRepository(db)
+
+			teamID, err := teamRepo.Insert(ctx, team.Team{Name: "booking", DisplayName: "Booking"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			invitee, err := member.NewMember(
+				member.WithEmail(email),
+				member.WithFirstName("Invited"),
+				member.WithLastName("Member"),
+				member.WithPasswordHash([]byte("hash")),
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.registered {
+				if _, err := memberRepo.Insert(ctx, invitee); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			token, err := invitation.NewToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			inv, err := invitation.NewInvitation(token, email, team.TeamCollection{{ID: teamID}}, 1, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			invitationID, err := invitationRepo.Insert(ctx, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			memberID, err := invitationRepo.Accept(ctx, invitationID, invitee, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			// A failed acceptance leaves the invitation in place, so that it
+			// may be accepted again.
+			if err != nil {
+				if _, err := invitationRepo.ByID(ctx, invitationID); err != nil {
+					t.Fatalf("got %v, want invitation kept", err)
+				}
+
+				return
+			}
+
+			accepted, err := memberRepo.ByID(ctx, memberID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !accepted.Active || !accepted.IsEmailVerified() {
+				t.Fatalf("got active %t and verified %t, want both", accepted.Active, accepted.IsEmailVerified())
+			}
+
+			if len(accepted.Teams) != 1 || accepted.Teams[0].ID != teamID {
+				t.Fatalf("got teams %+v, want team %d", accepted.Teams, teamID)
+			}
+
+			if _, err := invitationRepo.Accept(ctx, invitationID, invitee, time.Now()); !errors.Is(err, invitation.ErrNotFound) {
+				t.Fatalf("got %v, want %v", err, invitation.ErrNotFound)
+			}
+		})
+	}
+}
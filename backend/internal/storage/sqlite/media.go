@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mattismoel/konnekt/internal/domain/media"
+)
+
+// The variants of an image, stored as a JSON array alongside the URL of the
+// image.
+type ImageVariants []media.Variant
+
+// Scan implements sql.Scanner.
+func (v *ImageVariants) Scan(src any) error {
+	variants := make(ImageVariants, 0)
+	if err := scanJSON(src, &variants); err != nil {
+		return err
+	}
+
+	*v = variants
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (v ImageVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+
+	return jsonValue(v)
+}
+
+// The framing of an image, stored as a JSON object.
+type ImageFraming media.Framing
+
+// Scan implements sql.Scanner.
+func (f *ImageFraming) Scan(src any) error {
+	var framing ImageFraming
+	if err := scanJSON(src, &framing); err != nil {
+		return err
+	}
+
+	*f = framing
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (f ImageFraming) Value() (driver.Value, error) {
+	return jsonValue(f)
+}
+
+// The crops of an image, stored as a JSON array.
+type ImageCrops []media.AspectCrop
+
+// Scan implements sql.Scanner.
+func (c *ImageCrops) Scan(src any) error {
+	crops := make(ImageCrops, 0)
+	if err := scanJSON(src, &crops); err != nil {
+		return err
+	}
+
+	*c = crops
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (c ImageCrops) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+
+	return jsonValue(c)
+}
+
+// The media asset of an image, stored as NULL for images of no asset.
+type MediaID int64
+
+// Scan implements sql.Scanner.
+func (id *MediaID) Scan(src any) error {
+	var n sql.NullInt64
+	if err := n.Scan(src); err != nil {
+		return err
+	}
+
+	*id = MediaID(n.Int64)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (id MediaID) Value() (driver.Value, error) {
+	if id <= 0 {
+		return nil, nil
+	}
+
+	return int64(id), nil
+}
+
+// Unmarshals the given JSON column into dst. Null columns leave dst as is.
+func scanJSON(src any, dst any) error {
+	var data []byte
+
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(src)
+	case []byte:
+		data = src
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dst)
+	}
+
+	return json.Unmarshal(data, dst)
+}
+
+func jsonValue(v any) (driver.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/media"
 	"github.com/mattismoel/konnekt/internal/domain/member"
 	"github.com/mattismoel/konnekt/internal/query"
 )
@@ -14,13 +16,18 @@ import (
 var _ member.Repository = (*MemberRepository)(nil)
 
 type Member struct {
-	ID                int64
-	Email             string
-	FirstName         string
-	LastName          string
-	PasswordHash      []byte
-	Active            bool
-	ProfilePictureURL string
+	ID                     int64
+	Email                  string
+	FirstName              string
+	LastName               string
+	PasswordHash           []byte
+	Active                 bool
+	ProfilePictureURL      string
+	ProfilePictureVariants ImageVariants
+	ProfilePictureBlurHash string
+	ProfilePictureColor    string
+	EmailVerifiedAt        sql.NullTime
+	VerificationSentAt     sql.NullTime
 }
 
 type MemberCollection []Member
@@ -35,6 +42,20 @@ func NewMemberRepository(db *sql.DB) (*MemberRepository, error) {
 	}, nil
 }
 
+func MemberFromInternal(m member.Member) Member {
+	return Member{
+		ID:                     m.ID,
+		Email:                  m.Email,
+		FirstName:              m.FirstName,
+		LastName:               m.LastName,
+		PasswordHash:           m.PasswordHash,
+		ProfilePictureURL:      m.ProfilePicture.URL,
+		ProfilePictureVariants: m.ProfilePicture.Variants,
+		ProfilePictureBlurHash: m.ProfilePicture.BlurHash,
+		ProfilePictureColor:    m.ProfilePicture.Color,
+	}
+}
+
 func (repo MemberRepository) Insert(ctx context.Context, m member.Member) (int64, error) {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -43,14 +64,7 @@ func (repo MemberRepository) Insert(ctx context.Context, m member.Member) (int64
 
 	defer tx.Rollback()
 
-	memberID, err := insertMember(ctx, tx, Member{
-		ID:                m.ID,
-		Email:             m.Email,
-		FirstName:         m.FirstName,
-		LastName:          m.LastName,
-		PasswordHash:      m.PasswordHash,
-		ProfilePictureURL: m.ProfilePictureURL,
-	})
+	memberID, err := insertMember(ctx, tx, MemberFromInternal(m))
 
 	if err != nil {
 		switch {
@@ -117,7 +131,23 @@ func (repo MemberRepository) Approve(ctx context.Context, memberID int64) error
 }
 
 // TODO: Implement...
-func (repo MemberRepository) SetProfilePictureURL(ctx context.Context, memberID int64, url string) error {
+func (repo MemberRepository) SetProfilePicture(ctx context.Context, memberID int64, img media.Image) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = setMemberProfilePicture(ctx, tx, memberID, img)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -203,10 +233,13 @@ func (repo MemberRepository) Update(ctx context.Context, memberID int64, m membe
 	defer tx.Rollback()
 
 	err = updateMember(ctx, tx, memberID, Member{
-		FirstName:         m.FirstName,
-		LastName:          m.LastName,
-		Email:             m.Email,
-		ProfilePictureURL: m.ProfilePictureURL,
+		FirstName:              m.FirstName,
+		LastName:               m.LastName,
+		Email:                  m.Email,
+		ProfilePictureURL:      m.ProfilePicture.URL,
+		ProfilePictureVariants: m.ProfilePicture.Variants,
+		ProfilePictureBlurHash: m.ProfilePicture.BlurHash,
+		ProfilePictureColor:    m.ProfilePicture.Color,
 	})
 
 	if err != nil {
@@ -265,7 +298,15 @@ func (repo MemberRepository) Delete(ctx context.Context, memberID int64) error {
 		return err
 	}
 
-	if err := deleteMemberSession(ctx, tx, memberID); err != nil {
+	if err := deleteSessions(ctx, tx, sq.Eq{"member_id": memberID}); err != nil {
+		return err
+	}
+
+	if err := deletePasswordResets(ctx, tx, sq.Eq{"member_id": memberID}); err != nil {
+		return err
+	}
+
+	if err := deleteTwoFactor(ctx, tx, memberID); err != nil {
 		return err
 	}
 
@@ -299,12 +340,147 @@ func (repo MemberRepository) PasswordHash(ctx context.Context, memberID int64) (
 	return ph, nil
 }
 
+func (repo MemberRepository) SetPasswordHash(ctx context.Context, memberID int64, hash member.PasswordHash) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := setMemberPasswordHash(ctx, tx, memberID, hash); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo MemberRepository) SetEmailVerified(ctx context.Context, memberID int64, verifiedAt time.Time) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := setMemberTime(ctx, tx, memberID, "email_verified_at", verifiedAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo MemberRepository) SetVerificationSentAt(ctx context.Context, memberID int64, sentAt time.Time) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := setMemberTime(ctx, tx, memberID, "verification_sent_at", sentAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func setMemberTime(ctx context.Context, tx *sql.Tx, memberID int64, column string, t time.Time) error {
+	query, args, err := sq.
+		Update("member").
+		Set(column, formatTime(t)).
+		Where(sq.Eq{"id": memberID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func setMemberPasswordHash(ctx context.Context, tx *sql.Tx, memberID int64, hash []byte) error {
+	query, args, err := sq.
+		Update("member").
+		Set("password_hash", hash).
+		Where(sq.Eq{"id": memberID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func setMemberProfilePicture(ctx context.Context, tx *sql.Tx, memberID int64, img media.Image) error {
+	query, args, err := sq.
+		Update("member").
+		Set("profile_picture_url", img.URL).
+		Set("profile_picture_variants", ImageVariants(img.Variants)).
+		Set("profile_picture_blur_hash", img.BlurHash).
+		Set("profile_picture_color", img.Color).
+		Where(sq.Eq{"id": memberID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func insertMember(ctx context.Context, tx *sql.Tx, m Member) (int64, error) {
 	query, args, err := sq.
 		Insert("member").
 		Options("OR IGNORE").
-		Columns("email", "first_name", "last_name", "password_hash", "profile_picture_url").
-		Values(m.Email, m.FirstName, m.LastName, m.PasswordHash, m.ProfilePictureURL).
+		Columns(
+			"email",
+			"first_name",
+			"last_name",
+			"password_hash",
+			"profile_picture_url",
+			"profile_picture_variants",
+			"profile_picture_blur_hash",
+			"profile_picture_color",
+		).
+		Values(
+			m.Email,
+			m.FirstName,
+			m.LastName,
+			m.PasswordHash,
+			m.ProfilePictureURL,
+			m.ProfilePictureVariants,
+			m.ProfilePictureBlurHash,
+			m.ProfilePictureColor,
+		).
 		ToSql()
 
 	if err != nil {
@@ -397,8 +573,13 @@ func scanMember(s Scanner, dst *Member) error {
 		&dst.LastName,
 		&dst.Email,
 		&dst.ProfilePictureURL,
+		&dst.ProfilePictureVariants,
+		&dst.ProfilePictureBlurHash,
+		&dst.ProfilePictureColor,
 		&dst.Active,
 		&dst.PasswordHash,
+		&dst.EmailVerifiedAt,
+		&dst.VerificationSentAt,
 	)
 
 	if err != nil {
@@ -415,8 +596,13 @@ var memberBuilder = sq.
 		"member.last_name",
 		"member.email",
 		"member.profile_picture_url",
+		"member.profile_picture_variants",
+		"member.profile_picture_blur_hash",
+		"member.profile_picture_color",
 		"member.active",
 		"member.password_hash",
+		"member.email_verified_at",
+		"member.verification_sent_at",
 	).
 	From("member")
 
@@ -434,6 +620,13 @@ func listMembers(ctx context.Context, tx *sql.Tx, params QueryParams) (MemberCol
 
 			return sq.Eq{"active": "FALSE"}
 		},
+		"email_verified": func(f query.Filter) sq.Sqlizer {
+			if strings.ToUpper(f.Value) == "TRUE" {
+				return sq.NotEq{"email_verified_at": nil}
+			}
+
+			return sq.Eq{"email_verified_at": nil}
+		},
 		"first_name": func(f query.Filter) sq.Sqlizer {
 			return contains("first_name", f.Value)
 		},
@@ -485,10 +678,18 @@ func updateMember(ctx context.Context, tx *sql.Tx, memberID int64, m Member) err
 
 	if m.Email != "" {
 		builder = builder.Set("email", m.Email)
+
+		// A changed email must be verified anew.
+		builder = builder.Set("email_verified_at", sq.Expr(
+			"CASE WHEN email = ? THEN email_verified_at ELSE NULL END", m.Email,
+		))
 	}
 
 	if m.ProfilePictureURL != "" {
 		builder = builder.Set("profile_picture_url", m.ProfilePictureURL)
+		builder = builder.Set("profile_picture_variants", m.ProfilePictureVariants)
+		builder = builder.Set("profile_picture_blur_hash", m.ProfilePictureBlurHash)
+		builder = builder.Set("profile_picture_color", m.ProfilePictureColor)
 	}
 
 	query, args, err := builder.ToSql()
@@ -579,15 +780,23 @@ func deleteMemberTeams(ctx context.Context, tx *sql.Tx, memberID int64) error {
 
 func (m Member) ToInternal(teams TeamCollection) member.Member {
 	return member.Member{
-		ID:                m.ID,
-		FirstName:         m.FirstName,
-		LastName:          m.LastName,
-		Email:             m.Email,
-		PasswordHash:      m.PasswordHash,
-		ProfilePictureURL: m.ProfilePictureURL,
+		ID:           m.ID,
+		FirstName:    m.FirstName,
+		LastName:     m.LastName,
+		Email:        m.Email,
+		PasswordHash: m.PasswordHash,
+		ProfilePicture: media.Image{
+			URL:      m.ProfilePictureURL,
+			Variants: m.ProfilePictureVariants,
+			BlurHash: m.ProfilePictureBlurHash,
+			Color:    m.ProfilePictureColor,
+		},
 
 		Teams: teams.ToInternal(),
 
 		Active: m.Active,
+
+		EmailVerifiedAt:    ptrFromNullTime(m.EmailVerifiedAt),
+		VerificationSentAt: ptrFromNullTime(m.VerificationSentAt),
 	}
 }
@@ -0,0 +1,521 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+//go:embed seed.sql
+var seedSQL string
+
+var (
+	ErrInvalidMigrationName  = errors.New("Migration file names must be of format 0001_name.up.sql or 0001_name.down.sql")
+	ErrDuplicateMigration    = errors.New("Migration versions must be unique")
+	ErrMissingUpMigration    = errors.New("Migration must have an up migration")
+	ErrMissingDownMigration  = errors.New("Migration has no down migration")
+	ErrUnknownMigration      = errors.New("Database has applied a migration unknown to this version of the application")
+	ErrMigrationChecksum     = errors.New("Applied migration differs from the migration of this version of the application")
+	ErrMigrationsApplied     = errors.New("Database must not have applied migrations to be baselined")
+	ErrInvalidMigrationCount = errors.New("Amount of migrations must be a positive integer")
+)
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// A single schema migration. Migrations are applied in order of their
+// version.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Returns the checksum of the up migration, which is stored when the migration
+// is applied, so that later changes to applied migrations are detected.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// The state of a migration in the database.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Applies and reverts the schema migrations of the database, keeping track of
+// applied migrations in the schema_migration table.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+type MigratorCfgFunc func(m *Migrator) error
+
+// Creates a migrator for the migrations embedded in the application.
+func NewMigrator(db *sql.DB, cfgs ...MigratorCfgFunc) (*Migrator, error) {
+	m := &Migrator{db: db}
+
+	if err := WithMigrationFS(migrationFS, "migrations")(m); err != nil {
+		return nil, err
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Reads the migrations from the given directory of the file system instead.
+func WithMigrationFS(fsys fs.FS, dir string) MigratorCfgFunc {
+	return func(m *Migrator) error {
+		migrations, err := readMigrations(fsys, dir)
+		if err != nil {
+			return err
+		}
+
+		m.migrations = migrations
+		return nil
+	}
+}
+
+// Returns all migrations along with the time they were applied, if at all.
+func (m Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	applied, err := m.verifiedMigrations(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0)
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+
+		if a, ok := applied[migration.Version]; ok {
+			appliedAt := a.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Applies all pending migrations, each in its own transaction, returning the
+// applied migrations. Fails without applying any migration, if an applied
+// migration has been changed or is unknown.
+//
+// A database, which has no applied migrations but already has tables, is
+// assumed to have the schema of the first migration, created before migrations
+// were introduced, and is baselined at the first migration.
+func (m Migrator) Up(ctx context.Context) ([]Migration, error) {
+	// The journal mode is persistent, and cannot be changed within a
+	// transaction.
+	if _, err := m.db.ExecContext(ctx, "PRAGMA journal_mode = WAL"); err != nil {
+		return nil, err
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unmigrated := !slices.ContainsFunc(statuses, func(status MigrationStatus) bool {
+		return status.AppliedAt != nil
+	})
+
+	if unmigrated && len(m.migrations) > 0 {
+		hasSchema, err := m.hasSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasSchema {
+			if err := m.Baseline(ctx, m.migrations[0].Version); err != nil {
+				return nil, err
+			}
+
+			if statuses, err = m.Status(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	migrated := make([]Migration, 0)
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			continue
+		}
+
+		if err := m.apply(ctx, status.Migration); err != nil {
+			return migrated, fmt.Errorf("migration %04d_%s: %w", status.Version, status.Name, err)
+		}
+
+		migrated = append(migrated, status.Migration)
+	}
+
+	return migrated, nil
+}
+
+// Reverts the given amount of the most recently applied migrations, returning
+// the reverted migrations.
+func (m Migrator) Down(ctx context.Context, count int) ([]Migration, error) {
+	if count <= 0 {
+		return nil, ErrInvalidMigrationCount
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(statuses)
+
+	reverted := make([]Migration, 0)
+	for _, status := range statuses {
+		if len(reverted) >= count {
+			break
+		}
+
+		if status.AppliedAt == nil {
+			continue
+		}
+
+		if err := m.revert(ctx, status.Migration); err != nil {
+			return reverted, fmt.Errorf("migration %04d_%s: %w", status.Version, status.Name, err)
+		}
+
+		reverted = append(reverted, status.Migration)
+	}
+
+	return reverted, nil
+}
+
+// Marks all migrations up to and including the given version as applied,
+// without applying them. Used for databases, whose schema was created before
+// migrations were introduced.
+func (m Migrator) Baseline(ctx context.Context, version int) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	applied, err := m.verifiedMigrations(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if len(applied) > 0 {
+		return ErrMigrationsApplied
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+
+		if err := insertSchemaMigration(ctx, tx, migration); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Inserts the default teams and permissions. Existing rows are left untouched.
+func (m Migrator) Seed(ctx context.Context) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, seedSQL); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m Migrator) apply(ctx context.Context, migration Migration) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return err
+	}
+
+	if err := insertSchemaMigration(ctx, tx, migration); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m Migrator) revert(ctx context.Context, migration Migration) error {
+	if migration.Down == "" {
+		return ErrMissingDownMigration
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return err
+	}
+
+	query, args, err := sq.
+		Delete("schema_migration").
+		Where(sq.Eq{"version": migration.Version}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reports whether the database has any tables other than the schema_migration
+// table.
+func (m Migrator) hasSchema(ctx context.Context) (bool, error) {
+	query, args, err := sq.
+		Select("COUNT(*)").
+		From("sqlite_master").
+		Where(sq.Eq{"type": "table"}).
+		Where(sq.NotEq{"name": "schema_migration"}).
+		Where(sq.NotLike{"name": "sqlite_%"}).
+		ToSql()
+
+	if err != nil {
+		return false, err
+	}
+
+	var count int
+	if err := m.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+type SchemaMigration struct {
+	Version   int
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Returns the applied migrations by version, after verifying that each is
+// known and unchanged.
+func (m Migrator) verifiedMigrations(ctx context.Context, tx *sql.Tx) (map[int]SchemaMigration, error) {
+	applied, err := schemaMigrations(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for version, a := range applied {
+		i := slices.IndexFunc(m.migrations, func(migration Migration) bool {
+			return migration.Version == version
+		})
+
+		if i < 0 {
+			return nil, fmt.Errorf("%w: version %d", ErrUnknownMigration, version)
+		}
+
+		migration := m.migrations[i]
+		if migration.Checksum() != a.Checksum {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrMigrationChecksum, migration.Version, migration.Name)
+		}
+	}
+
+	return applied, nil
+}
+
+func schemaMigrations(ctx context.Context, tx *sql.Tx) (map[int]SchemaMigration, error) {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migration (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)
+	`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := sq.
+		Select("version", "checksum", "applied_at").
+		From("schema_migration").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	applied := make(map[int]SchemaMigration)
+
+	for rows.Next() {
+		var a SchemaMigration
+		if err := rows.Scan(&a.Version, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+
+		applied[a.Version] = a
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func insertSchemaMigration(ctx context.Context, tx *sql.Tx, migration Migration) error {
+	query, args, err := sq.
+		Insert("schema_migration").
+		Columns("version", "name", "checksum", "applied_at").
+		Values(migration.Version, migration.Name, migration.Checksum(), formatTime(time.Now())).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reads the migrations of the directory, ordered by version.
+func readMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name())
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d", ErrDuplicateMigration, version)
+		}
+
+		switch match[3] {
+		case "up":
+			migration.Up = string(content)
+		case "down":
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0)
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrMissingUpMigration, migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return a.Version - b.Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,368 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mattismoel/konnekt/internal/storage/sqlite"
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+var testMigrations = fstest.MapFS{
+	"migrations/0001_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY);")},
+	"migrations/0001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	"migrations/0002_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER PRIMARY KEY);")},
+	"migrations/0002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	type test struct {
+		// Migrations applied before the tested migrator is run.
+		applied fstest.MapFS
+		fsys    fstest.MapFS
+		wantErr error
+	}
+
+	changed := fstest.MapFS{
+		"migrations/0001_a.up.sql": {Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY, name TEXT);")},
+		"migrations/0002_b.up.sql": testMigrations["migrations/0002_b.up.sql"],
+	}
+
+	unknown := fstest.MapFS{
+		"migrations/0001_a.up.sql": testMigrations["migrations/0001_a.up.sql"],
+	}
+
+	tests := map[string]test{
+		"Fresh database":     {fsys: testMigrations},
+		"Pending migration":  {applied: unknown, fsys: testMigrations},
+		"Changed migration":  {applied: testMigrations, fsys: changed, wantErr: sqlite.ErrMigrationChecksum},
+		"Unknown migration":  {applied: testMigrations, fsys: unknown, wantErr: sqlite.ErrUnknownMigration},
+		"Already up to date": {applied: testMigrations, fsys: testMigrations},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := openTestDB(t)
+
+			if tt.applied != nil {
+				m, err := sqlite.NewMigrator(db, sqlite.WithMigrationFS(tt.applied, "migrations"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err := m.Up(ctx); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			m, err := sqlite.NewMigrator(db, sqlite.WithMigrationFS(tt.fsys, "migrations"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = m.Up(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, s := range statuses {
+				if s.AppliedAt == nil {
+					t.Fatalf("got pending migration %d, want all applied", s.Version)
+				}
+			}
+		})
+	}
+}
+
+func TestMigratorDown(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	m, err := sqlite.NewMigrator(db, sqlite.WithMigrationFS(testMigrations, "migrations"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	reverted, err := m.Down(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reverted) != 1 || reverted[0].Version != 2 {
+		t.Fatalf("got %+v, want migration 2 reverted", reverted)
+	}
+
+	if _, err := db.ExecContext(ctx, "SELECT * FROM b"); err == nil {
+		t.Fatal("got table b, want it dropped")
+	}
+
+	if _, err := db.ExecContext(ctx, "SELECT * FROM a"); err != nil {
+		t.Fatalf("got %v, want table a kept", err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("got %+v, want migration 2 reapplied", applied)
+	}
+}
+
+func TestMigratorBaseline(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	// The schema of the first migration exists, as created before migrations.
+	if _, err := db.ExecContext(ctx, "CREATE TABLE a (id INTEGER PRIMARY KEY);"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := sqlite.NewMigrator(db, sqlite.WithMigrationFS(testMigrations, "migrations"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Baseline(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("got %+v, want only migration 2 applied", applied)
+	}
+
+	if err := m.Baseline(ctx, 1); !errors.Is(err, sqlite.ErrMigrationsApplied) {
+		t.Fatalf("got %v, want %v", err, sqlite.ErrMigrationsApplied)
+	}
+}
+
+func TestMigratorExistingSchema(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	// The schema of the first migration exists, and holds data, as created
+	// before migrations.
+	_, err := db.ExecContext(ctx, "CREATE TABLE a (id INTEGER PRIMARY KEY); INSERT INTO a (id) VALUES (1);")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := sqlite.NewMigrator(db, sqlite.WithMigrationFS(testMigrations, "migrations"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("got %+v, want only migration 2 applied", applied)
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM a").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 {
+		t.Fatalf("got %d rows, want existing row kept", count)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Fatalf("got pending migration %d, want all applied", s.Version)
+		}
+	}
+}
+
+func TestReadMigrations(t *testing.T) {
+	type test struct {
+		fsys    fstest.MapFS
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Valid migrations": {fsys: testMigrations},
+		"Invalid name": {
+			fsys:    fstest.MapFS{"migrations/init.sql": {Data: []byte("SELECT 1;")}},
+			wantErr: sqlite.ErrInvalidMigrationName,
+		},
+		"Duplicate version": {
+			fsys: fstest.MapFS{
+				"migrations/0001_a.up.sql": {Data: []byte("SELECT 1;")},
+				"migrations/0001_b.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			wantErr: sqlite.ErrDuplicateMigration,
+		},
+		"Missing up migration": {
+			fsys:    fstest.MapFS{"migrations/0001_a.down.sql": {Data: []byte("SELECT 1;")}},
+			wantErr: sqlite.ErrMissingUpMigration,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := sqlite.NewMigrator(nil, sqlite.WithMigrationFS(tt.fsys, "migrations"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Applies and reverts all migrations of the application, verifying that each
+// is valid SQL, and that the seed applies to the migrated schema.
+func TestEmbeddedMigrations(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	m, err := sqlite.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if err := m.Seed(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reverted, err := m.Down(ctx, len(applied))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reverted) != len(applied) {
+		t.Fatalf("got %d reverted migrations, want %d", len(reverted), len(applied))
+	}
+}
+
+// Migrates a database, whose initial schema was created before migrations,
+// verifying that it is baselined rather than failing on existing tables.
+func TestEmbeddedMigrationsExistingSchema(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	m, err := sqlite.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.ExecContext(ctx, statuses[0].Up); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != len(statuses)-1 {
+		t.Fatalf("got %d applied migrations, want %d", len(applied), len(statuses)-1)
+	}
+}
+
+// Migrates a database, which already has an admin team, such as one created
+// before the permissions were seeded, verifying that the admin team is granted
+// the permissions of the routes added by migrations.
+func TestMigrationPermissions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	m, err := sqlite.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Down(ctx, len(applied)-1); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.ExecContext(ctx, "INSERT INTO team (name, display_name) VALUES ('admin', 'Admin')")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"view:event", "delete:storage", "view:media", "edit:media", "delete:media"} {
+		var count int
+
+		err := db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM teams_permissions
+			JOIN team ON team.id = teams_permissions.team_id
+			JOIN permission ON permission.id = teams_permissions.permission_id
+			WHERE team.name = 'admin' AND permission.name = ?`, name,
+		).Scan(&count)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 1 {
+			t.Fatalf("got %d grants of %s to admin, want 1", count, name)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+DROP TABLE landing_image;
+DROP TABLE artists_genres;
+DROP TABLE artists_socials;
+DROP TABLE genre;
+DROP TABLE social;
+DROP TABLE artist;
+DROP TABLE venue;
+DROP TABLE concert;
+DROP TABLE event;
+DROP TABLE teams_permissions;
+DROP TABLE permission;
+DROP TABLE members_teams;
+DROP TABLE team;
+DROP TABLE session;
+DROP TABLE member;
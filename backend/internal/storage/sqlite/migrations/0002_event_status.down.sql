@@ -0,0 +1,7 @@
+-- The view:event permission predates the status, and is left in place.
+ALTER TABLE event ADD COLUMN is_public BOOLEAN NOT NULL DEFAULT 'FALSE';
+
+UPDATE event SET is_public = TRUE WHERE status IN ('scheduled', 'postponed', 'cancelled');
+
+ALTER TABLE event DROP COLUMN status_notice;
+ALTER TABLE event DROP COLUMN status;
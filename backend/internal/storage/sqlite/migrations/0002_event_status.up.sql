@@ -0,0 +1,17 @@
+-- Replaces the visibility flag of events with a lifecycle status. Public
+-- events become scheduled, all others drafts.
+ALTER TABLE event ADD COLUMN status TEXT NOT NULL DEFAULT 'draft';
+ALTER TABLE event ADD COLUMN status_notice TEXT NOT NULL DEFAULT '';
+
+UPDATE event SET status = 'scheduled' WHERE is_public IN (1, 'TRUE', 'true');
+
+ALTER TABLE event DROP COLUMN is_public;
+
+-- Drafts are only shown to members allowed to view all events. The id matches
+-- that of the seed.
+INSERT OR IGNORE INTO permission (id, name, display_name, description) VALUES
+(1, 'view:event', 'View Event', 'Allows user to view event');
+
+INSERT OR IGNORE INTO teams_permissions (team_id, permission_id)
+SELECT team.id, permission.id FROM team, permission
+WHERE team.name = 'admin' AND permission.name = 'view:event';
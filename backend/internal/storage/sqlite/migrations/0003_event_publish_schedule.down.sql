@@ -0,0 +1,2 @@
+ALTER TABLE event DROP COLUMN unpublish_at;
+ALTER TABLE event DROP COLUMN publish_at;
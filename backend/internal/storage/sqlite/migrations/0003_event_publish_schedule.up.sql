@@ -0,0 +1,2 @@
+ALTER TABLE event ADD COLUMN publish_at TIMESTAMP;
+ALTER TABLE event ADD COLUMN unpublish_at TIMESTAMP;
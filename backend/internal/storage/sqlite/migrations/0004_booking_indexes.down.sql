@@ -0,0 +1,4 @@
+DROP INDEX concert_time;
+DROP INDEX concert_artist_time;
+DROP INDEX concert_event_id;
+DROP INDEX event_venue_id;
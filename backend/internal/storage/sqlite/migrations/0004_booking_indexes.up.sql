@@ -0,0 +1,5 @@
+-- Indexes used when looking up overlapping bookings of artists and venues.
+CREATE INDEX event_venue_id ON event (venue_id);
+CREATE INDEX concert_event_id ON concert (event_id);
+CREATE INDEX concert_artist_time ON concert (artist_id, from_date, to_date);
+CREATE INDEX concert_time ON concert (from_date, to_date);
@@ -0,0 +1,5 @@
+DROP INDEX event_series_id;
+
+ALTER TABLE event DROP COLUMN series_id;
+
+DROP TABLE series;
@@ -0,0 +1,11 @@
+CREATE TABLE series (
+  id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  slug TEXT UNIQUE NOT NULL,
+  description TEXT NOT NULL,
+  image_url TEXT NOT NULL DEFAULT ''
+);
+
+ALTER TABLE event ADD COLUMN series_id INTEGER REFERENCES series (id);
+
+CREATE INDEX event_series_id ON event (series_id);
@@ -0,0 +1,6 @@
+DROP INDEX event_recurrence_id;
+
+ALTER TABLE event DROP COLUMN occurrence_start;
+ALTER TABLE event DROP COLUMN recurrence_id;
+
+DROP TABLE recurrence;
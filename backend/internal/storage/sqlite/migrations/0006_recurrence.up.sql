@@ -0,0 +1,11 @@
+CREATE TABLE recurrence (
+  id INTEGER PRIMARY KEY,
+  rule TEXT NOT NULL,
+  time_zone TEXT NOT NULL,
+  exceptions TEXT NOT NULL DEFAULT ''
+);
+
+ALTER TABLE event ADD COLUMN recurrence_id INTEGER REFERENCES recurrence (id);
+ALTER TABLE event ADD COLUMN occurrence_start TIMESTAMP;
+
+CREATE INDEX event_recurrence_id ON event (recurrence_id);
@@ -0,0 +1,3 @@
+DROP TABLE venue_search;
+DROP TABLE artist_search;
+DROP TABLE event_search;
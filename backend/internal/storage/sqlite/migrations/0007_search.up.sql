@@ -0,0 +1,34 @@
+-- Full-text search indices, whose rowids are the IDs of the indexed rows.
+CREATE VIRTUAL TABLE event_search USING fts5 (
+  title,
+  description,
+  tokenize = 'porter unicode61 remove_diacritics 2'
+);
+
+CREATE VIRTUAL TABLE artist_search USING fts5 (
+  name,
+  description,
+  genres,
+  tokenize = 'porter unicode61 remove_diacritics 2'
+);
+
+CREATE VIRTUAL TABLE venue_search USING fts5 (
+  name,
+  city,
+  tokenize = 'porter unicode61 remove_diacritics 2'
+);
+
+INSERT INTO event_search (rowid, title, description)
+SELECT id, title, description FROM event;
+
+INSERT INTO artist_search (rowid, name, description, genres)
+SELECT artist.id, artist.name, artist.description, COALESCE((
+  SELECT group_concat(genre.name, ' ')
+  FROM artists_genres
+  JOIN genre ON genre.id = artists_genres.genre_id
+  WHERE artists_genres.artist_id = artist.id
+), '')
+FROM artist;
+
+INSERT INTO venue_search (rowid, name, city)
+SELECT id, name, city FROM venue;
@@ -0,0 +1,5 @@
+ALTER TABLE landing_image DROP COLUMN variants;
+ALTER TABLE member DROP COLUMN profile_picture_variants;
+ALTER TABLE series DROP COLUMN image_variants;
+ALTER TABLE event DROP COLUMN image_variants;
+ALTER TABLE artist DROP COLUMN image_variants;
@@ -0,0 +1,5 @@
+ALTER TABLE artist ADD COLUMN image_variants TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE event ADD COLUMN image_variants TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE series ADD COLUMN image_variants TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE member ADD COLUMN profile_picture_variants TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE landing_image ADD COLUMN variants TEXT NOT NULL DEFAULT '[]';
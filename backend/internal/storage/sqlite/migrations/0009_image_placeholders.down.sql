@@ -0,0 +1,10 @@
+ALTER TABLE landing_image DROP COLUMN color;
+ALTER TABLE landing_image DROP COLUMN blur_hash;
+ALTER TABLE member DROP COLUMN profile_picture_color;
+ALTER TABLE member DROP COLUMN profile_picture_blur_hash;
+ALTER TABLE series DROP COLUMN image_color;
+ALTER TABLE series DROP COLUMN image_blur_hash;
+ALTER TABLE event DROP COLUMN image_color;
+ALTER TABLE event DROP COLUMN image_blur_hash;
+ALTER TABLE artist DROP COLUMN image_color;
+ALTER TABLE artist DROP COLUMN image_blur_hash;
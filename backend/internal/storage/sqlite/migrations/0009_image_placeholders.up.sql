@@ -0,0 +1,10 @@
+ALTER TABLE artist ADD COLUMN image_blur_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE artist ADD COLUMN image_color TEXT NOT NULL DEFAULT '';
+ALTER TABLE event ADD COLUMN image_blur_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE event ADD COLUMN image_color TEXT NOT NULL DEFAULT '';
+ALTER TABLE series ADD COLUMN image_blur_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE series ADD COLUMN image_color TEXT NOT NULL DEFAULT '';
+ALTER TABLE member ADD COLUMN profile_picture_blur_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE member ADD COLUMN profile_picture_color TEXT NOT NULL DEFAULT '';
+ALTER TABLE landing_image ADD COLUMN blur_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE landing_image ADD COLUMN color TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE event DROP COLUMN image_crops;
+ALTER TABLE event DROP COLUMN image_framing;
+ALTER TABLE artist DROP COLUMN image_crops;
+ALTER TABLE artist DROP COLUMN image_framing;
@@ -0,0 +1,4 @@
+ALTER TABLE artist ADD COLUMN image_framing TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE artist ADD COLUMN image_crops TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE event ADD COLUMN image_framing TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE event ADD COLUMN image_crops TEXT NOT NULL DEFAULT '[]';
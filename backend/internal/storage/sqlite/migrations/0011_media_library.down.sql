@@ -0,0 +1,19 @@
+DELETE FROM teams_permissions WHERE permission_id IN (
+  SELECT id FROM permission
+  WHERE name IN ('view:media', 'edit:media', 'delete:media')
+);
+
+DELETE FROM permission
+WHERE name IN ('view:media', 'edit:media', 'delete:media');
+
+DROP INDEX landing_image_media_id;
+DROP INDEX event_image_media_id;
+DROP INDEX artist_image_media_id;
+
+ALTER TABLE landing_image DROP COLUMN media_id;
+ALTER TABLE event DROP COLUMN image_media_id;
+ALTER TABLE artist DROP COLUMN image_media_id;
+
+DROP INDEX media_tag_tag;
+DROP TABLE media_tag;
+DROP TABLE media;
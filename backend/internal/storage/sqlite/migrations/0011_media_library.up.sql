@@ -0,0 +1,39 @@
+CREATE TABLE media (
+  id INTEGER PRIMARY KEY,
+  title TEXT NOT NULL,
+  alt_text TEXT NOT NULL DEFAULT '',
+  credit TEXT NOT NULL DEFAULT '',
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  image_url TEXT NOT NULL,
+  image_variants TEXT NOT NULL DEFAULT '[]',
+  image_blur_hash TEXT NOT NULL DEFAULT '',
+  image_color TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE media_tag (
+  media_id INTEGER NOT NULL REFERENCES media (id),
+  tag TEXT NOT NULL,
+  PRIMARY KEY (media_id, tag)
+);
+
+CREATE INDEX media_tag_tag ON media_tag (tag);
+
+ALTER TABLE artist ADD COLUMN image_media_id INTEGER REFERENCES media (id);
+ALTER TABLE event ADD COLUMN image_media_id INTEGER REFERENCES media (id);
+ALTER TABLE landing_image ADD COLUMN media_id INTEGER REFERENCES media (id);
+
+CREATE INDEX artist_image_media_id ON artist (image_media_id);
+CREATE INDEX event_image_media_id ON event (image_media_id);
+CREATE INDEX landing_image_media_id ON landing_image (media_id);
+
+-- The ids of the permissions match those of the seed.
+INSERT OR IGNORE INTO permission (id, name, display_name, description) VALUES
+(20, 'view:media', 'View Media', 'Allows user to view the media library'),
+(21, 'edit:media', 'Edit Media', 'Allows user to upload and edit media'),
+(22, 'delete:media', 'Delete Media', 'Allows user to delete media');
+
+INSERT OR IGNORE INTO teams_permissions (team_id, permission_id)
+SELECT team.id, permission.id FROM team, permission
+WHERE team.name = 'admin' AND permission.name IN ('view:media', 'edit:media', 'delete:media');
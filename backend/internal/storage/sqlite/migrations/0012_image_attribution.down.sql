@@ -0,0 +1,9 @@
+ALTER TABLE landing_image DROP COLUMN license;
+ALTER TABLE landing_image DROP COLUMN credit;
+ALTER TABLE landing_image DROP COLUMN alt_text;
+ALTER TABLE event DROP COLUMN image_license;
+ALTER TABLE event DROP COLUMN image_credit;
+ALTER TABLE event DROP COLUMN image_alt_text;
+ALTER TABLE artist DROP COLUMN image_license;
+ALTER TABLE artist DROP COLUMN image_credit;
+ALTER TABLE artist DROP COLUMN image_alt_text;
@@ -0,0 +1,9 @@
+ALTER TABLE artist ADD COLUMN image_alt_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE artist ADD COLUMN image_credit TEXT NOT NULL DEFAULT '';
+ALTER TABLE artist ADD COLUMN image_license TEXT NOT NULL DEFAULT '';
+ALTER TABLE event ADD COLUMN image_alt_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE event ADD COLUMN image_credit TEXT NOT NULL DEFAULT '';
+ALTER TABLE event ADD COLUMN image_license TEXT NOT NULL DEFAULT '';
+ALTER TABLE landing_image ADD COLUMN alt_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE landing_image ADD COLUMN credit TEXT NOT NULL DEFAULT '';
+ALTER TABLE landing_image ADD COLUMN license TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE venue DROP COLUMN updated_at;
+ALTER TABLE artist DROP COLUMN updated_at;
+ALTER TABLE event DROP COLUMN updated_at;
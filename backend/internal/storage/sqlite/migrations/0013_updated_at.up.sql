@@ -0,0 +1,8 @@
+ALTER TABLE event ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT '1970-01-01T00:00:00Z';
+ALTER TABLE artist ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT '1970-01-01T00:00:00Z';
+ALTER TABLE venue ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT '1970-01-01T00:00:00Z';
+
+-- Existing records are considered updated, when the column was introduced.
+UPDATE event SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now');
+UPDATE artist SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now');
+UPDATE venue SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now');
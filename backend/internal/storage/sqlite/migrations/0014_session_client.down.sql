@@ -0,0 +1,6 @@
+DROP INDEX session_member_id;
+
+ALTER TABLE session DROP COLUMN last_seen_at;
+ALTER TABLE session DROP COLUMN created_at;
+ALTER TABLE session DROP COLUMN ip_address;
+ALTER TABLE session DROP COLUMN user_agent;
@@ -0,0 +1,12 @@
+ALTER TABLE session ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE session ADD COLUMN ip_address TEXT NOT NULL DEFAULT '';
+ALTER TABLE session ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT '1970-01-01T00:00:00Z';
+ALTER TABLE session ADD COLUMN last_seen_at TIMESTAMP NOT NULL DEFAULT '1970-01-01T00:00:00Z';
+
+-- The creation of existing sessions is unknown, so they are considered
+-- created and seen, when the columns were introduced.
+UPDATE session SET
+  created_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now'),
+  last_seen_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now');
+
+CREATE INDEX session_member_id ON session (member_id);
@@ -0,0 +1,2 @@
+DROP INDEX password_reset_member_id;
+DROP TABLE password_reset;
@@ -0,0 +1,7 @@
+CREATE TABLE password_reset (
+  id TEXT PRIMARY KEY,
+  member_id INTEGER NOT NULL,
+  expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX password_reset_member_id ON password_reset (member_id);
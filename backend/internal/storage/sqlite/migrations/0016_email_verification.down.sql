@@ -0,0 +1,2 @@
+ALTER TABLE member DROP COLUMN verification_sent_at;
+ALTER TABLE member DROP COLUMN email_verified_at;
@@ -0,0 +1,5 @@
+ALTER TABLE member ADD COLUMN email_verified_at TIMESTAMP;
+ALTER TABLE member ADD COLUMN verification_sent_at TIMESTAMP;
+
+-- Existing members registered before emails were verified, and are trusted.
+UPDATE member SET email_verified_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now');
@@ -0,0 +1,2 @@
+DROP TABLE invitation_team;
+DROP TABLE invitation;
@@ -0,0 +1,14 @@
+CREATE TABLE invitation (
+  id INTEGER PRIMARY KEY,
+  email TEXT UNIQUE NOT NULL,
+  token_hash TEXT UNIQUE NOT NULL,
+  invited_by INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE invitation_team (
+  invitation_id INTEGER NOT NULL REFERENCES invitation (id),
+  team_id INTEGER NOT NULL,
+  PRIMARY KEY (invitation_id, team_id)
+);
@@ -0,0 +1,8 @@
+DROP TABLE recovery_code;
+DROP TABLE two_factor;
+
+-- Pending sessions would be valid without the pending column.
+DELETE FROM session WHERE pending != '';
+
+ALTER TABLE session DROP COLUMN failed_attempts;
+ALTER TABLE session DROP COLUMN pending;
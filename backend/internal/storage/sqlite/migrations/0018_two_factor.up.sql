@@ -0,0 +1,15 @@
+ALTER TABLE session ADD COLUMN pending TEXT NOT NULL DEFAULT '';
+ALTER TABLE session ADD COLUMN failed_attempts INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE two_factor (
+  member_id INTEGER PRIMARY KEY,
+  secret TEXT NOT NULL,
+  confirmed_at TIMESTAMP,
+  last_period INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE recovery_code (
+  member_id INTEGER NOT NULL,
+  code_hash TEXT NOT NULL,
+  PRIMARY KEY (member_id, code_hash)
+);
@@ -0,0 +1,5 @@
+DELETE FROM teams_permissions WHERE permission_id IN (
+  SELECT id FROM permission WHERE name = 'delete:storage'
+);
+
+DELETE FROM permission WHERE name = 'delete:storage';
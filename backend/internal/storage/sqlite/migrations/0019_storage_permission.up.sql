@@ -0,0 +1,7 @@
+-- The id of the permission matches that of the seed.
+INSERT OR IGNORE INTO permission (id, name, display_name, description) VALUES
+(19, 'delete:storage', 'Delete Storage', 'Allows user to delete orphaned objects');
+
+INSERT OR IGNORE INTO teams_permissions (team_id, permission_id)
+SELECT team.id, permission.id FROM team, permission
+WHERE team.name = 'admin' AND permission.name = 'delete:storage';
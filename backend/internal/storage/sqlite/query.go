@@ -23,6 +23,8 @@ type QueryParams struct {
 	OrderBy map[string]query.Order
 	// The filters to apply to the query.
 	Filters query.FilterCollection
+	// The free text to search for.
+	Search string
 }
 
 // A SQLite query builder instance.
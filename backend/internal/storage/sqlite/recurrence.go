@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/event"
+)
+
+type Recurrence struct {
+	ID         int64
+	Rule       string
+	TimeZone   string
+	Exceptions string
+}
+
+func RecurrenceFromInternal(r event.Recurrence) Recurrence {
+	return Recurrence{
+		ID:         r.ID,
+		Rule:       r.Rule.String(),
+		TimeZone:   r.TimeZone,
+		Exceptions: strings.Join(r.Exceptions, ","),
+	}
+}
+
+func (r Recurrence) ToInternal() (event.Recurrence, error) {
+	rule, err := event.ParseRule(r.Rule)
+	if err != nil {
+		return event.Recurrence{}, err
+	}
+
+	exceptions := make([]string, 0)
+	if r.Exceptions != "" {
+		exceptions = strings.Split(r.Exceptions, ",")
+	}
+
+	return event.Recurrence{
+		ID:         r.ID,
+		Rule:       rule,
+		TimeZone:   r.TimeZone,
+		Exceptions: exceptions,
+	}, nil
+}
+
+// Inserts the recurrence along with all of its events in a single
+// transaction, so that a recurrence is never partially materialised.
+func (repo EventRepository) InsertRecurring(ctx context.Context, r event.Recurrence, events []event.Event) ([]int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	recurrenceID, err := insertRecurrence(ctx, tx, RecurrenceFromInternal(r))
+	if err != nil {
+		return nil, err
+	}
+
+	eventIDs := make([]int64, 0)
+	for _, e := range events {
+		dbEvent := EventFromInternal(e)
+		dbEvent.RecurrenceID = sql.NullInt64{Int64: recurrenceID, Valid: true}
+		dbEvent.OccurrenceStart = nullTimeFromPtr(e.OccurrenceStart)
+
+		eventID, err := insertEventWithConcerts(ctx, tx, dbEvent, e.Concerts)
+		if err != nil {
+			return nil, err
+		}
+
+		eventIDs = append(eventIDs, eventID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return eventIDs, nil
+}
+
+// Returns the recurrence of the event, or nil if the event is not part of a
+// recurrence.
+func eventRecurrence(ctx context.Context, tx *sql.Tx, e Event) (*event.Recurrence, error) {
+	if !e.RecurrenceID.Valid {
+		return nil, nil
+	}
+
+	dbRecurrence, err := recurrenceByID(ctx, tx, e.RecurrenceID.Int64)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := dbRecurrence.ToInternal()
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func recurrenceByID(ctx context.Context, tx *sql.Tx, recurrenceID int64) (Recurrence, error) {
+	query, args, err := sq.
+		Select("id", "rule", "time_zone", "exceptions").
+		From("recurrence").
+		Where(sq.Eq{"id": recurrenceID}).
+		ToSql()
+
+	if err != nil {
+		return Recurrence{}, err
+	}
+
+	var r Recurrence
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&r.ID, &r.Rule, &r.TimeZone, &r.Exceptions)
+	if err != nil {
+		return Recurrence{}, err
+	}
+
+	return r, nil
+}
+
+func insertRecurrence(ctx context.Context, tx *sql.Tx, r Recurrence) (int64, error) {
+	query, args, err := sq.
+		Insert("recurrence").
+		Columns("rule", "time_zone", "exceptions").
+		Values(r.Rule, r.TimeZone, r.Exceptions).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	recurrenceID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return recurrenceID, nil
+}
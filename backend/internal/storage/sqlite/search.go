@@ -0,0 +1,266 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/search"
+)
+
+// The amount of tokens of search result snippets.
+const SNIPPET_TOKEN_COUNT = 16
+
+var _ search.Repository = (*SearchRepository)(nil)
+
+// An FTS5 table indexing the text columns of an entity table. Rows of the
+// index have the same rowid as the ID of their entity.
+type searchTable struct {
+	table       string
+	titleColumn string
+	columns     []string
+
+	// Selects the ID followed by the indexed columns of the entities.
+	source sq.SelectBuilder
+}
+
+func (t searchTable) name() string {
+	return t.table + "_search"
+}
+
+var searchTables = map[search.Kind]searchTable{
+	search.KindEvent: {
+		table:       "event",
+		titleColumn: "title",
+		columns:     []string{"title", "description"},
+		source:      sq.Select("event.id", "event.title", "event.description").From("event"),
+	},
+	search.KindArtist: {
+		table:       "artist",
+		titleColumn: "name",
+		columns:     []string{"name", "description", "genres"},
+		source: sq.Select(
+			"artist.id",
+			"artist.name",
+			"artist.description",
+			`COALESCE((
+				SELECT group_concat(genre.name, ' ')
+				FROM artists_genres
+				JOIN genre ON genre.id = artists_genres.genre_id
+				WHERE artists_genres.artist_id = artist.id
+			), '')`,
+		).From("artist"),
+	},
+	search.KindVenue: {
+		table:       "venue",
+		titleColumn: "name",
+		columns:     []string{"name", "city"},
+		source:      sq.Select("venue.id", "venue.name", "venue.city").From("venue"),
+	},
+}
+
+type SearchRepository struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) (*SearchRepository, error) {
+	return &SearchRepository{
+		db: db,
+	}, nil
+}
+
+func (repo SearchRepository) Search(ctx context.Context, q search.Query) ([]search.Result, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	results := make([]search.Result, 0)
+	for _, kind := range q.Kinds {
+		kindResults, err := searchKind(ctx, tx, kind, q)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, kindResults...)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (repo SearchRepository) Reindex(ctx context.Context) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, kind := range search.Kinds() {
+		t := searchTables[kind]
+
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", t.name())); err != nil {
+			return err
+		}
+
+		query, args, err := sq.
+			Insert(t.name()).
+			Columns(append([]string{"rowid"}, t.columns...)...).
+			Select(t.source).
+			ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func searchKind(ctx context.Context, tx *sql.Tx, kind search.Kind, q search.Query) ([]search.Result, error) {
+	t, ok := searchTables[kind]
+	if !ok {
+		return nil, search.ErrInvalidKind
+	}
+
+	builder := sq.
+		Select(
+			fmt.Sprintf("%s.id", t.table),
+			fmt.Sprintf("%s.%s", t.table, t.titleColumn),
+		).
+		Column(
+			sq.Expr(
+				fmt.Sprintf("snippet(%s, -1, ?, ?, '…', %d)", t.name(), SNIPPET_TOKEN_COUNT),
+				search.HIGHLIGHT_START, search.HIGHLIGHT_END,
+			),
+		).
+		Column(fmt.Sprintf("%s.rank", t.name())).
+		From(t.name()).
+		Join(fmt.Sprintf("%s ON %s.id = %s.rowid", t.table, t.table, t.name())).
+		Where(fmt.Sprintf("%s MATCH ?", t.name()), matchExpression(q.Terms)).
+		OrderBy(fmt.Sprintf("%s.rank", t.name())).
+		Limit(uint64(q.Limit))
+
+	if kind == search.KindEvent && q.PublicOnly {
+		builder = builder.Where(isPublicEvent(q.Now))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	results := make([]search.Result, 0)
+
+	for rows.Next() {
+		r := search.Result{Kind: kind}
+
+		var snippet string
+		if err := rows.Scan(&r.ID, &r.Title, &snippet, &r.Rank); err != nil {
+			return nil, err
+		}
+
+		r.Snippet = search.Highlight(snippet)
+		results = append(results, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Restricts the query to entities matching the search text. The query is
+// left unchanged, if the text has no words.
+func withSearch(b sq.SelectBuilder, kind search.Kind, text string) sq.SelectBuilder {
+	terms := search.Terms(text)
+	if len(terms) <= 0 {
+		return b
+	}
+
+	t := searchTables[kind]
+
+	return b.Where(
+		fmt.Sprintf("%s.id IN (SELECT rowid FROM %s WHERE %s MATCH ?)", t.table, t.name(), t.name()),
+		matchExpression(terms),
+	)
+}
+
+// Returns an FTS5 query matching all terms by prefix. Terms are quoted, so
+// that they are never interpreted as query syntax.
+func matchExpression(terms []string) string {
+	phrases := make([]string, 0)
+	for _, term := range terms {
+		phrases = append(phrases, fmt.Sprintf(`"%s"*`, strings.ReplaceAll(term, `"`, `""`)))
+	}
+
+	return strings.Join(phrases, " ")
+}
+
+// Updates the search index entry of the entity with the given ID. Must be
+// called within the transaction changing the entity, after the change.
+func indexSearch(ctx context.Context, tx *sql.Tx, kind search.Kind, id int64) error {
+	if err := unindexSearch(ctx, tx, kind, id); err != nil {
+		return err
+	}
+
+	t := searchTables[kind]
+
+	query, args, err := sq.
+		Insert(t.name()).
+		Columns(append([]string{"rowid"}, t.columns...)...).
+		Select(t.source.Where(sq.Eq{t.table + ".id": id})).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Removes the search index entry of the entity with the given ID.
+func unindexSearch(ctx context.Context, tx *sql.Tx, kind search.Kind, id int64) error {
+	query, args, err := sq.
+		Delete(searchTables[kind].name()).
+		Where(sq.Eq{"rowid": id}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
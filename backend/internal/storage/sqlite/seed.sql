@@ -1,5 +1,8 @@
+-- Seeds the default teams and permissions. Existing rows are left untouched,
+-- so the seed may be applied repeatedly.
+
 -- INSERT TEAMS --
-INSERT INTO team (id, name, display_name, description) VALUES
+INSERT OR IGNORE INTO team (id, name, display_name, description) VALUES
 (1, 'event-management', 'Event Management', 'Handles event creation and scheduling'),
 (2, 'booking', 'Booking', 'Handles booking of artists and venues'),
 (3, 'public-relations', 'Public Relations', 'Manages public-facing content'),
@@ -8,7 +11,7 @@ INSERT INTO team (id, name, display_name, description) VALUES
 
 
 -- INSERT PERMISSIONS --
-INSERT INTO permission (id, name, display_name, description) VALUES
+INSERT OR IGNORE INTO permission (id, name, display_name, description) VALUES
 (1, 'view:event', 'View Event', 'Allows user to view event'),
 (2, 'edit:event', 'Edit Event', 'Allows user to edit event'),
 (3, 'delete:event', 'Delete Event', 'Allows user to delete event'),
@@ -31,31 +34,37 @@ INSERT INTO permission (id, name, display_name, description) VALUES
 
 (16, 'view:team', 'View Team', 'Allows user to view team'),
 (17, 'edit:team', 'Edit Team', 'Allows user to edit team'),
-(18, 'delete:team', 'Delete Team', 'Allows user to delete team');
+(18, 'delete:team', 'Delete Team', 'Allows user to delete team'),
+
+(19, 'delete:storage', 'Delete Storage', 'Allows user to delete orphaned objects'),
+
+(20, 'view:media', 'View Media', 'Allows user to view the media library'),
+(21, 'edit:media', 'Edit Media', 'Allows user to upload and edit media'),
+(22, 'delete:media', 'Delete Media', 'Allows user to delete media');
 
 
 -- ASSIGN PERMISSIONS TO TEAMS --
-INSERT INTO teams_permissions (team_id, permission_id)
+INSERT OR IGNORE INTO teams_permissions (team_id, permission_id)
 SELECT 4, id FROM permission;
 
 -- Member (team_id 5): only view permissions
-INSERT INTO teams_permissions (team_id, permission_id)
+INSERT OR IGNORE INTO teams_permissions (team_id, permission_id)
 SELECT 5, id FROM permission WHERE name LIKE 'view:%';
 
 -- Event Management (team_id 1): view/edit event & concert
-INSERT INTO teams_permissions (team_id, permission_id)
+INSERT OR IGNORE INTO teams_permissions (team_id, permission_id)
 SELECT 1, id FROM permission WHERE name IN (
   'view:event', 'edit:event', 'view:concert', 'edit:concert'
 );
 
 -- Booking (team_id 2): view/edit venue & artist
-INSERT INTO teams_permissions (team_id, permission_id)
+INSERT OR IGNORE INTO teams_permissions (team_id, permission_id)
 SELECT 2, id FROM permission WHERE name IN (
   'view:venue', 'edit:venue', 'view:artist', 'edit:artist'
 );
 
 -- Public Relations (team_id 3): view event & artist
-INSERT INTO teams_permissions (team_id, permission_id)
+INSERT OR IGNORE INTO teams_permissions (team_id, permission_id)
 SELECT 3, id FROM permission WHERE name IN (
   'view:event', 'view:artist'
 );
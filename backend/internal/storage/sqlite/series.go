@@ -0,0 +1,486 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/media"
+	"github.com/mattismoel/konnekt/internal/domain/series"
+	"github.com/mattismoel/konnekt/internal/query"
+)
+
+type Series struct {
+	ID            int64
+	Name          string
+	Slug          string
+	Description   string
+	ImageURL      string
+	ImageVariants ImageVariants
+	ImageBlurHash string
+	ImageColor    string
+}
+
+func SeriesFromInternal(s series.Series) Series {
+	return Series{
+		ID:            s.ID,
+		Name:          s.Name,
+		Slug:          s.Slug,
+		Description:   s.Description,
+		ImageURL:      s.Image.URL,
+		ImageVariants: s.Image.Variants,
+		ImageBlurHash: s.Image.BlurHash,
+		ImageColor:    s.Image.Color,
+	}
+}
+
+func (s Series) ToInternal() series.Series {
+	return series.Series{
+		ID:          s.ID,
+		Name:        s.Name,
+		Slug:        s.Slug,
+		Description: s.Description,
+		Image: media.Image{
+			URL:      s.ImageURL,
+			Variants: s.ImageVariants,
+			BlurHash: s.ImageBlurHash,
+			Color:    s.ImageColor,
+		},
+	}
+}
+
+var _ series.Repository = (*SeriesRepository)(nil)
+
+type SeriesRepository struct {
+	db *sql.DB
+}
+
+func NewSeriesRepository(db *sql.DB) (*SeriesRepository, error) {
+	return &SeriesRepository{
+		db: db,
+	}, nil
+}
+
+func (repo SeriesRepository) List(ctx context.Context, q query.ListQuery) (query.ListResult[series.Series], error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return query.ListResult[series.Series]{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbSeries, err := listSeries(ctx, tx, QueryParams{
+		Offset:  q.Offset(),
+		Limit:   q.Limit,
+		OrderBy: q.OrderBy,
+		Filters: q.Filters,
+	})
+
+	if err != nil {
+		return query.ListResult[series.Series]{}, err
+	}
+
+	totalCount, err := count(ctx, tx, "series")
+	if err != nil {
+		return query.ListResult[series.Series]{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return query.ListResult[series.Series]{}, err
+	}
+
+	records := make([]series.Series, 0)
+	for _, s := range dbSeries {
+		records = append(records, s.ToInternal())
+	}
+
+	return query.ListResult[series.Series]{
+		Page:       q.Page,
+		PerPage:    q.PerPage,
+		TotalCount: totalCount,
+		PageCount:  q.PageCount(totalCount),
+		Records:    records,
+	}, nil
+}
+
+func (repo SeriesRepository) ByID(ctx context.Context, seriesID int64) (series.Series, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbSeries, err := seriesByID(ctx, tx, seriesID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return series.Series{}, series.ErrNoExist
+		}
+
+		return series.Series{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return series.Series{}, err
+	}
+
+	return dbSeries.ToInternal(), nil
+}
+
+func (repo SeriesRepository) BySlug(ctx context.Context, slug string) (series.Series, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return series.Series{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbSeries, err := seriesBySlug(ctx, tx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return series.Series{}, series.ErrNoExist
+		}
+
+		return series.Series{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return series.Series{}, err
+	}
+
+	return dbSeries.ToInternal(), nil
+}
+
+func (repo SeriesRepository) Insert(ctx context.Context, s series.Series) (int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	seriesID, err := insertSeries(ctx, tx, SeriesFromInternal(s))
+	if err != nil {
+		if errors.Is(err, ErrAlreadyExists) {
+			return 0, series.ErrSlugTaken
+		}
+
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return seriesID, nil
+}
+
+func (repo SeriesRepository) Update(ctx context.Context, seriesID int64, s series.Series) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = updateSeries(ctx, tx, seriesID, SeriesFromInternal(s))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return series.ErrNoExist
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo SeriesRepository) SetImage(ctx context.Context, seriesID int64, img media.Image) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = setSeriesImage(ctx, tx, seriesID, img)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo SeriesRepository) Delete(ctx context.Context, seriesID int64) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// Events outlive their series, and are simply detached from it.
+	err = detachSeriesEvents(ctx, tx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	err = deleteSeries(ctx, tx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var seriesBuilder = sq.
+	Select(
+		"series.id",
+		"series.name",
+		"series.slug",
+		"series.description",
+		"series.image_url",
+		"series.image_variants",
+		"series.image_blur_hash",
+		"series.image_color",
+	).
+	From("series")
+
+func scanSeries(s Scanner, dst *Series) error {
+	err := s.Scan(
+		&dst.ID,
+		&dst.Name,
+		&dst.Slug,
+		&dst.Description,
+		&dst.ImageURL,
+		&dst.ImageVariants,
+		&dst.ImageBlurHash,
+		&dst.ImageColor,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func listSeries(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Series, error) {
+	builder := seriesBuilder
+
+	builder = withFiltering(builder, params.Filters, map[string]filterFunc{
+		"name": func(f query.Filter) sq.Sqlizer {
+			return contains("series.name", f.Value)
+		},
+	})
+
+	builder = withOrdering(builder, params.OrderBy, "name", "series")
+	builder = withPagination(builder, params)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	seriesList := make([]Series, 0)
+	for rows.Next() {
+		var s Series
+		if err := scanSeries(rows, &s); err != nil {
+			return nil, err
+		}
+
+		seriesList = append(seriesList, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return seriesList, nil
+}
+
+func seriesByID(ctx context.Context, tx *sql.Tx, seriesID int64) (Series, error) {
+	query, args, err := seriesBuilder.
+		Where(sq.Eq{"series.id": seriesID}).
+		ToSql()
+
+	if err != nil {
+		return Series{}, err
+	}
+
+	var s Series
+	if err := scanSeries(tx.QueryRowContext(ctx, query, args...), &s); err != nil {
+		return Series{}, err
+	}
+
+	return s, nil
+}
+
+func seriesBySlug(ctx context.Context, tx *sql.Tx, slug string) (Series, error) {
+	query, args, err := seriesBuilder.
+		Where(sq.Eq{"series.slug": slug}).
+		ToSql()
+
+	if err != nil {
+		return Series{}, err
+	}
+
+	var s Series
+	if err := scanSeries(tx.QueryRowContext(ctx, query, args...), &s); err != nil {
+		return Series{}, err
+	}
+
+	return s, nil
+}
+
+func insertSeries(ctx context.Context, tx *sql.Tx, s Series) (int64, error) {
+	query, args, err := sq.
+		Insert("series").
+		Options("OR IGNORE").
+		Columns("name", "slug", "description", "image_url", "image_variants", "image_blur_hash", "image_color").
+		Values(s.Name, s.Slug, s.Description, s.ImageURL, s.ImageVariants, s.ImageBlurHash, s.ImageColor).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected <= 0 {
+		return 0, ErrAlreadyExists
+	}
+
+	seriesID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return seriesID, nil
+}
+
+func setSeriesImage(ctx context.Context, tx *sql.Tx, seriesID int64, img media.Image) error {
+	query, args, err := sq.
+		Update("series").
+		Set("image_url", img.URL).
+		Set("image_variants", ImageVariants(img.Variants)).
+		Set("image_blur_hash", img.BlurHash).
+		Set("image_color", img.Color).
+		Where(sq.Eq{"id": seriesID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func updateSeries(ctx context.Context, tx *sql.Tx, seriesID int64, s Series) error {
+	builder := sq.
+		Update("series").
+		Set("name", s.Name).
+		Set("slug", s.Slug).
+		Set("description", s.Description).
+		Where(sq.Eq{"id": seriesID})
+
+	if s.ImageURL != "" {
+		builder = builder.Set("image_url", s.ImageURL)
+		builder = builder.Set("image_variants", s.ImageVariants)
+		builder = builder.Set("image_blur_hash", s.ImageBlurHash)
+		builder = builder.Set("image_color", s.ImageColor)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected <= 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func detachSeriesEvents(ctx context.Context, tx *sql.Tx, seriesID int64) error {
+	query, args, err := sq.
+		Update("event").
+		Set("series_id", nil).
+		Set("updated_at", formatTime(time.Now())).
+		Where(sq.Eq{"series_id": seriesID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func deleteSeries(ctx context.Context, tx *sql.Tx, seriesID int64) error {
+	query, args, err := sq.
+		Delete("series").
+		Where(sq.Eq{"id": seriesID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattismoel/konnekt/internal/query"
@@ -87,3 +88,35 @@ func withFiltering(b sq.SelectBuilder, fc query.FilterCollection, fm map[string]
 func contains(column string, value any) sq.Like {
 	return sq.Like{column: fmt.Sprintf("%%%s%%", value)}
 }
+
+// Formats a time as stored in the database. Times are stored as RFC 3339 UTC
+// strings, so that they compare lexicographically.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Formats a nullable time as stored in the database, returning nil for NULL.
+func formatNullTime(t sql.NullTime) any {
+	if !t.Valid {
+		return nil
+	}
+
+	return formatTime(t.Time)
+}
+
+func nullTimeFromPtr(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func ptrFromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	utc := t.Time.UTC()
+	return &utc
+}
@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattismoel/konnekt/internal/domain/team"
@@ -121,6 +122,10 @@ func (repo TeamRepository) ByID(ctx context.Context, id int64) (team.Team, error
 
 	dbTeam, err := teamByID(ctx, tx, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return team.Team{}, team.ErrTeamNotFound
+		}
+
 		return team.Team{}, err
 	}
 
@@ -0,0 +1,345 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/auth"
+)
+
+type TwoFactor struct {
+	MemberID    int64
+	Secret      string
+	ConfirmedAt sql.NullTime
+	LastPeriod  int64
+}
+
+func TwoFactorFromInternal(tf auth.TwoFactor) TwoFactor {
+	return TwoFactor{
+		MemberID:    tf.MemberID,
+		Secret:      string(tf.Secret),
+		ConfirmedAt: nullTimeFromPtr(tf.ConfirmedAt),
+		LastPeriod:  tf.LastPeriod,
+	}
+}
+
+func (tf TwoFactor) ToInternal() auth.TwoFactor {
+	return auth.TwoFactor{
+		MemberID:    tf.MemberID,
+		Secret:      auth.TOTPSecret(tf.Secret),
+		ConfirmedAt: ptrFromNullTime(tf.ConfirmedAt),
+		LastPeriod:  tf.LastPeriod,
+	}
+}
+
+func (repo AuthRepository) TwoFactor(ctx context.Context, memberID int64) (auth.TwoFactor, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return auth.TwoFactor{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbTwoFactor, err := twoFactorByMemberID(ctx, tx, memberID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.TwoFactor{}, auth.ErrTwoFactorNotEnrolled
+		}
+
+		return auth.TwoFactor{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return auth.TwoFactor{}, err
+	}
+
+	return dbTwoFactor.ToInternal(), nil
+}
+
+func (repo AuthRepository) SetTwoFactor(ctx context.Context, tf auth.TwoFactor) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := upsertTwoFactor(ctx, tx, TwoFactorFromInternal(tf)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo AuthRepository) UseTwoFactorPeriod(ctx context.Context, memberID int64, period int64) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// The period is only advanced, so that of two concurrent requests with the
+	// same code, only one succeeds.
+	query, args, err := sq.
+		Update("two_factor").
+		Where(sq.Eq{"member_id": memberID}).
+		Where(sq.Lt{"last_period": period}).
+		Set("last_period", period).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return auth.ErrInvalidTwoFactorCode
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo AuthRepository) DeleteTwoFactor(ctx context.Context, memberID int64) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := deleteTwoFactor(ctx, tx, memberID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo AuthRepository) SetRecoveryCodes(ctx context.Context, memberID int64, hashes ...string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := deleteRecoveryCodes(ctx, tx, sq.Eq{"member_id": memberID}); err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		if err := insertRecoveryCode(ctx, tx, memberID, hash); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo AuthRepository) UseRecoveryCode(ctx context.Context, memberID int64, hash string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	query, args, err := sq.
+		Delete("recovery_code").
+		Where(sq.Eq{"member_id": memberID, "code_hash": hash}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return auth.ErrInvalidTwoFactorCode
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo AuthRepository) RecoveryCodeCount(ctx context.Context, memberID int64) (int, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	query, args, err := sq.
+		Select("COUNT(*)").
+		From("recovery_code").
+		Where(sq.Eq{"member_id": memberID}).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+var twoFactorBuilder = sq.
+	Select(
+		"two_factor.member_id",
+		"two_factor.secret",
+		"two_factor.confirmed_at",
+		"two_factor.last_period",
+	).
+	From("two_factor")
+
+func scanTwoFactor(s Scanner, dst *TwoFactor) error {
+	err := s.Scan(&dst.MemberID, &dst.Secret, &dst.ConfirmedAt, &dst.LastPeriod)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func twoFactorByMemberID(ctx context.Context, tx *sql.Tx, memberID int64) (TwoFactor, error) {
+	query, args, err := twoFactorBuilder.
+		Where(sq.Eq{"member_id": memberID}).
+		ToSql()
+
+	if err != nil {
+		return TwoFactor{}, err
+	}
+
+	var tf TwoFactor
+	row := tx.QueryRowContext(ctx, query, args...)
+	if err := scanTwoFactor(row, &tf); err != nil {
+		return TwoFactor{}, err
+	}
+
+	return tf, nil
+}
+
+func upsertTwoFactor(ctx context.Context, tx *sql.Tx, tf TwoFactor) error {
+	query, args, err := sq.
+		Insert("two_factor").
+		Options("OR REPLACE").
+		Columns("member_id", "secret", "confirmed_at", "last_period").
+		Values(tf.MemberID, tf.Secret, formatNullTime(tf.ConfirmedAt), tf.LastPeriod).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Deletes the two-factor authentication of the member along with the recovery
+// codes of it.
+func deleteTwoFactor(ctx context.Context, tx *sql.Tx, memberID int64) error {
+	query, args, err := sq.
+		Delete("two_factor").
+		Where(sq.Eq{"member_id": memberID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return deleteRecoveryCodes(ctx, tx, sq.Eq{"member_id": memberID})
+}
+
+func insertRecoveryCode(ctx context.Context, tx *sql.Tx, memberID int64, hash string) error {
+	query, args, err := sq.
+		Insert("recovery_code").
+		Options("OR IGNORE").
+		Columns("member_id", "code_hash").
+		Values(memberID, hash).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func deleteRecoveryCodes(ctx context.Context, tx *sql.Tx, where sq.Sqlizer) error {
+	query, args, err := sq.
+		Delete("recovery_code").
+		Where(where).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
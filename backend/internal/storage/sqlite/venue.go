@@ -3,8 +3,10 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/mattismoel/konnekt/internal/domain/search"
 	"github.com/mattismoel/konnekt/internal/domain/venue"
 	"github.com/mattismoel/konnekt/internal/query"
 )
@@ -14,6 +16,7 @@ type Venue struct {
 	Name        string
 	CountryCode string
 	City        string
+	UpdatedAt   time.Time
 }
 
 var _ venue.Repository = (*VenueRepository)(nil)
@@ -39,6 +42,7 @@ func (repo VenueRepository) List(ctx context.Context, q venue.Query) (query.List
 	dbVenues, err := listVenues(ctx, tx, QueryParams{
 		Offset: q.Offset(),
 		Limit:  q.Limit,
+		Search: q.Search,
 	})
 
 	if err != nil {
@@ -102,6 +106,14 @@ func (repo VenueRepository) Insert(ctx context.Context, v venue.Venue) (int64, e
 		CountryCode: v.CountryCode,
 	})
 
+	if err != nil {
+		return 0, err
+	}
+
+	if err := indexSearch(ctx, tx, search.KindVenue, venueID); err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
@@ -125,6 +137,10 @@ func (repo VenueRepository) Update(ctx context.Context, venueID int64, v venue.V
 		return err
 	}
 
+	if err := indexSearch(ctx, tx, search.KindVenue, venueID); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -145,6 +161,10 @@ func (repo VenueRepository) Delete(ctx context.Context, venueID int64) error {
 		return err
 	}
 
+	if err := unindexSearch(ctx, tx, search.KindVenue, venueID); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -158,11 +178,12 @@ var venueBuilder = sq.
 		"venue.name",
 		"venue.country_code",
 		"venue.city",
+		"venue.updated_at",
 	).
 	From("venue")
 
 func scanVenue(s Scanner, dst *Venue) error {
-	err := s.Scan(&dst.ID, &dst.Name, &dst.CountryCode, &dst.City)
+	err := s.Scan(&dst.ID, &dst.Name, &dst.CountryCode, &dst.City, &dst.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -173,6 +194,7 @@ func scanVenue(s Scanner, dst *Venue) error {
 func listVenues(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Venue, error) {
 	builder := venueBuilder
 
+	builder = withSearch(builder, search.KindVenue, params.Search)
 	builder = withPagination(builder, params)
 
 	query, args, err := builder.ToSql()
@@ -204,8 +226,8 @@ func listVenues(ctx context.Context, tx *sql.Tx, params QueryParams) ([]Venue, e
 func insertVenue(ctx context.Context, tx *sql.Tx, v Venue) (int64, error) {
 	query, args, err := sq.
 		Insert("venue").
-		Columns("name", "country_code", "city").
-		Values(v.Name, v.CountryCode, v.City).
+		Columns("name", "country_code", "city", "updated_at").
+		Values(v.Name, v.CountryCode, v.City, formatTime(time.Now())).
 		ToSql()
 
 	if err != nil {
@@ -262,7 +284,10 @@ func deleteVenue(ctx context.Context, tx *sql.Tx, venueID int64) error {
 }
 
 func updateVenue(ctx context.Context, tx *sql.Tx, venueID int64, v Venue) error {
-	builder := sq.Update("venue").Where(sq.Eq{"id": venueID})
+	builder := sq.
+		Update("venue").
+		Where(sq.Eq{"id": venueID}).
+		Set("updated_at", formatTime(time.Now()))
 
 	if v.Name != "" {
 		builder = builder.Set("name", v.Name)
@@ -302,5 +327,6 @@ func (v Venue) ToInternal() venue.Venue {
 		Name:        v.Name,
 		CountryCode: v.CountryCode,
 		City:        v.City,
+		UpdatedAt:   v.UpdatedAt,
 	}
 }
@@ -0,0 +1,179 @@
+// Package upload guards the image uploads of requests, rejecting uploads of
+// excessive size, unsupported types and excessive dimensions before they are
+// decoded.
+package upload
+
+import (
+	"errors"
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+
+	// Registers the supported formats with image.DecodeConfig.
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	DEFAULT_MAX_BYTES = 20 << 20
+
+	// Decoded images take four bytes per pixel, so the default allows roughly
+	// 200 MB of pixels.
+	DEFAULT_MAX_PIXELS = 50_000_000
+)
+
+// The content types of accepted images, as sniffed by http.DetectContentType.
+var DefaultImageTypes = []string{"image/jpeg", "image/png"}
+
+var (
+	ErrTooLarge        = errors.New("Upload is too large")
+	ErrTooManyPixels   = errors.New("Image dimensions are too large")
+	ErrUnsupportedType = errors.New("Image type is not supported")
+	ErrInvalidImage    = errors.New("Image must be a valid image")
+
+	ErrInvalidMaxBytes  = errors.New("Maximum upload size must be positive")
+	ErrInvalidMaxPixels = errors.New("Maximum pixel count must be positive")
+	ErrNoImageTypes     = errors.New("At least one image type must be allowed")
+)
+
+// The amount of bytes read by http.DetectContentType.
+const sniffLen = 512
+
+type Guard struct {
+	maxBytes  int64
+	maxPixels int
+	types     []string
+}
+
+type CfgFunc func(g *Guard) error
+
+func NewGuard(cfgs ...CfgFunc) (*Guard, error) {
+	g := &Guard{
+		maxBytes:  DEFAULT_MAX_BYTES,
+		maxPixels: DEFAULT_MAX_PIXELS,
+		types:     slices.Clone(DefaultImageTypes),
+	}
+
+	for _, cfg := range cfgs {
+		if err := cfg(g); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// Limits the size of request bodies carrying uploads to the given amount of
+// bytes.
+func WithMaxBytes(n int64) CfgFunc {
+	return func(g *Guard) error {
+		if n <= 0 {
+			return ErrInvalidMaxBytes
+		}
+
+		g.maxBytes = n
+		return nil
+	}
+}
+
+// Limits the width times the height of uploaded images to the given amount of
+// pixels.
+func WithMaxPixels(n int) CfgFunc {
+	return func(g *Guard) error {
+		if n <= 0 {
+			return ErrInvalidMaxPixels
+		}
+
+		g.maxPixels = n
+		return nil
+	}
+}
+
+// Only accepts images of the given content types, such as "image/png".
+func WithImageTypes(types ...string) CfgFunc {
+	return func(g *Guard) error {
+		if len(types) == 0 {
+			return ErrNoImageTypes
+		}
+
+		g.types = slices.Clone(types)
+		return nil
+	}
+}
+
+func (g Guard) MaxBytes() int64 {
+	return g.maxBytes
+}
+
+func (g Guard) MaxPixels() int {
+	return g.maxPixels
+}
+
+func (g Guard) ImageTypes() []string {
+	return slices.Clone(g.types)
+}
+
+// Returns the image uploaded as the given form field of the request, whose
+// body is limited to the maximum upload size. The image is checked as by
+// Check.
+func (g Guard) FormImage(w http.ResponseWriter, r *http.Request, field string) (multipart.File, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, g.maxBytes)
+
+	file, _, err := r.FormFile(field)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, ErrTooLarge
+		}
+
+		return nil, err
+	}
+
+	if err := g.Check(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Checks the sniffed content type and the dimensions of the given image,
+// without decoding its pixels. The image is read from the start afterwards.
+func (g Guard) Check(r io.ReadSeeker) error {
+	head := make([]byte, sniffLen)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if !slices.Contains(g.types, contentType) {
+		return ErrUnsupportedType
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return ErrInvalidImage
+	}
+
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return ErrInvalidImage
+	}
+
+	if int64(cfg.Width)*int64(cfg.Height) > int64(g.maxPixels) {
+		return ErrTooManyPixels
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return nil
+}
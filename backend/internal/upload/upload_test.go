@@ -0,0 +1,201 @@
+package upload_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattismoel/konnekt/internal/upload"
+)
+
+func TestCheck(t *testing.T) {
+	type test struct {
+		data    []byte
+		cfgs    []upload.CfgFunc
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"JPEG":                {data: encodeJPEG(t, 40, 30)},
+		"PNG":                 {data: encodePNG(t, 40, 30)},
+		"GIF":                 {data: encodeGIF(t, 40, 30), wantErr: upload.ErrUnsupportedType},
+		"Text":                {data: []byte("hello"), wantErr: upload.ErrUnsupportedType},
+		"Empty":               {data: []byte{}, wantErr: upload.ErrUnsupportedType},
+		"Truncated PNG":       {data: encodePNG(t, 40, 30)[:20], wantErr: upload.ErrInvalidImage},
+		"Pixel ceiling":       {data: encodePNG(t, 40, 30), cfgs: []upload.CfgFunc{upload.WithMaxPixels(1200)}},
+		"Above pixel ceiling": {data: encodePNG(t, 40, 30), cfgs: []upload.CfgFunc{upload.WithMaxPixels(1199)}, wantErr: upload.ErrTooManyPixels},
+		"Decompression bomb":  {data: withPNGSize(t, encodePNG(t, 1, 1), 100_000, 100_000), wantErr: upload.ErrTooManyPixels},
+		"Disallowed type":     {data: encodePNG(t, 40, 30), cfgs: []upload.CfgFunc{upload.WithImageTypes("image/jpeg")}, wantErr: upload.ErrUnsupportedType},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			guard, err := upload.NewGuard(tt.cfgs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := bytes.NewReader(tt.data)
+
+			err = guard.Check(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			// The image must be readable from the start after checking.
+			rest, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(rest, tt.data) {
+				t.Fatal("got partially read image, want image read from the start")
+			}
+		})
+	}
+}
+
+func TestFormImage(t *testing.T) {
+	type test struct {
+		data    []byte
+		field   string
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"Valid image":     {data: encodePNG(t, 40, 30), field: "file"},
+		"Body too large":  {data: bytes.Repeat([]byte{0}, 4096), field: "file", wantErr: upload.ErrTooLarge},
+		"Missing field":   {data: encodePNG(t, 40, 30), field: "image", wantErr: http.ErrMissingFile},
+		"Unsupported":     {data: []byte("GIF89a"), field: "file", wantErr: upload.ErrUnsupportedType},
+		"Too many pixels": {data: withPNGSize(t, encodePNG(t, 1, 1), 100_000, 100_000), field: "file", wantErr: upload.ErrTooManyPixels},
+	}
+
+	guard, err := upload.NewGuard(upload.WithMaxBytes(2048))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var body bytes.Buffer
+
+			mw := multipart.NewWriter(&body)
+
+			fw, err := mw.CreateFormFile("file", "image")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := fw.Write(tt.data); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := mw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/", &body)
+			r.Header.Set("Content-Type", mw.FormDataContentType())
+
+			file, err := guard.FormImage(httptest.NewRecorder(), r, tt.field)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			if file != nil {
+				file.Close()
+			}
+		})
+	}
+}
+
+func TestNewGuard(t *testing.T) {
+	type test struct {
+		cfgs    []upload.CfgFunc
+		wantErr error
+	}
+
+	tests := map[string]test{
+		"No options":      {},
+		"Valid options":   {cfgs: []upload.CfgFunc{upload.WithMaxBytes(1024), upload.WithMaxPixels(1024), upload.WithImageTypes("image/png")}},
+		"Zero max bytes":  {cfgs: []upload.CfgFunc{upload.WithMaxBytes(0)}, wantErr: upload.ErrInvalidMaxBytes},
+		"Negative pixels": {cfgs: []upload.CfgFunc{upload.WithMaxPixels(-1)}, wantErr: upload.ErrInvalidMaxPixels},
+		"No image types":  {cfgs: []upload.CfgFunc{upload.WithImageTypes()}, wantErr: upload.ErrNoImageTypes},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := upload.NewGuard(tt.cfgs...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func encodeJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func encodeGIF(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// Returns the given PNG image with the dimensions of its header replaced,
+// leaving its pixel data as is. Such images are small, but would take
+// excessive memory to decode.
+func withPNGSize(t *testing.T, data []byte, width, height uint32) []byte {
+	t.Helper()
+
+	// The header chunk follows the 8 byte signature, and holds its length,
+	// type, width and height, each of 4 bytes.
+	if len(data) < 33 || string(data[12:16]) != "IHDR" {
+		t.Fatal("got invalid PNG, want header chunk")
+	}
+
+	data = bytes.Clone(data)
+	binary.BigEndian.PutUint32(data[16:20], width)
+	binary.BigEndian.PutUint32(data[20:24], height)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	return data
+}
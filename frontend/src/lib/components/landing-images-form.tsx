@@ -8,6 +8,7 @@ import { useQueryClient } from "@tanstack/react-query"
 import ImageList from "./image-list"
 import { FaUpload } from "react-icons/fa6"
 import { useAuth } from "../context/auth"
+import Input from "./ui/input"
 
 type Props = {
 	images: Image[]
@@ -21,6 +22,7 @@ const LandingImagesForm = ({ images }: Props) => {
 	const isEditable = hasPermissions(["edit:content", "delete:content"])
 
 	const [file, setFile] = useState<File | null>(null)
+	const [altText, setAltText] = useState("")
 
 	const changeFile = (files: FileList | null) => {
 		if (!files) return
@@ -35,7 +37,7 @@ const LandingImagesForm = ({ images }: Props) => {
 		if (!file) return
 
 		try {
-			await uploadLandingImage(file)
+			await uploadLandingImage(file, altText)
 			await queryClient.invalidateQueries({
 				queryKey: ["landing", "images"]
 			})
@@ -75,6 +77,7 @@ const LandingImagesForm = ({ images }: Props) => {
 			{isEditable && (
 				<div className="flex justify-between gap-4 mb-16">
 					<FilePicker onChange={changeFile} />
+					<Input value={altText} onChange={e => setAltText(e.target.value)} placeholder="Billedbeskrivelse (alt-tekst)" className="w-full" />
 					<Button type="button" variant="secondary" onClick={handleUpload}><FaUpload />Upload</Button>
 				</div>
 			)}
@@ -41,11 +41,11 @@ const MemberInfo = ({ member, includedTeams }: MemberInfoProps) => {
 	return (
 		<div className="group bg-background flex flex-col border border-zinc-800 hover:border-zinc-700 rounded-sm overflow-hidden hover:bg-zinc-900 transition-colors">
 			<div className="overflow-hidden">
-				<img aria-disabled alt="Background blur" src={member.profilePictureUrl} loading="lazy" className="brightness-75 h-48 w-full object-cover scale-100 group-hover:scale-100 group-hover:brightness-100 transition-[scale,filter] duration-500" />
+				<img aria-disabled alt="Background blur" src={member.profilePicture?.url} loading="lazy" className="brightness-75 h-48 w-full object-cover scale-100 group-hover:scale-100 group-hover:brightness-100 transition-[scale,filter] duration-500" />
 			</div>
 			<div className="relative p-4 @lg:p-6 flex flex-col gap-2 cursor-default w-full object-cover">
 				<img
-					src={member.profilePictureUrl}
+					src={member.profilePicture?.url}
 					alt={`${member.firstName} ${member.lastName}`}
 					loading="lazy"
 					className="pointer-events-none absolute h-full w-full blur-3xl opacity-0 group-hover:opacity-25 transition-opacity duration-1000"
@@ -1,6 +1,7 @@
 import { z } from "zod";
 import { genreSchema } from "./genre";
-import { APIError, apiErrorSchema, idSchema, requestAndParse, type ID } from "@/lib/api";
+import { APIError, idSchema, requestAndParse, type ID } from "@/lib/api";
+import { imageSchema, uploadImage, type Image } from "@/lib/image";
 import { createUrl, isValidUrl, type Query } from "@/lib/url";
 import { createListResult, type ListResult } from "@/lib/query";
 
@@ -19,7 +20,7 @@ import { FaTiktok } from "react-icons/fa6";
 export const artistSchema = z.object({
 	id: idSchema,
 	name: z.string(),
-	imageUrl: z.string().optional().or(z.string().url().optional()),
+	image: imageSchema,
 	description: z.string(),
 	genres: genreSchema.array(),
 	socials: z.string().url().array(),
@@ -53,31 +54,32 @@ export const artistForm = z.object({
 		.nonempty()
 		.url({ message: "URL skal være gyldigt" })
 		.array(),
-	image: z.instanceof(File).optional()
+	image: z.instanceof(File).optional(),
+	imageAltText: z.string().optional(),
 })
 
 export type ArtistFormValues = z.infer<typeof artistForm>
 
 const createArtistSchema = artistForm
-	.omit({ image: true })
-	.extend({ imageUrl: z.string().url() })
+	.omit({ image: true, imageAltText: true })
+	.extend({ image: imageSchema })
 
 const editArtistSchema = artistForm
-	.omit({ image: true })
-	.extend({ imageUrl: z.string().url().optional() })
+	.omit({ image: true, imageAltText: true })
+	.extend({ image: imageSchema.optional() })
 
 export const createArtist = async (form: ArtistFormValues) => {
-	let { image, ...rest } = form
+	let { image, imageAltText, ...rest } = form
 	if (!image) throw new APIError(400, "Could not upload artist image", "Image file not present")
 
-	const imageUrl = await uploadArtistImage(image)
+	const uploadedImage = await uploadArtistImage(image)
 
 	const artist = requestAndParse(
 		createUrl("/api/artists"),
 		artistSchema,
 		"Could not create artist",
 
-		{ bodySchema: createArtistSchema, body: { ...rest, imageUrl } },
+		{ bodySchema: createArtistSchema, body: { ...rest, image: { ...uploadedImage, altText: imageAltText } } },
 		"POST",
 	)
 
@@ -85,23 +87,28 @@ export const createArtist = async (form: ArtistFormValues) => {
 };
 
 /**
- * @description Updates an artist.
+ * @description Updates an artist. The alt text of the form describes either the
+ * newly uploaded image, or the current image of the artist.
  * @param {ID} artistId - The artist to be updated's ID.
  * @param form - The form data to update the artist with.
+ * @param {Image} currentImage - The current image of the artist.
  */
-export const updateArtist = async (artistId: ID, form: ArtistFormValues): Promise<Artist> => {
+export const updateArtist = async (artistId: ID, form: ArtistFormValues, currentImage?: Image): Promise<Artist> => {
 	const { data, success, error } = artistForm.safeParse(form)
 	if (!success) throw error
 
-	const { image, ...rest } = data;
+	const { image, imageAltText, ...rest } = data;
 
-	const imageUrl = image ? await uploadArtistImage(image) : undefined
+	const uploadedImage = image ? await uploadArtistImage(image) : currentImage
 
 	const artist = requestAndParse(
 		createUrl(`/api/artists/${artistId}`),
 		artistSchema,
 		"Could not update artist",
-		{ bodySchema: editArtistSchema, body: { ...rest, imageUrl } },
+		{
+			bodySchema: editArtistSchema,
+			body: { ...rest, image: uploadedImage && { ...uploadedImage, altText: imageAltText } }
+		},
 		"PUT"
 	)
 
@@ -111,27 +118,13 @@ export const updateArtist = async (artistId: ID, form: ArtistFormValues): Promis
 /**
  * @description Uploads the artist image for the artist specified by its artistId.
  * @param {File} file - The image file to be used as the artist image.
- * @returns {string} The URL of the artist image.
+ * @returns {Image} The uploaded artist image along with its variants.
  */
-export const uploadArtistImage = async (file: File, init?: RequestInit): Promise<string> => {
-	const formData = new FormData();
-	formData.append('image', file);
-
-	const res = await fetch("/api/artists/image", {
+export const uploadArtistImage = async (file: File, init?: RequestInit): Promise<Image> => {
+	return uploadImage("/api/artists/image", file, "image", "Could not update artist image", {
 		...init,
-		method: 'PUT',
-		credentials: 'include',
-		body: formData
-	});
-
-	if (!res.ok) {
-		const err = apiErrorSchema.parse(await res.json())
-		throw new APIError(res.status, "Could not update artist image", err.message)
-	}
-
-	const url = await res.text()
-
-	return url
+		method: "PUT",
+	})
 };
 
 
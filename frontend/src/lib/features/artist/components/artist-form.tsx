@@ -57,13 +57,15 @@ const ArtistForm = ({ artist, genres }: Props) => {
 		disabled: !isEditable,
 		defaultValues: {
 			...artist,
+			image: undefined,
+			imageAltText: artist?.image.altText,
 			genreIds: artist?.genres.map(genre => genre.id) || [],
 			socials: artist?.socials.map(s => ({ value: s }))
 		},
 		resolver: zodResolver(internalArtistFormSchema),
 	})
 
-	const { control, formState: { errors }, handleSubmit } = methods
+	const { control, register, formState: { errors }, handleSubmit } = methods
 	const fieldArrayMethods = useFieldArray({ control, name: "socials" })
 
 	const queryClient = useQueryClient()
@@ -83,7 +85,7 @@ const ArtistForm = ({ artist, genres }: Props) => {
 			}
 
 			artist
-				? await updateArtist(artist.id, data)
+				? await updateArtist(artist.id, data, artist.image)
 				: await createArtist(data)
 
 			await queryClient.invalidateQueries({ queryKey: ["artists"] })
@@ -101,7 +103,7 @@ const ArtistForm = ({ artist, genres }: Props) => {
 							<FormField error={errors.image}>
 								<ImagePreview
 									{...field}
-									src={artist?.imageUrl}
+									src={artist?.image.url}
 									accept="image/jpeg,image/png"
 								// onChange={(file) => setValue("image", file)}
 								/>
@@ -109,6 +111,10 @@ const ArtistForm = ({ artist, genres }: Props) => {
 						)}
 					/>
 
+					<FormField error={errors.imageAltText}>
+						<Input placeholder="Billedbeskrivelse (alt-tekst)" {...register("imageAltText")} />
+					</FormField>
+
 					<GeneralSection />
 					<SpotifySection />
 					<GenreSection />
@@ -2,6 +2,7 @@ import { requestAndParse } from "@/lib/api";
 import { createUrl } from "@/lib/url";
 import { z } from "zod";
 import { memberSchema, uploadMemberProfilePicture } from "./member";
+import { imageSchema } from "@/lib/image";
 import { env } from "../../env";
 
 const MINIMUM_PASSWORD_LENGTH = 8
@@ -69,13 +70,13 @@ export const logOut = async () => {
 }
 
 const registerSchema = baseRegisterForm.extend({
-	profilePictureUrl: z.string().url()
+	profilePicture: imageSchema,
 })
 
 export const register = async (form: RegisterFormValues) => {
 	const { profilePictureFile } = form;
 
-	const profilePictureUrl = await uploadMemberProfilePicture(profilePictureFile)
+	const profilePicture = await uploadMemberProfilePicture(profilePictureFile)
 
 	const member = await requestAndParse(
 		createUrl(`/api/auth/register`),
@@ -84,7 +85,7 @@ export const register = async (form: RegisterFormValues) => {
 		{
 			body: {
 				...form,
-				profilePictureUrl,
+				profilePicture,
 			}, bodySchema: registerSchema
 		},
 		"POST",
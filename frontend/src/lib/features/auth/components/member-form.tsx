@@ -90,7 +90,7 @@ const MemberForm = ({ member, memberTeams, teams }: Props) => {
 							name='image'
 							render={({ field }) => (
 								<FormField error={errors.image} className='justify-center'>
-									<ProfilePictureSelector {...field} src={member.profilePictureUrl} />
+									<ProfilePictureSelector {...field} src={member.profilePicture?.url} />
 								</FormField>
 							)}
 						/>
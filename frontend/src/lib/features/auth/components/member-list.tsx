@@ -79,7 +79,7 @@ const MemberEntry = ({ member }: MemberEntryProps) => {
 		<List.Entry className="relative">
 			<List.Entry.LinkSection to="/admin/members/$memberId" params={{ memberId: member.id.toString() }} className="flex-row items-center gap-4">
 				<img
-					src={member.profilePictureUrl || Avatar}
+					src={member.profilePicture?.url || Avatar}
 					alt="Profil"
 					className="h-8 w-8 rounded-full object-cover"
 				/>
@@ -159,7 +159,7 @@ const ApprovalEntry = ({ member }: ApprovalEntryProps) => {
 			<List.Entry.LinkSection to="/admin/members/$memberId" params={{ memberId: member.id.toString() }}>
 				<div className="flex flex-1 items-center gap-4">
 					<img
-						src={member.profilePictureUrl || Avatar}
+						src={member.profilePicture?.url || Avatar}
 						alt="Profil"
 						className="h-8 w-8 rounded-full object-cover"
 					/>
@@ -1,4 +1,5 @@
-import { APIError, apiErrorSchema, idSchema, requestAndParse, type ID } from "@/lib/api"
+import { idSchema, requestAndParse, type ID } from "@/lib/api"
+import { imageSchema, uploadImage, type Image } from "@/lib/image"
 import { createListResult } from "@/lib/query"
 import { createUrl, type Query } from "@/lib/url"
 import { z } from "zod"
@@ -10,10 +11,7 @@ export const memberSchema = z.object({
 	firstName: z.string(),
 	lastName: z.string(),
 	teams: teamSchema.array().min(1),
-	profilePictureUrl: z
-		.string()
-		.url()
-		.optional(),
+	profilePicture: imageSchema.optional(),
 
 	active: z.boolean(),
 })
@@ -42,7 +40,7 @@ export type MemberFormValues = z.infer<typeof memberForm>
 
 const editMemberSchema = memberForm
 	.omit({ image: true })
-	.extend({ profilePictureUrl: z.string().url().optional() })
+	.extend({ profilePicture: imageSchema.optional() })
 
 export const memberSession = async () => {
 	const member = await requestAndParse(
@@ -100,36 +98,19 @@ export const editMember = async (memberId: ID, form: MemberFormValues) => {
 
 	const { image, ...rest } = data;
 
-	const profilePictureUrl = image ? await uploadMemberProfilePicture(image) : undefined
+	const profilePicture = image ? await uploadMemberProfilePicture(image) : undefined
 
 	const member = requestAndParse(
 		createUrl(`/api/members/${memberId}`),
 		memberSchema,
 		"Could not update artist",
-		{ bodySchema: editMemberSchema, body: { ...rest, profilePictureUrl } },
+		{ bodySchema: editMemberSchema, body: { ...rest, profilePicture } },
 		"PUT"
 	)
 
 	return member
 }
 
-export const uploadMemberProfilePicture = async (file: File): Promise<string> => {
-	const formData = new FormData()
-
-	formData.append("file", file)
-
-	const res = await fetch(`/api/members/picture`, {
-		body: formData,
-		method: "POST",
-		credentials: "include",
-	})
-
-	if (!res.ok) {
-		const err = apiErrorSchema.parse(await res.json())
-		throw new APIError(res.status, "Could not upload member profile picture", err.message)
-	}
-
-	const url = await res.text()
-
-	return url
+export const uploadMemberProfilePicture = async (file: File): Promise<Image> => {
+	return uploadImage("/api/members/picture", file, "file", "Could not upload member profile picture")
 }
@@ -18,10 +18,11 @@ export const landingImages = async () => {
 	return srcs
 }
 
-export const uploadLandingImage = async (file: File): Promise<Image> => {
+export const uploadLandingImage = async (file: File, altText: string): Promise<Image> => {
 	const formData = new FormData()
 
 	formData.set("file", file)
+	formData.set("altText", altText)
 
 	const res = await fetch("/api/content/landing-images", {
 		body: formData,
@@ -39,7 +39,7 @@ const EventCard = ({ event }: Props) => {
 				}}
 			>
 				<img
-					src={event.imageUrl}
+					src={event.image.url}
 					alt={event.title}
 					loading="lazy"
 					className="h-full w-full brightness-75 scale-110 object-cover transition-all duration-200 group-hover:scale-100 group-hover:brightness-100 md:brightness-90"
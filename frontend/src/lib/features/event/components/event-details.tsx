@@ -34,7 +34,7 @@ const EventDetails = ({ event, active, prefix }: Props) => {
 	return (
 		<div className="relative isolate flex h-[calc((100svh/5)*4)] items-end overflow-hidden pb-8 sm:pb-16">
 			<img
-				src={event.imageUrl}
+				src={event.image.url}
 				alt="Event cover"
 				className="absolute top-0 left-0 -z-10 h-full w-full object-cover brightness-50"
 			/>
@@ -3,7 +3,7 @@ import { zodResolver } from "@hookform/resolvers/zod"
 import { FaArrowsRotate, FaPlus, FaUpload } from "react-icons/fa6"
 import { addMinutes, roundToNearestHours } from "date-fns"
 import ConcertList from "./concert-list"
-import { createEvent, eventForm, updateEvent, type Event, type EventFormValues } from "@/lib/features/event/event"
+import { createEvent, eventForm, eventStatusLabels, eventStatusSchema, setEventStatus, statusRequiresNotice, updateEvent, type Event, type EventFormValues } from "@/lib/features/event/event"
 import type { Venue } from "@/lib/features/event/venue"
 import type { Artist } from "@/lib/features/artist/artist"
 import { createContext, useContext } from "react"
@@ -65,21 +65,23 @@ const EventForm = ({ event, venues, artists }: Props) => {
 				description: event.description,
 				venueId: event?.venue.id,
 				image: undefined,
+				imageAltText: event.image.altText,
 				ticketUrl: event.ticketUrl,
 				concerts: event?.concerts.map(c => ({
 					from: c.from,
 					to: c.to,
 					artistID: c.artist.id,
 				})),
-				isPublic: event.isPublic
+				status: event.status,
+				statusNotice: event.statusNotice,
 			} : {
-				isPublic: true,
+				status: "scheduled",
 				venueId: venues.find(v => v.name === "Posten")?.id
 			},
 		resolver: zodResolver(eventForm),
 	})
 
-	const { control, formState: { errors }, setValue, getValues, handleSubmit } = methods;
+	const { control, formState: { errors }, register, setValue, getValues, handleSubmit } = methods;
 
 	const fieldArrayMethods = useFieldArray({ control, name: "concerts" })
 	const { fields, remove, append } = fieldArrayMethods
@@ -103,7 +105,16 @@ const EventForm = ({ event, venues, artists }: Props) => {
 
 	const onSubmit = createSubmitHandler({
 		action: async (form: EventFormValues) => {
-			event ? await updateEvent(form, event.id) : await createEvent(form)
+			if (!event) {
+				await createEvent(form)
+			} else {
+				await updateEvent(form, event.id, event.image)
+
+				if (form.status !== event.status) {
+					await setEventStatus(event.id, form.status, form.statusNotice)
+				}
+			}
+
 			await queryClient.invalidateQueries({ queryKey: ["events"] })
 		},
 		successMessage: event ? "Event opdateret" : "Event skabt",
@@ -116,7 +127,11 @@ const EventForm = ({ event, venues, artists }: Props) => {
 			<EventFormContext.Provider value={{ ...methods, ...fieldArrayMethods, artists, venues, event, onAddConcert, onDeleteConcert }}>
 				<form onSubmit={handleSubmit(onSubmit)} className="flex flex-col gap-16 @container">
 					<FormField error={errors.image}>
-						<ImagePreview disabled={!isEditable} src={event?.imageUrl} accept="image/jpeg,image/png" onChange={file => setValue("image", file)} />
+						<ImagePreview disabled={!isEditable} src={event?.image.url} accept="image/jpeg,image/png" onChange={file => setValue("image", file)} />
+					</FormField>
+
+					<FormField error={errors.imageAltText}>
+						<Input {...register("imageAltText")} placeholder="Billedbeskrivelse (alt-tekst)" />
 					</FormField>
 
 					<GeneralSection />
@@ -143,9 +158,16 @@ const EventForm = ({ event, venues, artists }: Props) => {
 }
 
 const GeneralSection = () => {
-	const { register, formState: { errors, disabled } } = useEventFormContext()
+	const { event, register, watch, formState: { errors, disabled } } = useEventFormContext()
 	const isEditable = !disabled
 
+	// New events may only be created as drafts or scheduled.
+	const statuses = event
+		? eventStatusSchema.options
+		: (["draft", "scheduled"] as const)
+
+	const status = watch("status")
+
 	return (
 		<section>
 
@@ -163,12 +185,19 @@ const GeneralSection = () => {
 					<VenueSelector />
 				</div>
 				{isEditable && (
-					<FormField className="w-min">
-						<label className="flex gap-2 items-center">
-							<input type="checkbox" {...register("isPublic")} />
-							Offentlig
-						</label>
-					</FormField>
+					<div className="flex flex-col @xl:flex-row gap-4">
+						<FormField error={errors.status}>
+							<Selector {...register("status")} placeholder="Vælg status..." className="w-full">
+								{statuses.map(s => <option key={s} value={s}>{eventStatusLabels[s]}</option>)}
+							</Selector>
+						</FormField>
+
+						{statusRequiresNotice(status) && (
+							<FormField error={errors.statusNotice}>
+								<Input {...register("statusNotice")} placeholder="Besked til publikum" className="w-full" />
+							</FormField>
+						)}
+					</div>
 				)}
 			</div>
 		</section>
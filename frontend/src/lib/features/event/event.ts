@@ -1,20 +1,41 @@
 import { z } from "zod";
 import { concertForm, concertSchema } from "./concert";
 import { venueSchema } from "./venue";
-import { APIError, apiErrorSchema, idSchema, requestAndParse, type ID } from "@/lib/api";
+import { APIError, idSchema, requestAndParse, type ID } from "@/lib/api";
+import { imageSchema, uploadImage, type Image } from "@/lib/image";
 import { createUrl, type Query } from "@/lib/url";
 import { createListResult, type ListResult } from "@/lib/query";
 import { startOfToday } from "date-fns";
 
+export const eventStatusSchema = z.enum(["draft", "scheduled", "postponed", "cancelled", "archived"])
+
+export type EventStatus = z.infer<typeof eventStatusSchema>
+
+export const eventStatusLabels: Record<EventStatus, string> = {
+	draft: "Kladde",
+	scheduled: "Planlagt",
+	postponed: "Udskudt",
+	cancelled: "Aflyst",
+	archived: "Arkiveret",
+}
+
+/**
+ * @description Returns whether or not the status must be accompanied by a
+ * notice to the audience.
+ */
+export const statusRequiresNotice = (status: EventStatus): boolean =>
+	status === "postponed" || status === "cancelled"
+
 export const eventSchema = z.object({
 	id: idSchema,
 	title: z.string().nonempty(),
 	description: z.string().nonempty(),
 	ticketUrl: z.string().url(),
-	imageUrl: z.string().optional().or(z.string().url().optional()),
+	image: imageSchema,
 	concerts: concertSchema.array(),
 	venue: venueSchema,
-	isPublic: z.boolean(),
+	status: eventStatusSchema,
+	statusNotice: z.string().optional(),
 })
 
 export type Event = z.infer<typeof eventSchema>
@@ -26,45 +47,53 @@ export const eventForm = z.object({
 	venueId: idSchema,
 	concerts: concertForm.array().min(1, { message: "Et event skal have mindst én koncert" }),
 	image: z.instanceof(File).optional(),
-	isPublic: z.boolean(),
+	imageAltText: z.string().optional(),
+	status: eventStatusSchema,
+	statusNotice: z.string().optional(),
 });
 
 export type EventFormValues = z.infer<typeof eventForm>
 
 const createEventSchema = eventForm
-	.omit({ image: true })
-	.extend({ imageUrl: z.string().url() })
+	.omit({ image: true, imageAltText: true, statusNotice: true })
+	.extend({ image: imageSchema })
 
 export const createEvent = async (form: EventFormValues): Promise<Event> => {
 	const { data: formData, error: formError } = eventForm.safeParse(form)
 	if (formError) throw formError
 
-	const { image, ...rest } = formData
+	const { image, imageAltText, statusNotice, ...rest } = formData
 	if (!image) throw new APIError(400, "Could not create event", "Cover image must be set")
 
-	const imageUrl = await uploadEventCoverImage(image)
+	const uploadedImage = await uploadEventCoverImage(image)
 
 	const event = await requestAndParse(
 		createUrl(`/api/events`),
 		eventSchema,
 		"Could not create event",
-		{ body: { ...rest, imageUrl }, bodySchema: createEventSchema },
+		{ body: { ...rest, image: { ...uploadedImage, altText: imageAltText } }, bodySchema: createEventSchema },
 		"POST",
 	)
 
 	return event
 }
 
+// The status of an event is changed separately with setEventStatus.
 const updateEventSchema = eventForm
-	.omit({ image: true })
-	.extend({ imageUrl: z.string().url().optional() })
+	.omit({ image: true, imageAltText: true, status: true, statusNotice: true })
+	.extend({ image: imageSchema.optional() })
 
-export const updateEvent = async (form: EventFormValues, eventId: ID): Promise<Event> => {
+/**
+ * @description Updates an event. The alt text of the form describes either the
+ * newly uploaded cover image, or the current cover image of the event.
+ */
+export const updateEvent = async (form: EventFormValues, eventId: ID, currentImage?: Image): Promise<Event> => {
 	const { data, success, error } = eventForm.safeParse(form)
 	if (!success) throw error
 
-	const imageUrl = data.image ? await uploadEventCoverImage(data.image) : undefined
-	const { image, ...rest } = data;
+	const { image, imageAltText, status, statusNotice, ...rest } = data;
+
+	const uploadedImage = image ? await uploadEventCoverImage(image) : currentImage
 
 	const event = await requestAndParse(
 		createUrl(`/api/events/${eventId}`),
@@ -72,7 +101,7 @@ export const updateEvent = async (form: EventFormValues, eventId: ID): Promise<E
 		"Could not update event",
 		{
 			bodySchema: updateEventSchema,
-			body: { ...rest, imageUrl }
+			body: { ...rest, image: uploadedImage && { ...uploadedImage, altText: imageAltText } }
 		},
 		"PUT"
 	)
@@ -80,26 +109,28 @@ export const updateEvent = async (form: EventFormValues, eventId: ID): Promise<E
 	return event
 }
 
-export const uploadEventCoverImage = async (file: File, init?: RequestInit): Promise<string> => {
-	const formData = new FormData()
-
-	formData.append("image", file)
-
-	const res = await fetch(`/api/events/image`, {
-		...init,
-		method: "POST",
-		credentials: "include",
-		body: formData,
-	})
+const setEventStatusSchema = z.object({
+	status: eventStatusSchema,
+	notice: z.string().optional(),
+})
 
-	if (!res.ok) {
-		const err = apiErrorSchema.parse(await res.json())
-		throw new APIError(res.status, "Could not upload event cover image", err.message)
-	}
+export const setEventStatus = async (eventId: ID, status: EventStatus, notice?: string): Promise<Event> => {
+	const event = await requestAndParse(
+		createUrl(`/api/events/${eventId}/status`),
+		eventSchema,
+		"Could not change event status",
+		{
+			bodySchema: setEventStatusSchema,
+			body: { status, notice },
+		},
+		"POST"
+	)
 
-	const url = await res.text()
+	return event
+}
 
-	return url
+export const uploadEventCoverImage = async (file: File, init?: RequestInit): Promise<Image> => {
+	return uploadImage("/api/events/image", file, "image", "Could not upload event cover image", init)
 }
 
 export const listEvents = async (query: Query,): Promise<ListResult<Event>> => {
@@ -0,0 +1,53 @@
+import { z } from "zod";
+import { APIError, apiErrorSchema } from "./api";
+
+export const imageVariantSchema = z.object({
+	width: z.number().int().positive(),
+	url: z.string().url(),
+})
+
+/**
+ * @description An uploaded image along with its responsive variants, ordered
+ * by ascending width. Any further fields, such as the framing of the image, are
+ * kept as is, so that an uploaded image may be sent back unchanged.
+ */
+export const imageSchema = z.object({
+	url: z.string(),
+	variants: imageVariantSchema.array().nullable(),
+	blurHash: z.string().optional(),
+	color: z.string().optional(),
+	altText: z.string().optional(),
+	credit: z.string().optional(),
+}).passthrough()
+
+export type Image = z.infer<typeof imageSchema>
+
+/**
+ * @description Uploads an image file as the given form field, returning the
+ * uploaded image.
+ */
+export const uploadImage = async (
+	url: string,
+	file: File,
+	field: string,
+	errorMsg: string,
+	init?: RequestInit,
+): Promise<Image> => {
+	const formData = new FormData()
+
+	formData.append(field, file)
+
+	const res = await fetch(url, {
+		method: "POST",
+		...init,
+		credentials: "include",
+		body: formData,
+	})
+
+	if (!res.ok) {
+		const err = apiErrorSchema.parse(await res.json())
+		throw new APIError(res.status, errorMsg, err.message)
+	}
+
+	return imageSchema.parse(await res.json())
+}
@@ -43,7 +43,7 @@ function RouteComponent() {
         <div className="grid min-h-svh grid-cols-1 grid-rows-[85svh_1fr]">
           <div className="px-auto relative isolate flex items-end py-16">
             <img
-              src={artist.imageUrl}
+              src={artist.image.url}
               alt="Cover af {artist.name}"
               className="absolute top-0 left-0 h-full w-full object-cover"
             />
@@ -84,7 +84,7 @@ function RouteComponent() {
           {artists.map(artist => (
             <img
               key={artist.id}
-              src={artist.imageUrl}
+              src={artist.image.url}
               alt={artist.name}
               className={cn("pointer-events-none absolute top-0 left-0 -z-10 h-full w-full object-cover opacity-0 brightness-50 transition-all duration-1000", {
                 "opacity-100 scale-105": selected?.id === artist.id
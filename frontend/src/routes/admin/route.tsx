@@ -53,7 +53,7 @@ const AuthLayout = () => {
         </Navbar.RouteList>
       </Navbar.Content>
       <button className="group" onClick={() => setShowUserContext(true)}>
-        <img src={member.profilePictureUrl} alt="Profil" className="h-8 w-8 rounded-full object-cover outline outline-zinc-700 group-hover:outline-2" />
+        <img src={member.profilePicture?.url} alt="Profil" className="h-8 w-8 rounded-full object-cover outline outline-zinc-700 group-hover:outline-2" />
       </button>
       <ContextMenu show={showUserContext} onClose={() => setShowUserContext(false)}>
         <ContextMenu.LinkEntry